@@ -7,7 +7,6 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"os/exec"
 	"runtime"
 	"strconv"
@@ -32,6 +31,8 @@ type authFlow struct {
 	// OpenID Discovery endpoint: see https://openid.net/specs/openid-connect-discovery-1_0.html
 	oidcDiscoveryEndpoint string
 
+	// disableBrowserOpen logs in from another device, such as on a machine without a browser, by
+	// printing the URL and code to enter there instead of opening a browser.
 	disableBrowserOpen bool
 
 	httpClient *http.Client
@@ -56,7 +57,18 @@ const (
 	tokenTypeUserOAuthToken = "user-oauth-token"
 )
 
-var errAuthorizationPending = errors.New("authorization pending on user")
+// LoginFlagDevice logs in from another device instead of opening a browser.
+const LoginFlagDevice = "device"
+
+var (
+	errAuthorizationPending = errors.New("authorization pending on user")
+	// errSlowDown is returned when the token endpoint is being polled too often.
+	errSlowDown = errors.New("polling for authorization too often")
+)
+
+// slowDownInterval is how much longer to wait between polls each time the token endpoint asks the CLI to
+// slow down, as the device authorization grant (RFC 8628) requires.
+const slowDownInterval = 5 * time.Second
 
 type openIDDiscoveryResponse struct {
 	TokenEndPoint               string   `json:"token_endpoint"`
@@ -113,6 +125,12 @@ func LoginAction(c *cli.Context) error {
 			t.ExpiresAt.Format("Mon Jan 2 15:04:05 MST 2006"))
 	}
 
+	if name := c.String(LoginFlagCredentialStore); name != "" && name != credentialStoreName(client.credentials) {
+		if err := client.switchCredentialStore(name); err != nil {
+			return err
+		}
+	}
+
 	if client.conf.Auth != nil && !client.conf.Auth.isExpired() {
 		loggedInMessage(client.conf.Auth, true)
 		return nil
@@ -126,15 +144,14 @@ func LoginAction(c *cli.Context) error {
 			return err
 		}
 	} else {
+		client.authFlow.disableBrowserOpen = c.Bool(LoginFlagDevice)
 		t, err = client.authFlow.login(client.c.Context)
 		if err != nil {
 			return err
 		}
 	}
 
-	// write token to config.
-	client.conf.Auth = t
-	if err := storeConfigToCache(client.conf); err != nil {
+	if err := client.saveToken(t); err != nil {
 		return err
 	}
 
@@ -187,34 +204,115 @@ func WhoAmIAction(c *cli.Context) error {
 		return nil
 	}
 	fmt.Fprintf(c.App.Writer, "%s\n", auth.User.Email)
+	if c.Bool("token-info") {
+		info, err := accessTokenInfoFromToken(auth.AccessToken)
+		if err != nil {
+			return err
+		}
+		printAccessTokenInfo(c.App.Writer, info)
+	}
 	return nil
 }
 
+// accessTokenInfo is the diagnostic information about an access token that is safe to print.
+type accessTokenInfo struct {
+	Token     string
+	Issuer    string
+	Subject   string
+	Audience  []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	Scopes    []string
+}
+
+type accessTokenClaims struct {
+	jwt.RegisteredClaims
+
+	Scope       string   `json:"scope"`
+	Permissions []string `json:"permissions"`
+}
+
+// accessTokenInfoFromToken decodes the claims of the given access token without verifying it
+// or making any network calls, so that it can be used to debug credentials while offline.
+func accessTokenInfoFromToken(token string) (*accessTokenInfo, error) {
+	var claims accessTokenClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return nil, errors.Wrap(err, "could not decode access token")
+	}
+
+	info := &accessTokenInfo{
+		Token:    redactToken(token),
+		Issuer:   claims.Issuer,
+		Subject:  claims.Subject,
+		Audience: claims.Audience,
+		Scopes:   append(strings.Fields(claims.Scope), claims.Permissions...),
+	}
+	if claims.IssuedAt != nil {
+		info.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		info.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return info, nil
+}
+
+// redactToken returns enough of token to tell tokens apart without exposing a usable credential.
+func redactToken(token string) string {
+	const shown = 4
+	if len(token) <= 2*shown {
+		return "[redacted]"
+	}
+	return token[:shown] + "..." + token[len(token)-shown:]
+}
+
+func printAccessTokenInfo(w io.Writer, info *accessTokenInfo) {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return "unknown"
+		}
+		return t.Format("Mon Jan 2 15:04:05 MST 2006")
+	}
+	expires := formatTime(info.ExpiresAt)
+	if !info.ExpiresAt.IsZero() && info.ExpiresAt.Before(time.Now()) {
+		expires += " (expired)"
+	}
+
+	for _, field := range [][2]string{
+		{"token", info.Token},
+		{"issuer", info.Issuer},
+		{"subject", info.Subject},
+		{"audience", strings.Join(info.Audience, ", ")},
+		{"issued at", formatTime(info.IssuedAt)},
+		{"expires", expires},
+		{"scopes", strings.Join(info.Scopes, " ")},
+	} {
+		fmt.Fprintf(w, "%-10s %s\n", field[0]+":", field[1])
+	}
+}
+
 func (c *appClient) ensureLoggedIn() error {
 	if c.client != nil {
 		return nil
 	}
 
 	if c.conf.Auth == nil {
-		return errors.New("not logged in: run the following command to login:\n\tviam login")
+		return newAuthError(errors.New("not logged in: run the following command to login:\n\tviam login"))
 	}
 
 	if c.conf.Auth.isExpired() {
 		if !c.conf.Auth.canRefresh() {
 			utils.UncheckedError(c.logout())
-			return errors.New("token expired and cannot refresh")
+			return newAuthError(errors.New("token expired and cannot refresh"))
 		}
 
 		// expired.
 		newToken, err := c.authFlow.refreshToken(c.c.Context, c.conf.Auth)
 		if err != nil {
 			utils.UncheckedError(c.logout()) // clear cache if failed to refresh
-			return errors.Wrapf(err, "error while refreshing token")
+			return newAuthError(errors.Wrapf(err, "error while refreshing token"))
 		}
 
-		// write token to config.
-		c.conf.Auth = newToken
-		if err := storeConfigToCache(c.conf); err != nil {
+		if err := c.saveToken(newToken); err != nil {
 			return err
 		}
 	}
@@ -236,16 +334,52 @@ func (c *appClient) ensureLoggedIn() error {
 	return nil
 }
 
-// logout logs out the client and clears the config.
+// logout logs out the client and removes its token from the credential store.
 func (c *appClient) logout() error {
-	if err := removeConfigFromCache(); err != nil && !os.IsNotExist(err) {
+	if err := c.credentials.remove(); err != nil {
 		return err
 	}
-	c.conf = &config{}
+	c.conf = &config{CredentialStore: c.conf.CredentialStore}
 	return nil
 }
 
+// saveToken makes t the client's token and persists it to the credential store.
+func (c *appClient) saveToken(t *token) error {
+	c.conf.Auth = t
+	return c.credentials.save(t)
+}
+
+// switchCredentialStore moves the client's token, if it has one, to the named store.
+func (c *appClient) switchCredentialStore(name string) error {
+	store, err := newCredentialStore(name)
+	if err != nil {
+		return err
+	}
+	return c.moveCredentials(store, name)
+}
+
+// moveCredentials moves the client's token, if it has one, to store, which is recorded in the config as
+// name. The token is saved to store before it is removed from the old one, so that the user stays logged
+// in if store cannot be written to, such as when the keychain's tools are not installed.
+func (c *appClient) moveCredentials(store credentialStore, name string) error {
+	old := c.credentials
+	if c.conf.Auth != nil {
+		if err := store.save(c.conf.Auth); err != nil {
+			return err
+		}
+	}
+	c.credentials = store
+	c.conf.CredentialStore = name
+	if _, ok := old.(fileCredentialStore); ok && c.conf.Auth != nil {
+		// the file store is the cached config, which store has just rewritten without a plaintext token.
+		return nil
+	}
+	return old.remove()
+}
+
+// prepareDial returns what is needed to dial the given part, with a dial context derived from ctx.
 func (c *appClient) prepareDial(
+	ctx context.Context,
 	orgStr, locStr, robotStr, partStr string,
 	debug bool,
 ) (context.Context, string, []rpc.DialOption, error) {
@@ -268,7 +402,7 @@ func (c *appClient) prepareDial(
 	defer func() {
 		utils.UncheckedError(rpcDialer.Close())
 	}()
-	dialCtx := rpc.ContextWithDialer(c.c.Context, rpcDialer)
+	dialCtx := rpc.ContextWithDialer(ctx, rpcDialer)
 
 	rpcOpts := append(c.copyRPCOpts(),
 		rpc.WithExternalAuth(c.baseURL.Host, part.Fqdn),
@@ -403,25 +537,38 @@ func (a *authFlow) makeDeviceCodeRequest(ctx context.Context, discovery *openIDD
 }
 
 func (a *authFlow) directUser(code *deviceCodeResponse) error {
-	infof(a.console, `you can log into Viam through the opened browser window or follow the URL below.
-ensure the code in the URL matches the one shown in your browser.
-  %s`, code.VerificationURIComplete)
-
 	if a.disableBrowserOpen {
+		infof(a.console, `to log into Viam, open the URL below in a browser on any device and enter the code %s
+  %s
+or open this URL, which has the code already filled in:
+  %s
+waiting for you to log in...`, code.UserCode, code.VerificationURI, code.VerificationURIComplete)
 		return nil
 	}
 
+	infof(a.console, `you can log into Viam through the opened browser window or follow the URL below.
+ensure the code in the URL matches the one shown in your browser.
+  %s`, code.VerificationURIComplete)
+
 	return openbrowser(code.VerificationURIComplete)
 }
 
 func (a *authFlow) waitForUser(ctx context.Context, code *deviceCodeResponse, discovery *openIDDiscoveryResponse) (*tokenResponse, error) {
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(code.ExpiresIn*int(time.Second)))
+	expiresIn := time.Duration(code.ExpiresIn * int(time.Second))
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, expiresIn)
 	defer cancel()
 
 	waitInterval := defaultWaitInterval
+	pollInterval := time.Duration(code.Interval * int(time.Second))
+	if pollInterval <= 0 {
+		pollInterval = defaultWaitInterval
+	}
 	for {
 		if !utils.SelectContextOrWait(ctxWithTimeout, waitInterval) {
-			return nil, fmt.Errorf("timed out getting token after %f seconds", waitInterval.Seconds())
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("timed out getting token after %v, log in again to get a new code", expiresIn)
 		}
 
 		data := url.Values{}
@@ -443,13 +590,16 @@ func (a *authFlow) waitForUser(ctx context.Context, code *deviceCodeResponse, di
 		}
 
 		resp, err := processTokenResponse(res)
-		if err != nil && !errors.Is(err, errAuthorizationPending) {
-			return nil, err
-		} else if err == nil {
+		switch {
+		case err == nil:
 			return resp, nil
+		case errors.Is(err, errSlowDown):
+			pollInterval += slowDownInterval
+		case !errors.Is(err, errAuthorizationPending):
+			return nil, err
 		}
 
-		waitInterval = time.Duration(code.Interval * int(time.Second))
+		waitInterval = pollInterval
 	}
 }
 
@@ -563,8 +713,11 @@ func processTokenResponse(res *http.Response) (*tokenResponse, error) {
 			return nil, err
 		}
 
-		if resp.Error == "authorization_pending" {
+		switch resp.Error {
+		case "authorization_pending":
 			return nil, errAuthorizationPending
+		case "slow_down":
+			return nil, errSlowDown
 		}
 
 		return nil, fmt.Errorf("%s: %s", resp.Error, resp.ErrorDescription)
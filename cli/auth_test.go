@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/urfave/cli/v2"
+	"go.viam.com/test"
+)
+
+func TestAccessTokenInfo(t *testing.T) {
+	issuedAt := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	expiresAt := issuedAt.Add(time.Hour)
+	claims := accessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://auth.viam.com/",
+			Subject:   "user-id",
+			Audience:  jwt.ClaimStrings{"https://app.viam.com/"},
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Scope:       "openid email offline_access",
+		Permissions: []string{"read:robots"},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	test.That(t, err, test.ShouldBeNil)
+
+	// the token is expired, but should still be decoded since this is for debugging.
+	info, err := accessTokenInfoFromToken(token)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, info.Issuer, test.ShouldEqual, "https://auth.viam.com/")
+	test.That(t, info.Subject, test.ShouldEqual, "user-id")
+	test.That(t, info.Audience, test.ShouldResemble, []string{"https://app.viam.com/"})
+	test.That(t, info.IssuedAt.Equal(issuedAt), test.ShouldBeTrue)
+	test.That(t, info.ExpiresAt.Equal(expiresAt), test.ShouldBeTrue)
+	test.That(t, info.Scopes, test.ShouldResemble, []string{"openid", "email", "offline_access", "read:robots"})
+
+	var out bytes.Buffer
+	printAccessTokenInfo(&out, info)
+	test.That(t, out.String(), test.ShouldNotContainSubstring, token)
+	test.That(t, out.String(), test.ShouldContainSubstring, info.Token)
+	test.That(t, out.String(), test.ShouldContainSubstring, "(expired)")
+	test.That(t, out.String(), test.ShouldContainSubstring, "openid email offline_access read:robots")
+
+	_, err = accessTokenInfoFromToken("not-a-jwt")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestRedactToken(t *testing.T) {
+	test.That(t, redactToken("short"), test.ShouldEqual, "[redacted]")
+	test.That(t, redactToken("abcdefghijklmnop"), test.ShouldEqual, "abcd...mnop")
+}
+
+func TestWhoAmIAction(t *testing.T) {
+	useTempViamDotDir(t)
+	whoAmI := func(t *testing.T) (string, string) {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		cCtx := cli.NewContext(&cli.App{Writer: &out, ErrWriter: &errOut}, nil, nil)
+		test.That(t, WhoAmIAction(cCtx), test.ShouldBeNil)
+		return out.String(), errOut.String()
+	}
+
+	out, errOut := whoAmI(t)
+	test.That(t, out, test.ShouldContainSubstring, `not logged in. run "login" command`)
+	test.That(t, errOut, test.ShouldBeEmpty)
+
+	test.That(t, fileCredentialStore{}.save(&token{
+		AccessToken: "access",
+		ExpiresAt:   time.Now().Add(time.Hour),
+		User:        userData{Email: "user@viam.com"},
+	}), test.ShouldBeNil)
+	out, errOut = whoAmI(t)
+	test.That(t, out, test.ShouldEqual, "user@viam.com\n")
+	test.That(t, errOut, test.ShouldBeEmpty)
+}
+
+func TestDeviceLogin(t *testing.T) {
+	idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"email": "someone@example.com",
+		"sub":   "user-id",
+	}).SignedString([]byte("secret"))
+	test.That(t, err, test.ShouldBeNil)
+
+	// the auth server grants the token on the third poll.
+	var polls atomic.Int32
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	writeJSON := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		test.That(t, json.NewEncoder(w).Encode(v), test.ShouldBeNil)
+	}
+	mux.HandleFunc(defaultOpenIDDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, openIDDiscoveryResponse{
+			TokenEndPoint:               server.URL + "/token",
+			DeviceAuthorizationEndpoint: server.URL + "/device/code",
+		})
+	})
+	mux.HandleFunc("/device/code", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, deviceCodeResponse{
+			DeviceCode:              "device-code",
+			UserCode:                "ABCD-EFGH",
+			VerificationURI:         server.URL + "/activate",
+			VerificationURIComplete: server.URL + "/activate?user_code=ABCD-EFGH",
+			ExpiresIn:               60,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		test.That(t, r.ParseForm(), test.ShouldBeNil)
+		test.That(t, r.PostForm.Get("device_code"), test.ShouldEqual, "device-code")
+		switch polls.Add(1) {
+		case 1, 2:
+			writeJSON(w, http.StatusForbidden, tokenErrorResponse{Error: "authorization_pending"})
+		default:
+			writeJSON(w, http.StatusOK, tokenResponse{
+				AccessToken:  "access-token",
+				RefreshToken: "refresh-token",
+				IDToken:      idToken,
+				ExpiresIn:    3600,
+			})
+		}
+	})
+
+	var console bytes.Buffer
+	flow := newCLIAuthFlowWithAuthDomain(server.URL, "audience", "client-id", &console)
+	flow.disableBrowserOpen = true
+	tok, err := flow.login(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, polls.Load(), test.ShouldEqual, 3)
+	test.That(t, tok.AccessToken, test.ShouldEqual, "access-token")
+	test.That(t, tok.User.Email, test.ShouldEqual, "someone@example.com")
+	test.That(t, tok.TokenURL, test.ShouldEqual, server.URL+"/token")
+	test.That(t, console.String(), test.ShouldContainSubstring, "enter the code ABCD-EFGH")
+	test.That(t, console.String(), test.ShouldContainSubstring, server.URL+"/activate\n")
+
+	t.Run("slow down", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		writeJSON(rec, http.StatusBadRequest, tokenErrorResponse{Error: "slow_down"})
+		_, err := processTokenResponse(rec.Result())
+		test.That(t, err, test.ShouldBeError, errSlowDown)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		mux.HandleFunc("/denied/token", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusForbidden, tokenErrorResponse{Error: "access_denied", ErrorDescription: "user said no"})
+		})
+		_, err := flow.waitForUser(context.Background(), &deviceCodeResponse{ExpiresIn: 60},
+			&openIDDiscoveryResponse{TokenEndPoint: server.URL + "/denied/token"})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "user said no")
+	})
+}
@@ -3,22 +3,28 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
 	"runtime/debug"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/edaniels/golog"
+	"github.com/fatih/color"
 	"github.com/fullstorydev/grpcurl"
 	"github.com/google/uuid"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
-	"go.uber.org/zap"
+	"go.uber.org/multierr"
 	datapb "go.viam.com/api/app/data/v1"
 	apppb "go.viam.com/api/app/v1"
 	"go.viam.com/utils"
@@ -26,8 +32,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
 
-	rconfig "go.viam.com/rdk/config"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/client"
@@ -44,6 +50,8 @@ type appClient struct {
 	baseURL    *url.URL
 	rpcOpts    []rpc.DialOption
 	authFlow   *authFlow
+	// credentials is where conf.Auth is persisted.
+	credentials credentialStore
 
 	selectedOrg *apppb.Organization
 	selectedLoc *apppb.Location
@@ -72,6 +80,44 @@ func ListOrganizationsAction(c *cli.Context) error {
 	return nil
 }
 
+// CreateOrganizationAction is the corresponding Action for 'organizations create'.
+func CreateOrganizationAction(c *cli.Context) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	client, err := newAppClient(c)
+	if err != nil {
+		return err
+	}
+	if err := client.ensureLoggedIn(); err != nil {
+		return err
+	}
+	resp, err := client.client.CreateOrganization(c.Context, &apppb.CreateOrganizationRequest{Name: c.String("name")})
+	if err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			return errors.Wrapf(err, "%q does not have permission to create organizations", client.conf.Auth.User.Email)
+		}
+		return errors.Wrap(err, "could not create organization")
+	}
+	org := resp.Organization
+	if format == formatJSON {
+		return printJSON(c.App.Writer, organizationJSON{ID: org.Id, Name: org.Name, PublicNamespace: org.PublicNamespace})
+	}
+	fmt.Fprintf(c.App.Writer, "created organization %s (id: %s)\n", org.Name, org.Id)
+	if org.PublicNamespace != "" {
+		fmt.Fprintf(c.App.Writer, "public namespace: %s\n", org.PublicNamespace)
+	}
+	return nil
+}
+
+// organizationJSON is how an organization is printed with --format json.
+type organizationJSON struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	PublicNamespace string `json:"public_namespace,omitempty"`
+}
+
 // ListLocationsAction is the corresponding Action for 'locations list'.
 func ListLocationsAction(c *cli.Context) error {
 	client, err := newAppClient(c)
@@ -108,8 +154,91 @@ func ListLocationsAction(c *cli.Context) error {
 	return listLocations(orgStr)
 }
 
+// CreateLocationAction is the corresponding Action for 'locations create'.
+func CreateLocationAction(c *cli.Context) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	client, err := newAppClient(c)
+	if err != nil {
+		return err
+	}
+	loc, err := client.createLocation(c.String("organization"), c.String("name"))
+	if err != nil {
+		return errors.Wrap(err, "could not create location")
+	}
+	if format == formatJSON {
+		return printJSON(c.App.Writer, locationJSON{ID: loc.Id, Name: loc.Name, OrganizationID: client.selectedOrg.Id})
+	}
+	fmt.Fprintf(c.App.Writer, "created location %s (id: %s) in %s\n", loc.Name, loc.Id, client.selectedOrg.Name)
+	return nil
+}
+
+// DeleteLocationAction is the corresponding Action for 'locations delete'.
+func DeleteLocationAction(c *cli.Context) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	locStr := c.Args().First()
+	if locStr == "" {
+		return errors.New("location required")
+	}
+	client, err := newAppClient(c)
+	if err != nil {
+		return err
+	}
+	if err := client.selectOrganization(c.String("organization")); err != nil {
+		return err
+	}
+	if err := client.selectLocation(locStr); err != nil {
+		return err
+	}
+	loc := client.selectedLoc
+
+	if !c.Bool("yes") {
+		ok, err := confirmf(c, "delete location %s (id: %s) from %s?", loc.Name, loc.Id, client.selectedOrg.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("aborted")
+		}
+	}
+	if _, err := client.client.DeleteLocation(c.Context, &apppb.DeleteLocationRequest{LocationId: loc.Id}); err != nil {
+		return errors.Wrap(err, "could not delete location")
+	}
+	if format == formatJSON {
+		return printJSON(c.App.Writer, locationJSON{ID: loc.Id, Name: loc.Name, OrganizationID: client.selectedOrg.Id})
+	}
+	fmt.Fprintf(c.App.Writer, "deleted location %s (id: %s)\n", loc.Name, loc.Id)
+	return nil
+}
+
+// locationJSON is how a location is printed with --format json.
+type locationJSON struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	OrganizationID string `json:"organization_id"`
+}
+
 // ListRobotsAction is the corresponding Action for 'robots list'.
 func ListRobotsAction(c *cli.Context) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+	onlineOnly := c.Bool("online-only")
+	offlineAfter := c.Duration("offline-after")
+	if onlineOnly && offlineAfter <= 0 {
+		return newValidationError(errors.New("offline-after must be positive"))
+	}
+	limit := c.Int("limit")
+	if limit < 0 {
+		return newValidationError(errors.Errorf("limit must not be negative, got %d", limit))
+	}
+
 	client, err := newAppClient(c)
 	if err != nil {
 		return err
@@ -120,15 +249,79 @@ func ListRobotsAction(c *cli.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "could not list robots")
 	}
+	now := time.Now()
+	if onlineOnly {
+		robots = onlineRobots(robots, offlineAfter, now)
+	}
+	total := len(robots)
+	robots = limitRobots(robots, limit)
 
+	if format == formatJSON {
+		if len(robots) < total {
+			// the note goes to stderr so that the JSON on stdout stays parseable.
+			infof(c.App.ErrWriter, "showing the first %d of %d robots, raise --limit to see more", len(robots), total)
+		}
+		return printJSON(c.App.Writer, robotsJSON(robots))
+	}
 	if orgStr == "" || locStr == "" {
 		fmt.Fprintf(c.App.Writer, "%s -> %s\n", client.selectedOrg.Name, client.selectedLoc.Name)
 	}
+	printRobotList(c.App.Writer, robots, onlineOnly, now)
+	if len(robots) < total {
+		infof(c.App.Writer, "showing the first %d of %d robots, raise --limit to see more", len(robots), total)
+	}
+	return nil
+}
 
+// limitRobots sorts robots by name and returns at most the first limit of them. A limit of 0 returns
+// them all.
+func limitRobots(robots []*apppb.Robot, limit int) []*apppb.Robot {
+	sorted := make([]*apppb.Robot, len(robots))
+	copy(sorted, robots)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetName() < sorted[j].GetName()
+	})
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// onlineRobots returns the robots that were last online less than offlineAfter before now.
+func onlineRobots(robots []*apppb.Robot, offlineAfter time.Duration, now time.Time) []*apppb.Robot {
+	var online []*apppb.Robot
 	for _, robot := range robots {
-		fmt.Fprintf(c.App.Writer, "%s (id: %s)\n", robot.Name, robot.Id)
+		if now.Sub(robot.LastAccess.AsTime()) < offlineAfter {
+			online = append(online, robot)
+		}
 	}
-	return nil
+	return online
+}
+
+// printRobotList prints a line per robot, including how long ago it was last online if showLastOnline is set.
+func printRobotList(w io.Writer, robots []*apppb.Robot, showLastOnline bool, now time.Time) {
+	for _, robot := range robots {
+		if showLastOnline {
+			fmt.Fprintf(w, "%s (id: %s) last online %s ago\n", robot.Name, robot.Id, now.Sub(robot.LastAccess.AsTime()).Round(time.Second))
+			continue
+		}
+		fmt.Fprintf(w, "%s (id: %s)\n", robot.Name, robot.Id)
+	}
+}
+
+// robotJSON is how a robot is printed with --format json.
+type robotJSON struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	LastAccess time.Time `json:"last_access"`
+}
+
+func robotsJSON(robots []*apppb.Robot) []robotJSON {
+	out := make([]robotJSON, 0, len(robots))
+	for _, robot := range robots {
+		out = append(out, robotJSON{ID: robot.Id, Name: robot.Name, LastAccess: robot.LastAccess.AsTime()})
+	}
+	return out
 }
 
 // RobotStatusAction is the corresponding Action for 'robot status'.
@@ -140,21 +333,57 @@ func RobotStatusAction(c *cli.Context) error {
 
 	orgStr := c.String("organization")
 	locStr := c.String("location")
-	robot, err := client.robot(orgStr, locStr, c.String("robot"))
-	if err != nil {
+	robotStr := c.String("robot")
+	if !c.Bool("watch") {
+		_, err := client.printRobotStatus(orgStr, locStr, robotStr, nil)
 		return err
 	}
-	parts, err := client.robotParts(client.selectedOrg.Id, client.selectedLoc.Id, robot.Id)
+
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return newValidationError(errors.New("interval must be positive"))
+	}
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+	defer stop()
+	c.Context = ctx
+
+	var lastOnline map[string]bool
+	for {
+		fmt.Fprint(c.App.Writer, clearScreen)
+		fmt.Fprintf(c.App.Writer, "Every %s: viam robot status\t%s\n\n", interval, time.Now().Format(time.UnixDate))
+		lastOnline, err = client.printRobotStatus(orgStr, locStr, robotStr, lastOnline)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if !utils.SelectContextOrWait(ctx, interval) {
+			return nil
+		}
+	}
+}
+
+// printRobotStatus prints the status of the robot and its parts and returns whether each part,
+// by ID, is currently online. Parts whose online state differs from lastOnline are highlighted;
+// pass a nil lastOnline to highlight nothing.
+func (c *appClient) printRobotStatus(orgStr, locStr, robotStr string, lastOnline map[string]bool) (map[string]bool, error) {
+	robot, err := c.robot(orgStr, locStr, robotStr)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := c.robotParts(c.selectedOrg.Id, c.selectedLoc.Id, robot.Id)
 	if err != nil {
-		return errors.Wrap(err, "could not get robot parts")
+		return nil, errors.Wrap(err, "could not get robot parts")
 	}
 
+	w := c.c.App.Writer
 	if orgStr == "" || locStr == "" {
-		fmt.Fprintf(c.App.Writer, "%s -> %s\n", client.selectedOrg.Name, client.selectedLoc.Name)
+		fmt.Fprintf(w, "%s -> %s\n", c.selectedOrg.Name, c.selectedLoc.Name)
 	}
 
 	fmt.Fprintf(
-		c.App.Writer,
+		w,
 		"ID: %s\nname: %s\nlast access: %s (%s ago)\n",
 		robot.Id,
 		robot.Name,
@@ -163,31 +392,45 @@ func RobotStatusAction(c *cli.Context) error {
 	)
 
 	if len(parts) != 0 {
-		fmt.Fprintln(c.App.Writer, "parts:")
+		fmt.Fprintln(w, "parts:")
 	}
+	online := make(map[string]bool, len(parts))
 	for i, part := range parts {
 		name := part.Name
 		if part.MainPart {
 			name += " (main)"
 		}
+		online[part.Id] = isPartOnline(part)
+		state := "offline"
+		if online[part.Id] {
+			state = "online"
+		}
+		if wasOnline, ok := lastOnline[part.Id]; lastOnline != nil && (!ok || wasOnline != online[part.Id]) {
+			state = color.New(color.Bold, color.FgYellow).Sprintf("%s (changed)", state)
+		}
 		fmt.Fprintf(
-			c.App.Writer,
-			"\tID: %s\n\tname: %s\n\tlast access: %s (%s ago)\n",
+			w,
+			"\tID: %s\n\tname: %s\n\tstate: %s\n\tlast access: %s (%s ago)\n",
 			part.Id,
 			name,
+			state,
 			part.LastAccess.AsTime().Format(time.UnixDate),
 			time.Since(part.LastAccess.AsTime()),
 		)
 		if i != len(parts)-1 {
-			fmt.Fprintln(c.App.Writer, "")
+			fmt.Fprintln(w, "")
 		}
 	}
 
-	return nil
+	return online, nil
 }
 
 // RobotLogsAction is the corresponding Action for 'robot logs'.
 func RobotLogsAction(c *cli.Context) error {
+	filter, err := newLogFilter(c.String("grep"), c.Bool("grep-invert"))
+	if err != nil {
+		return err
+	}
 	client, err := newAppClient(c)
 	if err != nil {
 		return err
@@ -206,36 +449,36 @@ func RobotLogsAction(c *cli.Context) error {
 		return errors.Wrap(err, "could not get robot parts")
 	}
 
-	for i, part := range parts {
-		if i != 0 {
-			fmt.Fprintln(c.App.Writer, "")
-		}
-
-		var header string
-		if orgStr == "" || locStr == "" || robotStr == "" {
-			header = fmt.Sprintf("%s -> %s -> %s -> %s", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name, part.Name)
-		} else {
-			header = part.Name
-		}
-		if err := client.printRobotPartLogs(
-			orgStr, locStr, robotStr, part.Id,
-			c.Bool("errors"),
-			"\t",
-			header,
-		); err != nil {
-			return errors.Wrap(err, "could not print robot logs")
+	if orgStr == "" || locStr == "" || robotStr == "" {
+		fmt.Fprintf(c.App.Writer, "%s -> %s -> %s\n", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
+	}
+	if c.Bool("tail") {
+		if err := client.tailRobotLogs(parts, c.Bool("errors"), filter, defaultLogReorderDelay); err != nil {
+			return errors.Wrap(err, "could not tail robot logs")
 		}
+		return nil
+	}
+	if err := client.printRobotLogs(parts, c.Bool("errors"), filter); err != nil {
+		return errors.Wrap(err, "could not print robot logs")
 	}
-
 	return nil
 }
 
-// RobotPartStatusAction is the corresponding Action for 'robot part status'.
+// RobotPartStatusAction is the corresponding Action for 'robot part status'. With --format json or
+// --fail-on-unhealthy, it also connects to the part to report the health of the part's resources.
 func RobotPartStatusAction(c *cli.Context) error {
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
 	client, err := newAppClient(c)
 	if err != nil {
 		return err
 	}
+	logger, err := newLogger(c)
+	if err != nil {
+		return err
+	}
 
 	orgStr := c.String("organization")
 	locStr := c.String("location")
@@ -250,28 +493,53 @@ func RobotPartStatusAction(c *cli.Context) error {
 		return errors.Wrap(err, "could not get robot part")
 	}
 
-	if orgStr == "" || locStr == "" || robotStr == "" {
-		fmt.Fprintf(c.App.Writer, "%s -> %s -> %s\n", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
+	// connecting to the part is slow, and times out for offline parts, so it is only checked when asked for.
+	failOnUnhealthy := c.Bool("fail-on-unhealthy")
+	var health partHealthJSON
+	if format == formatJSON || failOnUnhealthy {
+		health, err = client.partHealth(c.Context, orgStr, locStr, robotStr, part, logger)
+		if err != nil {
+			return err
+		}
 	}
+	if format == formatJSON {
+		if err := printJSON(c.App.Writer, health); err != nil {
+			return err
+		}
+	} else {
+		if orgStr == "" || locStr == "" || robotStr == "" {
+			fmt.Fprintf(c.App.Writer, "%s -> %s -> %s\n", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
+		}
 
-	name := part.Name
-	if part.MainPart {
-		name += " (main)"
+		name := part.Name
+		if part.MainPart {
+			name += " (main)"
+		}
+		fmt.Fprintf(
+			c.App.Writer,
+			"ID: %s\nname: %s\nlast access: %s (%s ago)\n",
+			part.Id,
+			name,
+			part.LastAccess.AsTime().Format(time.UnixDate),
+			time.Since(part.LastAccess.AsTime()),
+		)
+		if failOnUnhealthy {
+			printPartHealth(c.App.Writer, health)
+		}
 	}
-	fmt.Fprintf(
-		c.App.Writer,
-		"ID: %s\nname: %s\nlast access: %s (%s ago)\n",
-		part.Id,
-		name,
-		part.LastAccess.AsTime().Format(time.UnixDate),
-		time.Since(part.LastAccess.AsTime()),
-	)
 
+	if failOnUnhealthy && !health.Healthy {
+		return errPartUnhealthy(health)
+	}
 	return nil
 }
 
 // RobotPartLogsAction is the corresponding Action for 'robot part logs'.
 func RobotPartLogsAction(c *cli.Context) error {
+	filter, err := newLogFilter(c.String("grep"), c.Bool("grep-invert"))
+	if err != nil {
+		return err
+	}
 	client, err := newAppClient(c)
 	if err != nil {
 		return err
@@ -289,10 +557,17 @@ func RobotPartLogsAction(c *cli.Context) error {
 	if orgStr == "" || locStr == "" || robotStr == "" {
 		header = fmt.Sprintf("%s -> %s -> %s", client.selectedOrg.Name, client.selectedLoc.Name, robot.Name)
 	}
+	out, closeOut, err := robotPartLogsWriter(c)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
 	if c.Bool("tail") {
 		return client.tailRobotPartLogs(
 			orgStr, locStr, robotStr, c.String("part"),
 			c.Bool("errors"),
+			filter,
+			out,
 			"",
 			header,
 		)
@@ -300,16 +575,60 @@ func RobotPartLogsAction(c *cli.Context) error {
 	return client.printRobotPartLogs(
 		orgStr, locStr, robotStr, c.String("part"),
 		c.Bool("errors"),
+		filter,
+		out,
 		"",
 		header,
 	)
 }
 
+// robotPartLogsWriter returns where 'robot part logs' writes log entries: the terminal unless --quiet is
+// set, and --output-file if it is set. The returned func closes the file.
+func robotPartLogsWriter(c *cli.Context) (io.Writer, func(), error) {
+	path := c.String("output-file")
+	if path == "" {
+		if c.Bool("quiet") {
+			return nil, nil, newValidationError(errors.New("--quiet requires --output-file, or there is nowhere to write the logs"))
+		}
+		return c.App.Writer, func() {}, nil
+	}
+	logFile, err := newRotatingLogFile(path, c.Int64("max-size")*1024*1024, c.App.ErrWriter)
+	if err != nil {
+		return nil, nil, err
+	}
+	closeFile := func() {
+		if err := logFile.Close(); err != nil {
+			warningf(c.App.ErrWriter, "could not close %s: %s", path, err)
+		}
+	}
+	if c.Bool("quiet") {
+		// the logs are shown after all if they cannot be written to the file.
+		logFile.fallback = c.App.Writer
+		return logFile, closeFile, nil
+	}
+	return io.MultiWriter(c.App.Writer, logFile), closeFile, nil
+}
+
+// defaultRunTimeout is how long 'robot part run' waits for a command that is not streamed, unless
+// --timeout is set.
+const defaultRunTimeout = time.Minute
+
 // RobotPartRunAction is the corresponding Action for 'robot part run'.
 func RobotPartRunAction(c *cli.Context) error {
 	svcMethod := c.Args().First()
 	if svcMethod == "" {
-		return errors.New("service method required")
+		return newValidationError(errors.New("service method required"))
+	}
+	describe := c.Bool("describe")
+	if describe && c.IsSet("data") {
+		return newValidationError(errors.New("--describe does not invoke the method, so it does not take --data"))
+	}
+	var data string
+	if !describe {
+		var err error
+		if data, err = readRunPayload(c.String("data"), c.App.Reader); err != nil {
+			return newValidationError(err)
+		}
 	}
 
 	client, err := newAppClient(c)
@@ -317,10 +636,16 @@ func RobotPartRunAction(c *cli.Context) error {
 		return err
 	}
 
-	// Create logger based on presence of "debug" flag.
-	logger := zap.NewNop().Sugar()
-	if c.Bool("debug") {
-		logger = golog.NewDebugLogger("cli")
+	logger, err := newLogger(c)
+	if err != nil {
+		return err
+	}
+
+	// streams run until they are interrupted unless they are given a timeout.
+	streamDur := c.Duration("stream")
+	timeout := c.Duration("timeout")
+	if !c.IsSet("timeout") && streamDur == 0 {
+		timeout = defaultRunTimeout
 	}
 
 	return client.runRobotPartCommand(
@@ -329,8 +654,10 @@ func RobotPartRunAction(c *cli.Context) error {
 		c.String("robot"),
 		c.String("part"),
 		svcMethod,
-		c.String("data"),
-		c.Duration("stream"),
+		data,
+		streamDur,
+		timeout,
+		describe,
 		c.Bool("debug"),
 		logger,
 	)
@@ -345,10 +672,9 @@ func RobotPartShellAction(c *cli.Context) error {
 		return err
 	}
 
-	// Create logger based on presence of "debug" flag.
-	logger := zap.NewNop().Sugar()
-	if c.Bool("debug") {
-		logger = golog.NewDebugLogger("cli")
+	logger, err := newLogger(c)
+	if err != nil {
+		return err
 	}
 
 	return client.startRobotPartShell(
@@ -389,24 +715,80 @@ func VersionAction(c *cli.Context) error {
 	if dep, ok := deps["go.viam.com/api"]; ok {
 		apiVersion = dep.Version
 	}
-	appVersion := rconfig.Version
-	if appVersion == "" {
-		appVersion = "(dev)"
+	appVersion := cliVersion()
+	format, err := outputFormat(c)
+	if err != nil {
+		return err
+	}
+
+	out := versionJSON{Version: appVersion, Git: version, API: apiVersion}
+	if c.Bool("check-update") {
+		// a failed check should never fail the command, so only warn about it.
+		httpClient := &http.Client{Timeout: checkUpdateTimeout}
+		latest, available, err := checkForUpdate(c.Context, httpClient, latestReleaseURL, appVersion)
+		if err != nil {
+			warningf(c.App.ErrWriter, "could not check for updates: %s", err)
+		} else {
+			out.LatestVersion = latest
+			out.UpdateAvailable = &available
+		}
+	}
+
+	if format == formatJSON {
+		return printJSON(c.App.Writer, out)
 	}
 	fmt.Fprintf(c.App.Writer, "version %s git=%s api=%s\n", appVersion, version, apiVersion)
+	if out.UpdateAvailable != nil {
+		if *out.UpdateAvailable {
+			fmt.Fprintf(c.App.Writer, "update available: %s, to upgrade run:\n\t%s\n", out.LatestVersion, upgradeCommand())
+		} else {
+			fmt.Fprintf(c.App.Writer, "up to date with latest version %s\n", out.LatestVersion)
+		}
+	}
 	return nil
 }
 
+// defaultBaseURL is the app that is dialed when no --base-url is given.
+const defaultBaseURL = "https://app.viam.com:443"
+
+// checkBaseURL parses the --base-url app is dialed at, filling in the default port for its scheme, and
+// returns the dial options it needs. app is dialed over TLS for https:// and without it for http://, as a
+// local app instance is usually served. Since credentials are then sent in the clear, http:// must be
+// allowed explicitly with --allow-insecure.
 func checkBaseURL(c *cli.Context) (*url.URL, []rpc.DialOption, error) {
 	baseURL := c.String("base-url")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
 	baseURLParsed, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, newValidationError(errors.Wrap(err, "invalid --base-url"))
+	}
+
+	var defaultPort string
+	switch baseURLParsed.Scheme {
+	case "https":
+		defaultPort = "443"
+	case "http":
+		defaultPort = "80"
+	default:
+		return nil, nil, newValidationError(errors.Errorf("--base-url %q must start with https:// or http://", baseURL))
+	}
+	if baseURLParsed.Hostname() == "" {
+		return nil, nil, newValidationError(errors.Errorf("--base-url %q has no host", baseURL))
+	}
+	if baseURLParsed.Port() == "" {
+		baseURLParsed.Host = net.JoinHostPort(baseURLParsed.Hostname(), defaultPort)
 	}
 
 	if baseURLParsed.Scheme == "https" {
 		return baseURLParsed, nil, nil
 	}
+	if !c.Bool("allow-insecure") {
+		return nil, nil, newValidationError(errors.Errorf(
+			"--base-url %q does not use TLS, so your credentials would be sent unencrypted. "+
+				"pass --allow-insecure to connect anyway, such as to a local app instance", baseURL))
+	}
 	return baseURLParsed, []rpc.DialOption{
 		rpc.WithInsecure(),
 		rpc.WithAllowInsecureWithCredentialsDowngrade(),
@@ -437,10 +819,18 @@ func newAppClient(c *cli.Context) (*appClient, error) {
 		}
 		conf = &config{}
 	}
+	credentials, err := newCredentialStore(conf.CredentialStore)
+	if err != nil {
+		return nil, err
+	}
+	if conf.Auth, err = credentials.load(); err != nil {
+		return nil, err
+	}
 
 	return &appClient{
 		c:           c,
 		conf:        conf,
+		credentials: credentials,
 		baseURL:     baseURL,
 		rpcOpts:     rpcOpts,
 		selectedOrg: &apppb.Organization{},
@@ -624,6 +1014,23 @@ func (c *appClient) listLocations(orgID string) ([]*apppb.Location, error) {
 	return (*c.locs), nil
 }
 
+func (c *appClient) createLocation(orgStr, name string) (*apppb.Location, error) {
+	if err := c.ensureLoggedIn(); err != nil {
+		return nil, err
+	}
+	if err := c.selectOrganization(orgStr); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.CreateLocation(c.c.Context, &apppb.CreateLocationRequest{
+		OrganizationId: c.selectedOrg.Id,
+		Name:           name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Location, nil
+}
+
 func (c *appClient) listRobots(orgStr, locStr string) ([]*apppb.Robot, error) {
 	if err := c.ensureLoggedIn(); err != nil {
 		return nil, err
@@ -677,6 +1084,14 @@ func (c *appClient) robotPart(orgStr, locStr, robotStr, partStr string) (*apppb.
 	return nil, errors.Errorf("no robot part found for %q", partStr)
 }
 
+// partOnlineThreshold is how recently a part must have accessed the app to be considered online.
+const partOnlineThreshold = 10 * time.Second
+
+// isPartOnline reports whether the part has checked in with the app recently enough to be considered online.
+func isPartOnline(part *apppb.RobotPart) bool {
+	return time.Since(part.LastAccess.AsTime()) < partOnlineThreshold
+}
+
 func (c *appClient) robotPartLogs(orgStr, locStr, robotStr, partStr string, errorsOnly bool) ([]*apppb.LogEntry, error) {
 	part, err := c.robotPart(orgStr, locStr, robotStr, partStr)
 	if err != nil {
@@ -710,10 +1125,10 @@ func (c *appClient) robotParts(orgStr, locStr, robotStr string) ([]*apppb.RobotP
 	return resp.Parts, nil
 }
 
-func (c *appClient) printRobotPartLogsInner(logs []*apppb.LogEntry, indent string) {
+func (c *appClient) printRobotPartLogsInner(w io.Writer, logs []*apppb.LogEntry, indent string) {
 	for _, log := range logs {
 		fmt.Fprintf(
-			c.c.App.Writer,
+			w,
 			"%s%s\t%s\t%s\t%s\n",
 			indent,
 			log.Time.AsTime().Format("2006-01-02T15:04:05.000Z0700"),
@@ -724,25 +1139,42 @@ func (c *appClient) printRobotPartLogsInner(logs []*apppb.LogEntry, indent strin
 	}
 }
 
-func (c *appClient) printRobotPartLogs(orgStr, locStr, robotStr, partStr string, errorsOnly bool, indent, header string) error {
+func (c *appClient) printRobotPartLogs(
+	orgStr, locStr, robotStr, partStr string,
+	errorsOnly bool,
+	filter *logFilter,
+	w io.Writer,
+	indent, header string,
+) error {
 	logs, err := c.robotPartLogs(orgStr, locStr, robotStr, partStr, errorsOnly)
 	if err != nil {
 		return err
 	}
+	logs = filter.apply(logs)
 
 	if header != "" {
 		fmt.Fprintln(c.c.App.Writer, header)
 	}
 	if len(logs) == 0 {
+		if filter != nil {
+			fmt.Fprintf(c.c.App.Writer, "%sno recent logs match the filter\n", indent)
+			return nil
+		}
 		fmt.Fprintf(c.c.App.Writer, "%sno recent logs\n", indent)
 		return nil
 	}
-	c.printRobotPartLogsInner(logs, indent)
+	c.printRobotPartLogsInner(w, logs, indent)
 	return nil
 }
 
-// tailRobotPartLogs tails and prints logs for the given robot part.
-func (c *appClient) tailRobotPartLogs(orgStr, locStr, robotStr, partStr string, errorsOnly bool, indent, header string) error {
+// tailRobotPartLogs tails logs for the given robot part and writes them to w, dropping those the filter doesn't keep as they arrive.
+func (c *appClient) tailRobotPartLogs(
+	orgStr, locStr, robotStr, partStr string,
+	errorsOnly bool,
+	filter *logFilter,
+	w io.Writer,
+	indent, header string,
+) error {
 	part, err := c.robotPart(orgStr, locStr, robotStr, partStr)
 	if err != nil {
 		return err
@@ -767,33 +1199,236 @@ func (c *appClient) tailRobotPartLogs(orgStr, locStr, robotStr, partStr string,
 			}
 			return err
 		}
-		c.printRobotPartLogsInner(resp.Logs, indent)
+		c.printRobotPartLogsInner(w, filter.apply(resp.Logs), indent)
+	}
+}
+
+// printRobotLogs fetches the logs of every given part and prints them as a single stream
+// ordered by timestamp, with the name of the part each line came from.
+// If only some of the parts' logs can be fetched, the rest are still printed and a partial failure is returned.
+func (c *appClient) printRobotLogs(parts []*apppb.RobotPart, errorsOnly bool, filter *logFilter) error {
+	logsByPart := make([][]*apppb.LogEntry, len(parts))
+	var errs error
+	var numFailed int
+	for i, part := range parts {
+		resp, err := c.client.GetRobotPartLogs(c.c.Context, &apppb.GetRobotPartLogsRequest{
+			Id:         part.Id,
+			ErrorsOnly: errorsOnly,
+		})
+		if err != nil {
+			errs = multierr.Append(errs, errors.Wrapf(err, "could not get logs for part %q", part.Name))
+			numFailed++
+			continue
+		}
+		logsByPart[i] = filter.apply(resp.Logs)
+	}
+	if numFailed == len(parts) && errs != nil {
+		return errs
+	}
+
+	merged := mergePartLogs(parts, logsByPart)
+	if len(merged) == 0 {
+		if filter != nil {
+			fmt.Fprintln(c.c.App.Writer, "no recent logs match the filter")
+		} else {
+			fmt.Fprintln(c.c.App.Writer, "no recent logs")
+		}
+	}
+	c.printPartLogEntries(merged)
+	if errs != nil {
+		return newPartialFailureError(errs)
+	}
+	return nil
+}
+
+// tailRobotLogs follows the logs of every given part at once. Entries are held in a reorder
+// buffer for up to reorderDelay so that lines arriving from different parts are printed roughly
+// in timestamp order.
+func (c *appClient) tailRobotLogs(parts []*apppb.RobotPart, errorsOnly bool, filter *logFilter, reorderDelay time.Duration) error {
+	ctx, cancel := context.WithCancel(c.c.Context)
+	defer cancel()
+
+	entries := make(chan partLogEntry)
+	errs := make(chan error, len(parts))
+	for _, part := range parts {
+		part := part
+		tailClient, err := c.client.TailRobotPartLogs(ctx, &apppb.TailRobotPartLogsRequest{
+			Id:         part.Id,
+			ErrorsOnly: errorsOnly,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "could not tail logs for part %q", part.Name)
+		}
+		utils.PanicCapturingGo(func() {
+			for {
+				resp, err := tailClient.Recv()
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						err = nil
+					}
+					errs <- err
+					return
+				}
+				for _, log := range filter.apply(resp.Logs) {
+					select {
+					case <-ctx.Done():
+						errs <- nil
+						return
+					case entries <- partLogEntry{part: part.Name, log: log}:
+					}
+				}
+			}
+		})
+	}
+
+	ticker := time.NewTicker(reorderDelay / 4)
+	defer ticker.Stop()
+	buf := newLogReorderBuffer(reorderDelay)
+	remaining := len(parts)
+	for remaining > 0 {
+		select {
+		case <-ctx.Done():
+			c.printPartLogEntries(buf.flush(time.Now(), true))
+			return nil
+		case entry := <-entries:
+			buf.add(entry, time.Now())
+		case err := <-errs:
+			remaining--
+			if err != nil && !errors.Is(err, context.Canceled) {
+				c.printPartLogEntries(buf.flush(time.Now(), true))
+				return err
+			}
+		case now := <-ticker.C:
+			c.printPartLogEntries(buf.flush(now, false))
+		}
+	}
+	c.printPartLogEntries(buf.flush(time.Now(), true))
+	return nil
+}
+
+func (c *appClient) printPartLogEntries(entries []partLogEntry) {
+	for _, entry := range entries {
+		fmt.Fprintf(
+			c.c.App.Writer,
+			"%s\t%s\t%s\t%s\t%s\n",
+			entry.log.Time.AsTime().Format("2006-01-02T15:04:05.000Z0700"),
+			entry.part,
+			entry.log.Level,
+			entry.log.LoggerName,
+			entry.log.Message,
+		)
 	}
 }
 
 func (c *appClient) runRobotPartCommand(
 	orgStr, locStr, robotStr, partStr string,
 	svcMethod, data string,
-	streamDur time.Duration,
-	debug bool,
+	streamDur, timeout time.Duration,
+	describe, debug bool,
 	logger golog.Logger,
 ) error {
-	dialCtx, fqdn, rpcOpts, err := c.prepareDial(orgStr, locStr, robotStr, partStr, debug)
+	// the timeout covers dialing as well as the command itself.
+	ctx := c.c.Context
+	if timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	dialCtx, fqdn, rpcOpts, err := c.prepareDial(ctx, orgStr, locStr, robotStr, partStr, debug)
 	if err != nil {
-		return err
+		return wrapRunTimeout(ctx, err, timeout)
 	}
 
 	conn, err := grpc.Dial(dialCtx, fqdn, logger, rpcOpts...)
 	if err != nil {
-		return err
+		return wrapRunTimeout(ctx, err, timeout)
 	}
 	defer func() {
 		utils.UncheckedError(conn.Close())
 	}()
 
-	refCtx := metadata.NewOutgoingContext(c.c.Context, nil)
+	if describe {
+		return wrapRunTimeout(ctx, describeRobotPartRPC(ctx, c.c.App.Writer, conn, svcMethod), timeout)
+	}
+	return wrapRunTimeout(ctx, c.runRobotPartRPC(ctx, conn, svcMethod, data, streamDur), timeout)
+}
+
+// readRunPayload returns the JSON payload for 'robot part run'. The data flag is either the payload itself,
+// @path to read it from a file, or - to read it from stdin. The payload is checked to be well-formed JSON,
+// which may be several concatenated messages for client streaming methods.
+func readRunPayload(data string, stdin io.Reader) (string, error) {
+	source := "--data"
+	switch {
+	case data == "-":
+		source = "stdin"
+		payload, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read payload from stdin")
+		}
+		data = string(payload)
+	case strings.HasPrefix(data, "@"):
+		source = strings.TrimPrefix(data, "@")
+		//nolint:gosec
+		payload, err := os.ReadFile(source)
+		if err != nil {
+			return "", errors.Wrap(err, "could not read payload file")
+		}
+		data = string(payload)
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(data))
+	for {
+		var msg json.RawMessage
+		err := decoder.Decode(&msg)
+		if errors.Is(err, io.EOF) {
+			return data, nil
+		}
+		if err != nil {
+			offset := decoder.InputOffset()
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				offset = syntaxErr.Offset
+			} else if errors.Is(err, io.ErrUnexpectedEOF) {
+				offset = int64(len(data))
+			}
+			line, col := textPosition(data, offset)
+			return "", errors.Errorf("payload from %s is not valid JSON at line %d, column %d: %s", source, line, col, err)
+		}
+	}
+}
+
+// textPosition returns the 1-based line and column in text of the last byte before offset, which is the
+// byte that encoding/json reports a syntax error at.
+func textPosition(text string, offset int64) (int, int) {
+	if offset > int64(len(text)) {
+		offset = int64(len(text))
+	}
+	before := text[:offset]
+	line := strings.Count(before, "\n") + 1
+	return line, len(before) - strings.LastIndex(before, "\n") - 1
+}
+
+// wrapRunTimeout replaces err with a clearer error if it was caused by the run timeout of ctx expiring.
+func wrapRunTimeout(ctx context.Context, err error, timeout time.Duration) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.Errorf("timed out after %s waiting for robot part to respond", timeout)
+	}
+	return err
+}
+
+// runRobotPartRPC invokes svcMethod over conn with the given JSON data, printing the responses. If streamDur
+// is non-zero the method is invoked repeatedly at that interval until ctx is done, which is not
+// considered an error unless it happens while a call is in flight.
+func (c *appClient) runRobotPartRPC(
+	ctx context.Context,
+	conn rpc.ClientConn,
+	svcMethod, data string,
+	streamDur time.Duration,
+) error {
+	refCtx := metadata.NewOutgoingContext(ctx, nil)
 	refClient := grpcreflect.NewClientV1Alpha(refCtx, reflectpb.NewServerReflectionClient(conn))
-	reflSource := grpcurl.DescriptorSourceFromServer(c.c.Context, refClient)
+	reflSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
 	descSource := reflSource
 
 	options := grpcurl.FormatOptions{
@@ -819,7 +1454,7 @@ func (c *appClient) runRobotPartCommand(
 		}
 
 		if err := grpcurl.InvokeRPC(
-			c.c.Context,
+			ctx,
 			descSource,
 			conn,
 			svcMethod,
@@ -831,6 +1466,9 @@ func (c *appClient) runRobotPartCommand(
 		}
 
 		if h.Status.Code() != codes.OK {
+			if err := ctx.Err(); err != nil {
+				return false, err
+			}
 			grpcurl.PrintStatus(c.c.App.ErrWriter, h.Status, formatter)
 			cli.OsExiter(1)
 			return false, nil
@@ -848,15 +1486,15 @@ func (c *appClient) runRobotPartCommand(
 	defer ticker.Stop()
 
 	for {
-		if err := c.c.Context.Err(); err != nil {
-			if errors.Is(err, context.Canceled) {
+		if err := ctx.Err(); err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 				return nil
 			}
 			return err
 		}
 
 		select {
-		case <-c.c.Context.Done():
+		case <-ctx.Done():
 			return nil
 		case <-ticker.C:
 			if ok, err := invoke(); err != nil {
@@ -873,7 +1511,7 @@ func (c *appClient) startRobotPartShell(
 	debug bool,
 	logger golog.Logger,
 ) error {
-	dialCtx, fqdn, rpcOpts, err := c.prepareDial(orgStr, locStr, robotStr, partStr, debug)
+	dialCtx, fqdn, rpcOpts, err := c.prepareDial(c.c.Context, orgStr, locStr, robotStr, partStr, debug)
 	if err != nil {
 		return err
 	}
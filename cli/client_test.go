@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/urfave/cli/v2"
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/components/base"
+	viamgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestRunRobotPartRPCTimeout(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	// a base that never finishes moving, as if the part were unresponsive
+	unresponsiveBase := inject.NewBase("base1")
+	unresponsiveBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	coll, err := resource.NewAPIResourceCollection(base.API, map[resource.Name]base.Base{base.Named("base1"): unresponsiveBase})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[base.Base](base.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, coll), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+
+	client := &appClient{c: cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &bytes.Buffer{}}, nil, nil)}
+	const method = "viam.component.base.v1.BaseService/MoveStraight"
+	const data = `{"name": "base1", "distance_mm": 100, "mm_per_sec": 10}`
+
+	t.Run("unresponsive call times out", func(t *testing.T) {
+		timeout := 100 * time.Millisecond
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err := wrapRunTimeout(ctx, client.runRobotPartRPC(ctx, conn, method, data, 0), timeout)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "timed out after 100ms")
+	})
+
+	t.Run("unresponsive stream times out", func(t *testing.T) {
+		timeout := 100 * time.Millisecond
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		err := wrapRunTimeout(ctx, client.runRobotPartRPC(ctx, conn, method, data, 10*time.Millisecond), timeout)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "timed out after 100ms")
+	})
+}
+
+func TestReadRunPayload(t *testing.T) {
+	payloadPath := filepath.Join(t.TempDir(), "payload.json")
+	test.That(t, os.WriteFile(payloadPath, []byte("{\n  \"name\": \"base1\"\n}\n"), 0o600), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name     string
+		data     string
+		stdin    string
+		expected string
+	}{
+		{"inline", `{"name": "base1"}`, "", `{"name": "base1"}`},
+		{"empty", "", "", ""},
+		{"file", "@" + payloadPath, "", "{\n  \"name\": \"base1\"\n}\n"},
+		{"stdin", "-", `{"name": "base1"}`, `{"name": "base1"}`},
+		{"several messages", `{"name": "a"} {"name": "b"}`, "", `{"name": "a"} {"name": "b"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, err := readRunPayload(tc.data, strings.NewReader(tc.stdin))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, payload, test.ShouldEqual, tc.expected)
+		})
+	}
+
+	for _, tc := range []struct {
+		name     string
+		data     string
+		stdin    string
+		expected string
+	}{
+		{"malformed inline", `{"name": base1}`, "", "payload from --data is not valid JSON at line 1, column 10"},
+		{"malformed stdin", "-", "{\n  \"name\": \"base1\",\n}", "payload from stdin is not valid JSON at line 3, column 1"},
+		{"truncated", `{"name": "base1"`, "", "payload from --data is not valid JSON at line 1, column 16: unexpected EOF"},
+		{"missing file", "@" + payloadPath + ".missing", "", "could not read payload file"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := readRunPayload(tc.data, strings.NewReader(tc.stdin))
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+		})
+	}
+
+	t.Run("malformed file", func(t *testing.T) {
+		malformedPath := filepath.Join(t.TempDir(), "malformed.json")
+		test.That(t, os.WriteFile(malformedPath, []byte("[1, 2,, 3]"), 0o600), test.ShouldBeNil)
+		_, err := readRunPayload("@"+malformedPath, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "payload from "+malformedPath+" is not valid JSON at line 1, column 7")
+	})
+}
+
+func TestOnlineRobots(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	robotAt := func(name string, ago time.Duration) *apppb.Robot {
+		return &apppb.Robot{Id: name + "-id", Name: name, LastAccess: timestamppb.New(now.Add(-ago))}
+	}
+	robots := []*apppb.Robot{
+		robotAt("fresh", 3*time.Second),
+		robotAt("stale", time.Hour),
+		robotAt("recent", 4*time.Minute+30*time.Second),
+		robotAt("just-stale", 5*time.Minute),
+	}
+
+	online := onlineRobots(robots, 5*time.Minute, now)
+	test.That(t, online, test.ShouldHaveLength, 2)
+	test.That(t, online[0].Name, test.ShouldEqual, "fresh")
+	test.That(t, online[1].Name, test.ShouldEqual, "recent")
+	test.That(t, onlineRobots(robots, 2*time.Hour, now), test.ShouldHaveLength, 4)
+
+	var out bytes.Buffer
+	printRobotList(&out, online, true, now)
+	test.That(t, out.String(), test.ShouldEqual,
+		"fresh (id: fresh-id) last online 3s ago\nrecent (id: recent-id) last online 4m30s ago\n")
+
+	out.Reset()
+	test.That(t, printJSON(&out, robotsJSON(online)), test.ShouldBeNil)
+	var printed []map[string]string
+	test.That(t, json.Unmarshal(out.Bytes(), &printed), test.ShouldBeNil)
+	test.That(t, printed, test.ShouldResemble, []map[string]string{
+		{"id": "fresh-id", "name": "fresh", "last_access": "2023-06-01T11:59:57Z"},
+		{"id": "recent-id", "name": "recent", "last_access": "2023-06-01T11:55:30Z"},
+	})
+}
+
+func TestLimitRobots(t *testing.T) {
+	robots := []*apppb.Robot{{Name: "charlie"}, {Name: "alpha"}, {Name: "delta"}, {Name: "bravo"}}
+	names := func(robots []*apppb.Robot) []string {
+		var names []string
+		for _, robot := range robots {
+			names = append(names, robot.Name)
+		}
+		return names
+	}
+
+	test.That(t, names(limitRobots(robots, 0)), test.ShouldResemble, []string{"alpha", "bravo", "charlie", "delta"})
+	test.That(t, names(limitRobots(robots, 2)), test.ShouldResemble, []string{"alpha", "bravo"})
+	test.That(t, names(limitRobots(robots, 10)), test.ShouldHaveLength, 4)
+	// the robots are sorted in a copy
+	test.That(t, robots[0].Name, test.ShouldEqual, "charlie")
+}
+
+func TestCheckBaseURL(t *testing.T) {
+	newContext := func(baseURL string, allowInsecure bool) *cli.Context {
+		flags := flag.NewFlagSet("viam", flag.ContinueOnError)
+		flags.String("base-url", baseURL, "")
+		flags.Bool("allow-insecure", allowInsecure, "")
+		out := &bytes.Buffer{}
+		return cli.NewContext(&cli.App{Writer: out, ErrWriter: out}, flags, nil)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		baseURL       string
+		allowInsecure bool
+		host          string
+		insecure      bool
+		err           string
+	}{
+		{name: "default", baseURL: "", host: "app.viam.com:443"},
+		{name: "https without a port", baseURL: "https://app.viam.dev", host: "app.viam.dev:443"},
+		{name: "http with a custom port", baseURL: "http://localhost:8080", allowInsecure: true, host: "localhost:8080", insecure: true},
+		{name: "http without a port", baseURL: "http://localhost", allowInsecure: true, host: "localhost:80", insecure: true},
+		{name: "http needs --allow-insecure", baseURL: "http://localhost:8080", err: "pass --allow-insecure"},
+		{name: "no scheme", baseURL: "localhost:8080", err: "must start with https:// or http://"},
+		{name: "no host", baseURL: "https://", err: "has no host"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			baseURL, rpcOpts, err := checkBaseURL(newContext(tc.baseURL, tc.allowInsecure))
+			if tc.err != "" {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+				test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+				return
+			}
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, baseURL.Host, test.ShouldEqual, tc.host)
+			if tc.insecure {
+				test.That(t, rpcOpts, test.ShouldNotBeEmpty)
+			} else {
+				test.That(t, rpcOpts, test.ShouldBeEmpty)
+			}
+		})
+	}
+
+	t.Run("http base is dialed without TLS", func(t *testing.T) {
+		useTempViamDotDir(t)
+		logger := golog.NewTestLogger(t)
+		listener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		rpcServer, err := rpc.NewServer(logger, rpc.WithUnauthenticated())
+		test.That(t, err, test.ShouldBeNil)
+		go rpcServer.Serve(listener)
+		defer func() {
+			test.That(t, rpcServer.Stop(), test.ShouldBeNil)
+		}()
+
+		client, err := newAppClient(newContext("http://"+listener.Addr().String(), true))
+		test.That(t, err, test.ShouldBeNil)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		conn, err := rpc.DialDirectGRPC(ctx, client.baseURL.Host, logger, client.copyRPCOpts()...)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		// the server has no services, so reaching it at all shows that the connection is plaintext.
+		err = conn.Invoke(ctx, "/viam.app.v1.AppService/ListOrganizations", &emptypb.Empty{}, &emptypb.Empty{})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+	})
+}
@@ -43,4 +43,6 @@ func storeConfigToCache(cfg *config) error {
 
 type config struct {
 	Auth *token `json:"auth"`
+	// CredentialStore is where Auth is persisted. Auth is only written to this file by the file store.
+	CredentialStore string `json:"credential_store,omitempty"`
 }
@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoginFlagCredentialStore is where login keeps the user's token: file or keychain.
+const LoginFlagCredentialStore = "credential-store"
+
+const (
+	// credentialStoreFile keeps the token in plaintext in the cached CLI config.
+	credentialStoreFile = "file"
+	// credentialStoreKeychain keeps the token in the operating system's keychain: Keychain on macOS,
+	// Secret Service on Linux, and the Credential Manager on Windows.
+	credentialStoreKeychain = "keychain"
+
+	keychainService = "viam-cli"
+	keychainAccount = "auth"
+)
+
+var errKeychainItemNotFound = errors.New("no credentials in the keychain")
+
+// credentialStore persists the token of the logged in user between invocations of the CLI.
+type credentialStore interface {
+	// load returns the stored token, or nil if there is none.
+	load() (*token, error)
+	save(t *token) error
+	// remove deletes the stored token, if there is one.
+	remove() error
+}
+
+// newCredentialStore returns the named store. The file store is used when no name is given, which is
+// the case for configs written before stores could be chosen.
+func newCredentialStore(name string) (credentialStore, error) {
+	switch name {
+	case "", credentialStoreFile:
+		return fileCredentialStore{}, nil
+	case credentialStoreKeychain:
+		return &keychainCredentialStore{keychain: commandKeychain{goos: runtime.GOOS}}, nil
+	default:
+		return nil, newValidationError(errors.Errorf("%s must be %q or %q, got %q",
+			LoginFlagCredentialStore, credentialStoreFile, credentialStoreKeychain, name))
+	}
+}
+
+// credentialStoreName returns the name that config records for the store.
+func credentialStoreName(store credentialStore) string {
+	if _, ok := store.(*keychainCredentialStore); ok {
+		return credentialStoreKeychain
+	}
+	return credentialStoreFile
+}
+
+// fileCredentialStore keeps the token in the cached CLI config, as the CLI always has.
+type fileCredentialStore struct{}
+
+func (fileCredentialStore) load() (*token, error) {
+	conf, err := configFromCache()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return conf.Auth, nil
+}
+
+func (fileCredentialStore) save(t *token) error {
+	return storeConfigToCache(&config{Auth: t})
+}
+
+func (fileCredentialStore) remove() error {
+	if err := removeConfigFromCache(); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// keychainCredentialStore keeps the token in a keychain. The cached CLI config only records that the
+// keychain is in use, so that later commands know where to look for the token.
+type keychainCredentialStore struct {
+	keychain keychain
+}
+
+func (s *keychainCredentialStore) load() (*token, error) {
+	secret, err := s.keychain.get(keychainService, keychainAccount)
+	if err != nil {
+		if errors.Is(err, errKeychainItemNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "could not read credentials from the keychain")
+	}
+	var t token
+	if err := json.Unmarshal([]byte(secret), &t); err != nil {
+		return nil, errors.Wrap(err, "credentials in the keychain are corrupt, run 'viam logout' and login again")
+	}
+	return &t, nil
+}
+
+func (s *keychainCredentialStore) save(t *token) error {
+	secret, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err := s.keychain.set(keychainService, keychainAccount, string(secret)); err != nil {
+		return errors.Wrap(err, "could not write credentials to the keychain")
+	}
+	return storeConfigToCache(&config{CredentialStore: credentialStoreKeychain})
+}
+
+func (s *keychainCredentialStore) remove() error {
+	if err := s.keychain.delete(keychainService, keychainAccount); err != nil && !errors.Is(err, errKeychainItemNotFound) {
+		return errors.Wrap(err, "could not remove credentials from the keychain")
+	}
+	return nil
+}
+
+// keychain is a store of secrets, each identified by a service and an account.
+type keychain interface {
+	// get returns errKeychainItemNotFound if there is no such secret.
+	get(service, account string) (string, error)
+	set(service, account, secret string) error
+	// delete returns errKeychainItemNotFound if there is no such secret.
+	delete(service, account string) error
+}
+
+// commandKeychain uses the command line tools that come with each operating system's keychain.
+type commandKeychain struct {
+	goos string
+}
+
+const (
+	// securityItemNotFound is the exit code of macOS's security tool when no item matches.
+	securityItemNotFound = 44
+	// securityMaxCommandLength is the longest command security reads in interactive mode.
+	securityMaxCommandLength = 4096
+	// securityEncodedPrefix marks secrets that were base64 encoded to be written with security -i.
+	securityEncodedPrefix = "base64:"
+)
+
+// windowsPasswordVault loads the WinRT type that gives access to the Windows Credential Manager.
+const windowsPasswordVault = "[void][Windows.Security.Credentials.PasswordVault,Windows.Security.Credentials,ContentType=WindowsRuntime];" +
+	"$vault = New-Object Windows.Security.Credentials.PasswordVault;"
+
+func (k commandKeychain) get(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch k.goos {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsPasswordVault+
+			"try { $cred = $vault.Retrieve($env:VIAM_KEYCHAIN_SERVICE, $env:VIAM_KEYCHAIN_ACCOUNT) } catch { exit 2 };"+
+			"$cred.RetrievePassword(); [Console]::Out.Write($cred.Password)")
+		cmd.Env = append(os.Environ(), "VIAM_KEYCHAIN_SERVICE="+service, "VIAM_KEYCHAIN_ACCOUNT="+account)
+	default:
+		return "", errors.Errorf("the keychain credential store is not supported on %s", k.goos)
+	}
+	out, err := runKeychainCommand(cmd, "")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && k.isNotFound(exitErr.ExitCode(), out) {
+			return "", errKeychainItemNotFound
+		}
+		return "", err
+	}
+	if k.goos == "linux" && out == "" {
+		return "", errKeychainItemNotFound
+	}
+	out = strings.TrimSuffix(out, "\n")
+	if k.goos == "darwin" && strings.HasPrefix(out, securityEncodedPrefix) {
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(out, securityEncodedPrefix))
+		if err != nil {
+			return "", errors.Wrap(err, "could not decode the secret from the keychain")
+		}
+		return string(secret), nil
+	}
+	return out, nil
+}
+
+func (k commandKeychain) set(service, account, secret string) error {
+	var cmd *exec.Cmd
+	stdin := ""
+	switch k.goos {
+	case "darwin":
+		// security only takes the password as an argument, which any local user could read from the process
+		// list, so the command is given to security's interactive mode on stdin instead. The secret is
+		// base64 encoded so that it needs no quoting. -U replaces an existing item.
+		cmd = exec.Command("security", "-i")
+		stdin = fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s%s\n",
+			service, account, securityEncodedPrefix, base64.StdEncoding.EncodeToString([]byte(secret)))
+		if len(stdin) > securityMaxCommandLength {
+			return errors.Errorf("credentials are too long for the macOS keychain, use --%s=%s instead",
+				LoginFlagCredentialStore, credentialStoreFile)
+		}
+	case "linux":
+		cmd = exec.Command("secret-tool", "store", "--label", "Viam CLI credentials", "service", service, "account", account)
+		stdin = secret
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsPasswordVault+
+			"$vault.Add((New-Object Windows.Security.Credentials.PasswordCredential("+
+			"$env:VIAM_KEYCHAIN_SERVICE, $env:VIAM_KEYCHAIN_ACCOUNT, [Console]::In.ReadToEnd())))")
+		cmd.Env = append(os.Environ(), "VIAM_KEYCHAIN_SERVICE="+service, "VIAM_KEYCHAIN_ACCOUNT="+account)
+		stdin = secret
+	default:
+		return errors.Errorf("the keychain credential store is not supported on %s", k.goos)
+	}
+	_, err := runKeychainCommand(cmd, stdin)
+	return err
+}
+
+func (k commandKeychain) delete(service, account string) error {
+	var cmd *exec.Cmd
+	switch k.goos {
+	case "darwin":
+		cmd = exec.Command("security", "delete-generic-password", "-s", service, "-a", account)
+	case "linux":
+		cmd = exec.Command("secret-tool", "clear", "service", service, "account", account)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsPasswordVault+
+			"try { $cred = $vault.Retrieve($env:VIAM_KEYCHAIN_SERVICE, $env:VIAM_KEYCHAIN_ACCOUNT) } catch { exit 2 };"+
+			"$vault.Remove($cred)")
+		cmd.Env = append(os.Environ(), "VIAM_KEYCHAIN_SERVICE="+service, "VIAM_KEYCHAIN_ACCOUNT="+account)
+	default:
+		return errors.Errorf("the keychain credential store is not supported on %s", k.goos)
+	}
+	out, err := runKeychainCommand(cmd, "")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && k.isNotFound(exitErr.ExitCode(), out) {
+			return errKeychainItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// isNotFound returns whether a keychain command exited because the secret does not exist.
+func (k commandKeychain) isNotFound(exitCode int, out string) bool {
+	switch k.goos {
+	case "darwin":
+		return exitCode == securityItemNotFound
+	case "linux":
+		// secret-tool exits 1 without printing anything when nothing matches.
+		return exitCode == 1 && out == ""
+	case "windows":
+		return exitCode == 2
+	default:
+		return false
+	}
+}
+
+// runKeychainCommand runs cmd with stdin as its input and returns its output. The error includes
+// anything the command printed to stderr.
+func runKeychainCommand(cmd *exec.Cmd, stdin string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), errors.Wrapf(err, "%s failed: %s", cmd.Path, msg)
+		}
+		return stdout.String(), errors.Wrapf(err, "%s failed", cmd.Path)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+)
+
+// inMemoryCredentialStore is a credential store that keeps the token in memory.
+type inMemoryCredentialStore struct {
+	token   *token
+	removed int
+}
+
+func (s *inMemoryCredentialStore) load() (*token, error) {
+	return s.token, nil
+}
+
+func (s *inMemoryCredentialStore) save(t *token) error {
+	s.token = t
+	return nil
+}
+
+func (s *inMemoryCredentialStore) remove() error {
+	s.token = nil
+	s.removed++
+	return nil
+}
+
+// unwritableCredentialStore is a credential store that cannot be saved to.
+type unwritableCredentialStore struct {
+	inMemoryCredentialStore
+}
+
+func (s *unwritableCredentialStore) save(t *token) error {
+	return errors.New("secret-tool: command not found")
+}
+
+// inMemoryKeychain is a keychain that keeps its secrets in memory.
+type inMemoryKeychain map[[2]string]string
+
+func (k inMemoryKeychain) get(service, account string) (string, error) {
+	secret, ok := k[[2]string{service, account}]
+	if !ok {
+		return "", errKeychainItemNotFound
+	}
+	return secret, nil
+}
+
+func (k inMemoryKeychain) set(service, account, secret string) error {
+	k[[2]string{service, account}] = secret
+	return nil
+}
+
+func (k inMemoryKeychain) delete(service, account string) error {
+	if _, ok := k[[2]string{service, account}]; !ok {
+		return errKeychainItemNotFound
+	}
+	delete(k, [2]string{service, account})
+	return nil
+}
+
+// useTempViamDotDir points the cached CLI config at a temporary directory for the test.
+func useTempViamDotDir(t *testing.T) {
+	t.Helper()
+	orig := viamDotDir
+	viamDotDir = t.TempDir()
+	t.Cleanup(func() { viamDotDir = orig })
+}
+
+func TestCredentialStore(t *testing.T) {
+	tok := &token{
+		AccessToken: "access",
+		ExpiresAt:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		User:        userData{Email: "user@viam.com"},
+	}
+
+	t.Run("login and logout use the store", func(t *testing.T) {
+		store := &inMemoryCredentialStore{}
+		client := &appClient{conf: &config{}, credentials: store}
+		test.That(t, client.saveToken(tok), test.ShouldBeNil)
+		test.That(t, store.token, test.ShouldResemble, tok)
+		test.That(t, client.conf.Auth, test.ShouldResemble, tok)
+
+		test.That(t, client.logout(), test.ShouldBeNil)
+		test.That(t, store.token, test.ShouldBeNil)
+		test.That(t, client.conf.Auth, test.ShouldBeNil)
+	})
+
+	t.Run("switching stores moves the token", func(t *testing.T) {
+		useTempViamDotDir(t)
+		store := &inMemoryCredentialStore{token: tok}
+		client := &appClient{conf: &config{Auth: tok, CredentialStore: "memory"}, credentials: store}
+		test.That(t, client.switchCredentialStore(credentialStoreFile), test.ShouldBeNil)
+		test.That(t, store.removed, test.ShouldEqual, 1)
+		test.That(t, store.token, test.ShouldBeNil)
+		test.That(t, credentialStoreName(client.credentials), test.ShouldEqual, credentialStoreFile)
+
+		conf, err := configFromCache()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.Auth.AccessToken, test.ShouldEqual, tok.AccessToken)
+
+		err = client.switchCredentialStore("vault")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+	})
+
+	t.Run("failing to save to the new store keeps the old one", func(t *testing.T) {
+		store := &inMemoryCredentialStore{token: tok}
+		client := &appClient{conf: &config{Auth: tok, CredentialStore: "memory"}, credentials: store}
+		err := client.moveCredentials(&unwritableCredentialStore{}, credentialStoreKeychain)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, store.removed, test.ShouldEqual, 0)
+		test.That(t, store.token, test.ShouldResemble, tok)
+		test.That(t, client.credentials, test.ShouldEqual, store)
+		test.That(t, client.conf.CredentialStore, test.ShouldEqual, "memory")
+	})
+
+	t.Run("switching from the file store to the keychain", func(t *testing.T) {
+		useTempViamDotDir(t)
+		client := &appClient{conf: &config{Auth: tok}, credentials: fileCredentialStore{}}
+		test.That(t, client.saveToken(tok), test.ShouldBeNil)
+		kc := inMemoryKeychain{}
+		test.That(t, client.moveCredentials(&keychainCredentialStore{keychain: kc}, credentialStoreKeychain), test.ShouldBeNil)
+		test.That(t, kc, test.ShouldHaveLength, 1)
+
+		// the config records that the keychain is in use, without the token.
+		conf, err := configFromCache()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.CredentialStore, test.ShouldEqual, credentialStoreKeychain)
+		test.That(t, conf.Auth, test.ShouldBeNil)
+	})
+
+	t.Run("keychain keeps the token out of the config file", func(t *testing.T) {
+		useTempViamDotDir(t)
+		kc := inMemoryKeychain{}
+		store := &keychainCredentialStore{keychain: kc}
+		loaded, err := store.load()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, loaded, test.ShouldBeNil)
+
+		test.That(t, store.save(tok), test.ShouldBeNil)
+		test.That(t, kc, test.ShouldHaveLength, 1)
+		//nolint:gosec
+		cached, err := os.ReadFile(getCLICachePath())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(cached), test.ShouldNotContainSubstring, tok.AccessToken)
+		conf, err := configFromCache()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.CredentialStore, test.ShouldEqual, credentialStoreKeychain)
+
+		loaded, err = store.load()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, loaded, test.ShouldResemble, tok)
+
+		test.That(t, store.remove(), test.ShouldBeNil)
+		test.That(t, kc, test.ShouldBeEmpty)
+		// removing credentials that are already gone is not an error.
+		test.That(t, store.remove(), test.ShouldBeNil)
+	})
+}
+
+func TestCommandKeychainDarwin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake security tool is a shell script")
+	}
+	// security records its arguments and input, and prints what is in found when asked for a password.
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" > \"$(dirname \"$0\")/args\"\n" +
+		"cat > \"$(dirname \"$0\")/stdin\"\n" +
+		"if [ \"$1\" = find-generic-password ]; then cat \"$(dirname \"$0\")/found\"; fi\n"
+	test.That(t, os.WriteFile(filepath.Join(dir, "security"), []byte(script), 0o700), test.ShouldBeNil)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	read := func(name string) string {
+		//nolint:gosec
+		contents, err := os.ReadFile(filepath.Join(dir, name))
+		test.That(t, err, test.ShouldBeNil)
+		return strings.TrimSuffix(string(contents), "\n")
+	}
+	kc := commandKeychain{goos: "darwin"}
+
+	const secret = `{"access_token":"secret token","user_data":{"name":"O'Brien"}}`
+	test.That(t, kc.set(keychainService, keychainAccount, secret), test.ShouldBeNil)
+	// the secret is not passed as an argument, where other users could see it.
+	test.That(t, read("args"), test.ShouldEqual, "-i")
+	command := read("stdin")
+	test.That(t, command, test.ShouldStartWith, "add-generic-password -U -s viam-cli -a auth -w base64:")
+	test.That(t, command, test.ShouldNotContainSubstring, "secret token")
+
+	encoded := command[strings.LastIndex(command, " ")+1:]
+	test.That(t, os.WriteFile(filepath.Join(dir, "found"), []byte(encoded+"\n"), 0o600), test.ShouldBeNil)
+	got, err := kc.get(keychainService, keychainAccount)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got, test.ShouldEqual, secret)
+
+	// secrets written before they were encoded are read as they are.
+	test.That(t, os.WriteFile(filepath.Join(dir, "found"), []byte(secret+"\n"), 0o600), test.ShouldBeNil)
+	got, err = kc.get(keychainService, keychainAccount)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got, test.ShouldEqual, secret)
+
+	err = kc.set(keychainService, keychainAccount, strings.Repeat("x", securityMaxCommandLength))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "too long")
+}
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,8 +21,11 @@ import (
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 	datapb "go.viam.com/api/app/data/v1"
+	"go.viam.com/utils"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/timestamppb"
+
+	rdkutils "go.viam.com/rdk/utils"
 )
 
 const (
@@ -55,9 +60,11 @@ const (
 	DataFlagMethod = "method"
 	// DataFlagMimeTypes is the mime types filter.
 	DataFlagMimeTypes = "mime-types"
-	// DataFlagStart is an ISO-8601 timestamp indicating the start of the interval filter.
+	// DataFlagStart is an ISO-8601 timestamp, or a duration before now such as -24h or -7d, indicating the
+	// start of the interval filter.
 	DataFlagStart = "start"
-	// DataFlagEnd is an ISO-8601 timestamp indicating the end of the interval filter.
+	// DataFlagEnd is an ISO-8601 timestamp, or a duration before now, indicating the end of the interval
+	// filter. It defaults to now when the start is relative.
 	DataFlagEnd = "end"
 	// DataFlagParallelDownloads is the number of download requests to make in parallel.
 	DataFlagParallelDownloads = "parallel"
@@ -65,6 +72,16 @@ const (
 	DataFlagTags = "tags"
 	// DataFlagBboxLabels is the bbox labels filter.
 	DataFlagBboxLabels = "bbox-labels"
+	// DataFlagExtMap maps additional mime types to file extensions for exported binary data.
+	DataFlagExtMap = "ext-map"
+	// DataFlagCountOnly makes export print how much data matches the filters instead of downloading it.
+	DataFlagCountOnly = "count-only"
+	// DataFlagCompress makes export gzip tabular data as it is written. Binary data is never compressed.
+	DataFlagCompress = "compress"
+	// DataFlagColumns limits exported tabular data to the given top-level fields of each record.
+	DataFlagColumns = "columns"
+	// DataFlagSync makes export add only the data captured since the export already in the destination.
+	DataFlagSync = "sync"
 
 	dataTypeBinary  = "binary"
 	dataTypeTabular = "tabular"
@@ -76,25 +93,77 @@ func DataExportAction(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	countOnly := c.Bool(DataFlagCountOnly)
+	if !countOnly && c.Path(DataFlagDestination) == "" {
+		return newValidationError(errors.Errorf("%s is required unless %s is set", DataFlagDestination, DataFlagCountOnly))
+	}
 
 	client, err := newAppClient(c)
 	if err != nil {
 		return err
 	}
+	if countOnly {
+		return client.printDataCount(c.String(DataFlagDataType), filter)
+	}
+
+	dst := c.Path(DataFlagDestination)
+	compression := compressionNone
+	if c.String(DataFlagDataType) == dataTypeTabular && c.Bool(DataFlagCompress) {
+		compression = compressionGzip
+	}
+	var incremental *exportSync
+	if c.Bool(DataFlagSync) {
+		prior, err := readExportManifest(dst)
+		if err != nil {
+			return err
+		}
+		if prior == nil {
+			infof(c.App.Writer, "there is no export in %s to --%s with, so everything matching the filters is exported", dst, DataFlagSync)
+		} else {
+			incremental, err = newExportSync(dst, prior, c.String(DataFlagDataType), compression, time.Now())
+			if err != nil {
+				return err
+			}
+			incremental.applyTo(filter)
+		}
+	}
 
+	var files []exportedFile
+	var exportErr error
 	switch c.String(DataFlagDataType) {
 	case dataTypeBinary:
-		if err := client.binaryData(c.Path(DataFlagDestination), filter, c.Uint(DataFlagParallelDownloads)); err != nil {
+		extMap, err := parseExtMap(c.StringSlice(DataFlagExtMap))
+		if err != nil {
 			return err
 		}
-	case dataTypeTabular:
-		if err := client.tabularData(c.Path(DataFlagDestination), filter); err != nil {
-			return err
+		for _, flag := range []string{DataFlagCompress, DataFlagColumns} {
+			if c.IsSet(flag) {
+				warningf(c.App.ErrWriter, "--%s only applies to tabular data, binary data is exported as is", flag)
+			}
 		}
+		files, exportErr = client.binaryData(dst, filter, c.Uint(DataFlagParallelDownloads), extMap, incremental)
+	case dataTypeTabular:
+		files, exportErr = client.tabularData(dst, filter, compression, c.StringSlice(DataFlagColumns), incremental)
 	default:
-		return errors.Errorf("%s must be binary or tabular, got %q", DataFlagDataType, c.String(DataFlagDataType))
+		return newValidationError(errors.Errorf("%s must be binary or tabular, got %q", DataFlagDataType, c.String(DataFlagDataType)))
 	}
-	return nil
+	// A partial export still gets a manifest, so that it is clear which files were downloaded.
+	if exportErr != nil && errorCategoryOf(exportErr) != categoryPartialFailure {
+		return exportErr
+	}
+	if incremental != nil {
+		files = append(incremental.prior.Files, files...)
+	}
+	manifest, err := newExportManifest(c, c.String(DataFlagDataType), filter, files, time.Now())
+	if err != nil {
+		return err
+	}
+	manifest.Compression = compression
+	manifest.Complete = exportErr == nil
+	if err := manifest.write(dst); err != nil {
+		return err
+	}
+	return exportErr
 }
 
 // DataDeleteAction is the corresponding action for 'data delete'.
@@ -119,12 +188,64 @@ func DataDeleteAction(c *cli.Context) error {
 			return err
 		}
 	default:
-		return errors.Errorf("%s must be binary or tabular, got %q", DataFlagDataType, c.String(DataFlagDataType))
+		return newValidationError(errors.Errorf("%s must be binary or tabular, got %q", DataFlagDataType, c.String(DataFlagDataType)))
 	}
 
 	return nil
 }
 
+// printDataCount prints how many items of the given data type match the filter, and how much space they
+// take up if the API reports it, without downloading any of them.
+func (c *appClient) printDataCount(dataType string, filter *datapb.Filter) error {
+	if err := c.ensureLoggedIn(); err != nil {
+		return err
+	}
+	var count, totalSizeBytes uint64
+	switch dataType {
+	case dataTypeBinary:
+		resp, err := c.dataClient.BinaryDataByFilter(c.c.Context, &datapb.BinaryDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{Filter: filter},
+			CountOnly:   true,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not count binary data")
+		}
+		count, totalSizeBytes = resp.GetCount(), resp.GetTotalSizeBytes()
+	case dataTypeTabular:
+		resp, err := c.dataClient.TabularDataByFilter(c.c.Context, &datapb.TabularDataByFilterRequest{
+			DataRequest: &datapb.DataRequest{Filter: filter},
+			CountOnly:   true,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not count tabular data")
+		}
+		count, totalSizeBytes = resp.GetCount(), resp.GetTotalSizeBytes()
+	default:
+		return newValidationError(errors.Errorf("%s must be binary or tabular, got %q", DataFlagDataType, dataType))
+	}
+
+	if totalSizeBytes == 0 {
+		fmt.Fprintf(c.c.App.Writer, "%d %s data items match the filters\n", count, dataType)
+		return nil
+	}
+	fmt.Fprintf(c.c.App.Writer, "%d %s data items match the filters, about %s in total\n", count, dataType, formatBytes(totalSizeBytes))
+	return nil
+}
+
+// formatBytes formats a number of bytes with decimal units, e.g. 1.5 GB.
+func formatBytes(n uint64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "kMGTPE"[exp])
+}
+
 func createDataFilter(c *cli.Context) (*datapb.Filter, error) {
 	filter := &datapb.Filter{}
 
@@ -178,40 +299,104 @@ func createDataFilter(c *cli.Context) (*datapb.Filter, error) {
 	if len(c.StringSlice(DataFlagBboxLabels)) != 0 {
 		filter.BboxLabels = c.StringSlice(DataFlagBboxLabels)
 	}
-	var start *timestamppb.Timestamp
-	var end *timestamppb.Timestamp
-	timeLayout := time.RFC3339
-	if c.String(DataFlagStart) != "" {
-		t, err := time.Parse(timeLayout, c.String(DataFlagStart))
+	interval, err := parseCaptureInterval(c.String(DataFlagStart), c.String(DataFlagEnd), time.Now())
+	if err != nil {
+		return nil, newValidationError(err)
+	}
+	filter.Interval = interval
+	return filter, nil
+}
+
+// parseCaptureInterval parses the start and end flags into an interval, or nil if neither is set. Each
+// may be an RFC 3339 timestamp or a duration before now, such as -90m, -24h, or -7d. If start is relative
+// and end is not set, end is now.
+func parseCaptureInterval(startFlag, endFlag string, now time.Time) (*datapb.CaptureInterval, error) {
+	if startFlag == "" && endFlag == "" {
+		return nil, nil
+	}
+	interval := &datapb.CaptureInterval{}
+	var start, end time.Time
+	var startIsRelative bool
+	if startFlag != "" {
+		var err error
+		start, startIsRelative, err = parseTimeFlag(startFlag, now)
 		if err != nil {
-			return nil, errors.Wrap(err, "could not parse start flag")
+			return nil, errors.Wrapf(err, "could not parse %s flag", DataFlagStart)
 		}
-		start = timestamppb.New(t)
+		interval.Start = timestamppb.New(start)
 	}
-	if c.String(DataFlagEnd) != "" {
-		t, err := time.Parse(timeLayout, c.String(DataFlagEnd))
+	switch {
+	case endFlag != "":
+		var err error
+		end, _, err = parseTimeFlag(endFlag, now)
 		if err != nil {
-			return nil, errors.Wrap(err, "could not parse end flag")
+			return nil, errors.Wrapf(err, "could not parse %s flag", DataFlagEnd)
 		}
-		end = timestamppb.New(t)
+		interval.End = timestamppb.New(end)
+	case startIsRelative:
+		end = now
+		interval.End = timestamppb.New(end)
+	}
+	if interval.Start != nil && interval.End != nil && start.After(end) {
+		return nil, errors.Errorf("%s %s is after %s %s",
+			DataFlagStart, start.Format(time.RFC3339), DataFlagEnd, end.Format(time.RFC3339))
 	}
-	if start != nil || end != nil {
-		filter.Interval = &datapb.CaptureInterval{
-			Start: start,
-			End:   end,
+	return interval, nil
+}
+
+// parseTimeFlag parses an RFC 3339 timestamp, or a negative duration that is relative to now. Durations
+// accept the units of time.ParseDuration as well as d for days and w for weeks.
+func parseTimeFlag(value string, now time.Time) (time.Time, bool, error) {
+	if !strings.HasPrefix(value, "-") {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, false, errors.Errorf(
+				"%q must be an ISO-8601 timestamp such as 2023-06-01T00:00:00Z, or a duration before now such as -24h or -7d", value)
 		}
+		return t, false, nil
 	}
-	return filter, nil
+	ago, err := parseRelativeDuration(strings.TrimPrefix(value, "-"))
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "%q is not a valid duration", value)
+	}
+	return now.Add(-ago), true, nil
 }
 
-// BinaryData downloads binary data matching filter to dst.
-func (c *appClient) binaryData(dst string, filter *datapb.Filter, parallelDownloads uint) error {
+func parseRelativeDuration(value string) (time.Duration, error) {
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(value, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(value, "w"):
+		unit = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, err
+		}
+		if d < 0 {
+			return 0, errors.New("duration must not be negated twice")
+		}
+		return d, nil
+	}
+	count, err := strconv.ParseFloat(value[:len(value)-1], 64)
+	if err != nil || count < 0 {
+		return 0, errors.New("days and weeks must be given as a single positive number, such as 7d or 1.5w")
+	}
+	return time.Duration(count * float64(unit)), nil
+}
+
+// BinaryData downloads binary data matching filter to dst, and returns the files that were downloaded.
+// With --sync, incremental skips the data that was already downloaded; otherwise it is nil.
+func (c *appClient) binaryData(
+	dst string, filter *datapb.Filter, parallelDownloads uint, extMap map[string]string, incremental *exportSync,
+) ([]exportedFile, error) {
 	if err := c.ensureLoggedIn(); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := makeDestinationDirs(dst); err != nil {
-		return errors.Wrapf(err, "could not create destination directories")
+		return nil, errors.Wrapf(err, "could not create destination directories")
 	}
 
 	if parallelDownloads == 0 {
@@ -237,19 +422,21 @@ func (c *appClient) binaryData(dst string, filter *datapb.Filter, parallelDownlo
 		} else {
 			limit = parallelDownloads
 		}
-		if err := getMatchingBinaryIDs(ctx, c.dataClient, filter, ids, limit); err != nil {
+		if err := getMatchingBinaryIDs(ctx, c.dataClient, filter, ids, limit, incremental); err != nil {
 			errs <- err
 			cancel()
 		}
 	}()
 
 	// In parallel, read from ids and download the binary for each id in batches of defaultParallelDownloads.
+	var numFilesDownloaded atomic.Int32
+	var filesMu sync.Mutex
+	var files []exportedFile
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		var nextID *datapb.BinaryID
 		var done bool
-		var numFilesDownloaded atomic.Int32
 		var downloadWG sync.WaitGroup
 		for {
 			for i := uint(0); i < parallelDownloads; i++ {
@@ -271,12 +458,16 @@ func (c *appClient) binaryData(dst string, filter *datapb.Filter, parallelDownlo
 				downloadWG.Add(1)
 				go func(id *datapb.BinaryID) {
 					defer downloadWG.Done()
-					err := downloadBinary(ctx, c.dataClient, dst, id)
+					file, err := downloadBinary(ctx, c.dataClient, dst, id, extMap)
 					if err != nil {
 						errs <- err
 						cancel()
 						done = true
+						return
 					}
+					filesMu.Lock()
+					files = append(files, file)
+					filesMu.Unlock()
 					numFilesDownloaded.Add(1)
 					if numFilesDownloaded.Load()%logEveryN == 0 {
 						fmt.Fprintf(c.c.App.Writer, "downloaded %d files\n", numFilesDownloaded.Load())
@@ -295,16 +486,22 @@ func (c *appClient) binaryData(dst string, filter *datapb.Filter, parallelDownlo
 	wg.Wait()
 	close(errs)
 
+	// Downloads finish in any order, so sort the files to keep manifests of the same export comparable.
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
 	if err := <-errs; err != nil {
-		return err
+		if numFilesDownloaded.Load() > 0 {
+			return files, newPartialFailureError(errors.Wrapf(err, "only downloaded %d files", numFilesDownloaded.Load()))
+		}
+		return nil, err
 	}
 
-	return nil
+	return files, nil
 }
 
-// getMatchingIDs queries client for all BinaryData matching filter, and passes each of their ids into ids.
+// getMatchingIDs queries client for all BinaryData matching filter, and passes each of their ids into ids,
+// leaving out those that incremental says were already downloaded.
 func getMatchingBinaryIDs(ctx context.Context, client datapb.DataServiceClient, filter *datapb.Filter,
-	ids chan *datapb.BinaryID, limit uint,
+	ids chan *datapb.BinaryID, limit uint, incremental *exportSync,
 ) error {
 	var last string
 	defer close(ids)
@@ -333,16 +530,25 @@ func getMatchingBinaryIDs(ctx context.Context, client datapb.DataServiceClient,
 
 		for _, bd := range resp.GetData() {
 			md := bd.GetMetadata()
-			ids <- &datapb.BinaryID{
+			if incremental.skip(md.GetId()) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ids <- &datapb.BinaryID{
 				FileId:         md.GetId(),
 				OrganizationId: md.GetCaptureMetadata().GetOrganizationId(),
 				LocationId:     md.GetCaptureMetadata().GetLocationId(),
+			}:
 			}
 		}
 	}
 }
 
-func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst string, id *datapb.BinaryID) error {
+func downloadBinary(
+	ctx context.Context, client datapb.DataServiceClient, dst string, id *datapb.BinaryID, extMap map[string]string,
+) (exportedFile, error) {
 	var resp *datapb.BinaryDataByIDsResponse
 	var err error
 	for count := 0; count < maxRetryCount; count++ {
@@ -355,18 +561,18 @@ func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst st
 		}
 	}
 	if err != nil {
-		return errors.Wrapf(err, "received error from server")
+		return exportedFile{}, errors.Wrapf(err, "received error from server")
 	}
 	data := resp.GetData()
 
 	if len(data) != 1 {
-		return errors.Errorf("expected a single response, received %d", len(data))
+		return exportedFile{}, errors.Errorf("expected a single response, received %d", len(data))
 	}
 
 	datum := data[0]
 	mdJSONBytes, err := protojson.Marshal(datum.GetMetadata())
 	if err != nil {
-		return err
+		return exportedFile{}, err
 	}
 
 	timeRequested := datum.GetMetadata().GetTimeRequested().AsTime().Format(time.RFC3339Nano)
@@ -381,57 +587,112 @@ func downloadBinary(ctx context.Context, client datapb.DataServiceClient, dst st
 	//nolint:gosec
 	jsonFile, err := os.Create(filepath.Join(dst, metadataDir, fileName+".json"))
 	if err != nil {
-		return err
+		return exportedFile{}, err
 	}
 	if _, err := jsonFile.Write(mdJSONBytes); err != nil {
-		return err
+		return exportedFile{}, err
 	}
 
 	gzippedBytes := datum.GetBinary()
 	r, err := gzip.NewReader(bytes.NewBuffer(gzippedBytes))
 	if err != nil {
-		return err
+		return exportedFile{}, err
 	}
 
+	dataPath := filepath.Join(dst, dataDir, fileName+binaryFileExt(datum.GetMetadata(), extMap))
 	//nolint:gosec
-	dataFile, err := os.Create(filepath.Join(dst, dataDir, fileName+datum.GetMetadata().GetFileExt()))
+	dataFile, err := os.Create(dataPath)
 	if err != nil {
-		return errors.Wrapf(err, fmt.Sprintf("could not create file for datum %s", datum.GetMetadata().GetId()))
+		return exportedFile{}, errors.Wrapf(err, fmt.Sprintf("could not create file for datum %s", datum.GetMetadata().GetId()))
 	}
+	h := newFileHash()
 	//nolint:gosec
-	if _, err := io.Copy(dataFile, r); err != nil {
-		return err
+	if _, err := io.Copy(io.MultiWriter(dataFile, h), r); err != nil {
+		return exportedFile{}, err
 	}
 	if err := r.Close(); err != nil {
-		return err
+		return exportedFile{}, err
 	}
-	return nil
+	file := newExportedFile(dst, dataPath, datum.GetMetadata().GetId(), h)
+	file.Items = 1
+	if requested := datum.GetMetadata().GetTimeRequested(); requested != nil {
+		captured := requested.AsTime()
+		file.LatestCapture = &captured
+	}
+	return file, nil
 }
 
-// tabularData downloads binary data matching filter to dst.
-func (c *appClient) tabularData(dst string, filter *datapb.Filter) error {
+// tabularData downloads the tabular data matching filter to dst, and returns the data files that were
+// written. With gzip compression the data is compressed as it is downloaded, into data.ndjson.gz. If
+// columns is not empty, each record is cut down to just those fields, along with the times and metadata
+// index every record has. With --sync, incremental is not nil and the data is written to a new file, and
+// new metadata files, alongside the ones already in dst.
+func (c *appClient) tabularData(
+	dst string, filter *datapb.Filter, compression string, columns []string, incremental *exportSync,
+) ([]exportedFile, error) {
 	if err := c.ensureLoggedIn(); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := makeDestinationDirs(dst); err != nil {
-		return errors.Wrapf(err, "could not create destination directories")
+		return nil, errors.Wrapf(err, "could not create destination directories")
 	}
 
 	var err error
 	var resp *datapb.TabularDataByFilterResponse
 	// TODO(DATA-640): Support export in additional formats.
 	//nolint:gosec
-	dataFile, err := os.Create(filepath.Join(dst, dataDir, "data.ndjson"))
+	dataPath := filepath.Join(dst, dataDir, "data.ndjson")
+	if incremental != nil {
+		dataPath = filepath.Join(dst, dataDir, incremental.tabularFile)
+	}
+	if compression == compressionGzip {
+		dataPath += ".gz"
+	}
+	//nolint:gosec
+	dataFile, err := os.Create(dataPath)
 	if err != nil {
-		return errors.Wrapf(err, "could not create data file")
+		return nil, errors.Wrapf(err, "could not create data file")
+	}
+	//nolint:errcheck
+	defer dataFile.Close()
+	// The manifest hashes the file as it is on disk, so the hash is of the compressed data.
+	h := newFileHash()
+	var gz *gzip.Writer
+	var out io.Writer = io.MultiWriter(dataFile, h)
+	if compression == compressionGzip {
+		gz = gzip.NewWriter(out)
+		out = gz
+	}
+	w := bufio.NewWriter(out)
+	// finish flushes everything buffered, including the end of the gzip stream, to the data file.
+	finish := func() error {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if gz != nil {
+			return gz.Close()
+		}
+		return nil
 	}
-	w := bufio.NewWriter(dataFile)
 
 	fmt.Fprintf(c.c.App.Writer, "downloading..")
 	var last string
+	var numWritten int
+	checkedColumns := len(columns) == 0
 	mdIndexes := make(map[string]int)
 	mdIndex := 0
+	if incremental != nil {
+		mdIndex = incremental.nextMetadataIndex
+	}
+	var latestCapture *time.Time
+	// newFile returns the manifest entry for the data file as it has been written so far.
+	newFile := func() exportedFile {
+		file := newExportedFile(dst, dataPath, "", h)
+		file.Items = numWritten
+		file.LatestCapture = latestCapture
+		return file
+	}
 	for {
 		for count := 0; count < maxRetryCount; count++ {
 			resp, err = c.dataClient.TabularDataByFilter(context.Background(), &datapb.TabularDataByFilterRequest{
@@ -448,7 +709,11 @@ func (c *appClient) tabularData(dst string, filter *datapb.Filter) error {
 			}
 		}
 		if err != nil {
-			return err
+			if numWritten > 0 {
+				utils.UncheckedError(finish())
+				return []exportedFile{newFile()}, newPartialFailureError(errors.Wrapf(err, "only downloaded %d datapoints", numWritten))
+			}
+			return nil, err
 		}
 
 		last = resp.GetLast()
@@ -469,18 +734,18 @@ func (c *appClient) tabularData(dst string, filter *datapb.Filter) error {
 
 			mdJSONBytes, err := protojson.Marshal(md)
 			if err != nil {
-				return errors.Wrap(err, "could not marshal metadata")
+				return nil, errors.Wrap(err, "could not marshal metadata")
 			}
 			//nolint:gosec
 			mdFile, err := os.Create(filepath.Join(dst, metadataDir, strconv.Itoa(mdIndex)+".json"))
 			if err != nil {
-				return errors.Wrapf(err, fmt.Sprintf("could not create metadata file for metadata index %d", mdIndex))
+				return nil, errors.Wrapf(err, fmt.Sprintf("could not create metadata file for metadata index %d", mdIndex))
 			}
 			if _, err := mdFile.Write(mdJSONBytes); err != nil {
-				return errors.Wrapf(err, "could not write to metadata file %s", mdFile.Name())
+				return nil, errors.Wrapf(err, "could not write to metadata file %s", mdFile.Name())
 			}
 			if err := mdFile.Close(); err != nil {
-				return errors.Wrapf(err, "could not close metadata file %s", mdFile.Name())
+				return nil, errors.Wrapf(err, "could not close metadata file %s", mdFile.Name())
 			}
 			mdIndex++
 		}
@@ -493,26 +758,130 @@ func (c *appClient) tabularData(dst string, filter *datapb.Filter) error {
 				continue
 			}
 			m := d.AsMap()
+			if !checkedColumns {
+				if unknown := unknownColumns(m, columns); len(unknown) > 0 {
+					warningf(c.c.App.ErrWriter, "--%s names fields that are not in the data: %s. the first record has: %s",
+						DataFlagColumns, strings.Join(unknown, ", "), strings.Join(sortedKeys(m), ", "))
+				}
+				checkedColumns = true
+			}
+			if len(columns) > 0 {
+				m = projectColumns(m, columns)
+			}
 			m["TimeRequested"] = datum.GetTimeRequested()
 			m["TimeReceived"] = datum.GetTimeReceived()
 			m["MetadataIndex"] = localToGlobalMDIndex[int(datum.GetMetadataIndex())]
 			j, err := json.Marshal(m)
 			if err != nil {
-				return errors.Wrap(err, "could not marshal JSON response")
+				return nil, errors.Wrap(err, "could not marshal JSON response")
 			}
 			_, err = w.Write(append(j, []byte("\n")...))
 			if err != nil {
-				return errors.Wrapf(err, "could not write to file %s", dataFile.Name())
+				return nil, errors.Wrapf(err, "could not write to file %s", dataFile.Name())
+			}
+			numWritten++
+			if requested := datum.GetTimeRequested(); requested != nil {
+				if captured := requested.AsTime(); latestCapture == nil || captured.After(*latestCapture) {
+					latestCapture = &captured
+				}
 			}
 		}
 	}
 
 	fmt.Fprintf(c.c.App.Writer, "\n")
-	if err := w.Flush(); err != nil {
-		return errors.Wrapf(err, "could not flush writer for %s", dataFile.Name())
+	if err := finish(); err != nil {
+		return nil, errors.Wrapf(err, "could not flush writer for %s", dataFile.Name())
 	}
 
-	return nil
+	return []exportedFile{newFile()}, nil
+}
+
+// projectColumns returns the fields of record that are in columns.
+func projectColumns(record map[string]interface{}, columns []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		if v, ok := record[column]; ok {
+			projected[column] = v
+		}
+	}
+	return projected
+}
+
+// unknownColumns returns the columns that are not fields of record.
+func unknownColumns(record map[string]interface{}, columns []string) []string {
+	var unknown []string
+	for _, column := range columns {
+		if _, ok := record[column]; !ok {
+			unknown = append(unknown, column)
+		}
+	}
+	return unknown
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mimeTypeExtensions maps the mime types of commonly captured binary data to the extension
+// used when exported files don't have one of their own.
+var mimeTypeExtensions = map[string]string{
+	rdkutils.MimeTypeJPEG:     ".jpg",
+	rdkutils.MimeTypePNG:      ".png",
+	rdkutils.MimeTypeQOI:      ".qoi",
+	rdkutils.MimeTypePCD:      ".pcd",
+	"application/pcd":         ".pcd",
+	rdkutils.MimeTypeRawRGBA:  ".rgba",
+	rdkutils.MimeTypeRawDepth: ".dep",
+	"application/json":        ".json",
+	"text/plain":              ".txt",
+}
+
+// binaryFileExt returns the extension to give an exported binary file. The extension captured with the
+// file is preferred, followed by the user's ext-map, the known viam mime types, and then the system's mime
+// database. Unknown mime types get no extension.
+func binaryFileExt(md *datapb.BinaryMetadata, extMap map[string]string) string {
+	if ext := md.GetFileExt(); ext != "" {
+		return ext
+	}
+	mimeType := md.GetCaptureMetadata().GetMimeType()
+	if mimeType == "" {
+		return ""
+	}
+	if ext, ok := extMap[mimeType]; ok {
+		return ext
+	}
+	// lazy mime types share an extension with their eager equivalent.
+	mimeType = strings.TrimSuffix(mimeType, "+"+rdkutils.MimeTypeSuffixLazy)
+	if ext, ok := mimeTypeExtensions[mimeType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// parseExtMap parses ext-map flag values of the form "mime/type=.ext" into a map.
+func parseExtMap(values []string) (map[string]string, error) {
+	extMap := make(map[string]string, len(values))
+	for _, value := range values {
+		mimeType, ext, ok := strings.Cut(value, "=")
+		mimeType = strings.TrimSpace(mimeType)
+		ext = strings.TrimSpace(ext)
+		if !ok || mimeType == "" || ext == "" {
+			return nil, newValidationError(errors.Errorf("%s values must be of the form mime/type=.ext, got %q", DataFlagExtMap, value))
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		extMap[mimeType] = ext
+	}
+	return extMap, nil
 }
 
 func makeDestinationDirs(dst string) error {
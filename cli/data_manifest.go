@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	rconfig "go.viam.com/rdk/config"
+)
+
+// exportManifestFilename is the name of the manifest written alongside exported data.
+const exportManifestFilename = "export.json"
+
+// The compressions an exported data file may have.
+const (
+	compressionNone = "none"
+	compressionGzip = "gzip"
+)
+
+// exportManifest records what a 'data export' downloaded and how it was asked for, so that the
+// export can be checked and reproduced later.
+type exportManifest struct {
+	ExportedAt time.Time              `json:"exported_at"`
+	CLIVersion string                 `json:"cli_version"`
+	DataType   string                 `json:"data_type"`
+	Flags      map[string]interface{} `json:"flags"`
+	// Filter is the filter sent to the data service after the flags were resolved, such as relative
+	// times being turned into timestamps.
+	Filter json.RawMessage `json:"filter"`
+	Count  int             `json:"count"`
+	// Compression is how the data files are compressed. Binary data is never compressed, since most of
+	// it, such as images, is compressed already.
+	Compression string `json:"compression"`
+	// Complete is false when the export stopped partway through, in which case Files lists only the
+	// files that were downloaded.
+	Complete bool           `json:"complete"`
+	Files    []exportedFile `json:"files"`
+}
+
+// exportedFile is a single data file written by an export. Path is relative to the destination.
+type exportedFile struct {
+	ID     string `json:"id,omitempty"`
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	// Items is the number of data items in the file: one for binary data, and the number of datapoints
+	// for tabular data.
+	Items int `json:"items"`
+	// LatestCapture is when the most recently captured item in the file was captured, which is where
+	// 'data export --sync' picks up from.
+	LatestCapture *time.Time `json:"latest_capture,omitempty"`
+}
+
+// newExportManifest returns a manifest for an export of dataType data matching filter. It records the
+// value of every flag of the command, including defaults, so nothing about the export is implicit.
+func newExportManifest(
+	c *cli.Context, dataType string, filter *datapb.Filter, files []exportedFile, now time.Time,
+) (*exportManifest, error) {
+	filterJSON, err := protojson.Marshal(filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal filter")
+	}
+	flags := make(map[string]interface{})
+	if c.Command != nil {
+		for _, f := range c.Command.Flags {
+			name := f.Names()[0]
+			if _, ok := f.(*cli.StringSliceFlag); ok {
+				flags[name] = c.StringSlice(name)
+				continue
+			}
+			flags[name] = c.String(name)
+		}
+	}
+	if files == nil {
+		files = []exportedFile{}
+	}
+	var count int
+	for _, f := range files {
+		count += f.Items
+	}
+	return &exportManifest{
+		ExportedAt:  now.UTC(),
+		CLIVersion:  cliVersion(),
+		DataType:    dataType,
+		Flags:       flags,
+		Filter:      filterJSON,
+		Count:       count,
+		Compression: compressionNone,
+		Complete:    true,
+		Files:       files,
+	}, nil
+}
+
+// write writes the manifest to the export's destination directory.
+func (m *exportManifest) write(dst string) error {
+	manifestBytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(dst, exportManifestFilename)
+	//nolint:gosec
+	if err := os.WriteFile(manifestPath, append(manifestBytes, '\n'), 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", manifestPath)
+	}
+	return nil
+}
+
+// newExportedFile returns the manifest entry for the file at path, relative to dst, whose contents
+// were written through h.
+func newExportedFile(dst, path, id string, h hash.Hash) exportedFile {
+	rel, err := filepath.Rel(dst, path)
+	if err != nil {
+		rel = path
+	}
+	return exportedFile{ID: id, Path: filepath.ToSlash(rel), SHA256: hex.EncodeToString(h.Sum(nil))}
+}
+
+// newFileHash returns the hash used for files listed in an export manifest.
+func newFileHash() hash.Hash {
+	return sha256.New()
+}
+
+// cliVersion returns the version of the CLI, or (dev) for builds that are not releases.
+func cliVersion() string {
+	if rconfig.Version == "" {
+		return "(dev)"
+	}
+	return rconfig.Version
+}
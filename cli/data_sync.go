@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	datapb "go.viam.com/api/app/data/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// exportSync is what 'data export --sync' carries over from the export already in the destination, so
+// that only data captured since then is downloaded and added to it.
+type exportSync struct {
+	prior *exportManifest
+	// after is the latest capture time already downloaded, or zero if nothing to start after is known.
+	after time.Time
+	// downloaded holds the ids of the binary data already downloaded.
+	downloaded map[string]bool
+	// nextMetadataIndex is the first index not taken by a tabular metadata file.
+	nextMetadataIndex int
+	// tabularFile is the name of the data file new tabular data is written to, since the earlier ones are
+	// kept.
+	tabularFile string
+}
+
+// readExportManifest reads the manifest of the export in dst, returning nil if there is none.
+func readExportManifest(dst string) (*exportManifest, error) {
+	manifestPath := filepath.Join(dst, exportManifestFilename)
+	//nolint:gosec
+	manifestBytes, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var manifest exportManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", manifestPath)
+	}
+	return &manifest, nil
+}
+
+// newExportSync returns how to add to the export in dst, whose manifest is prior, with an export of
+// dataType data compressed with compression.
+//
+// Binary data is downloaded in parallel and in no particular order, so if the prior export did not
+// finish, data older than what it downloaded may be missing. In that case binary data is fetched from the
+// start of the filter again, skipping what was downloaded. Tabular data is one file per export that
+// cannot be picked up from partway through, so it is an error to sync an unfinished tabular export.
+func newExportSync(dst string, prior *exportManifest, dataType, compression string, now time.Time) (*exportSync, error) {
+	if prior.DataType != dataType {
+		return nil, newValidationError(errors.Errorf("cannot --sync %s data into %s, which has an export of %s data",
+			dataType, dst, prior.DataType))
+	}
+	if dataType == dataTypeTabular && prior.Compression != compression {
+		return nil, newValidationError(errors.Errorf("cannot --sync data with compression %s into %s, which has data with compression %s",
+			compression, dst, prior.Compression))
+	}
+	if dataType == dataTypeTabular && !prior.Complete {
+		return nil, newValidationError(errors.Errorf("the export in %s did not finish, so it cannot be synced. "+
+			"delete it and export again without --sync", dst))
+	}
+
+	incremental := &exportSync{
+		prior:       prior,
+		downloaded:  make(map[string]bool),
+		tabularFile: "data_" + now.UTC().Format("20060102T150405Z") + ".ndjson",
+	}
+	for _, f := range prior.Files {
+		if f.ID != "" {
+			incremental.downloaded[f.ID] = true
+		}
+		if prior.Complete && f.LatestCapture != nil && f.LatestCapture.After(incremental.after) {
+			incremental.after = *f.LatestCapture
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dst, metadataDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		index, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), ".json"))
+		if err == nil && index >= incremental.nextMetadataIndex {
+			incremental.nextMetadataIndex = index + 1
+		}
+	}
+	return incremental, nil
+}
+
+// applyTo narrows filter to the data captured after what was already downloaded.
+func (s *exportSync) applyTo(filter *datapb.Filter) {
+	if s.after.IsZero() {
+		return
+	}
+	// capture intervals include their start. Binary data captured at that time that was not downloaded is
+	// still fetched, since what was is skipped by id, but tabular data has no ids, so it starts just after.
+	start := s.after
+	if s.prior.DataType == dataTypeTabular {
+		start = start.Add(time.Nanosecond)
+	}
+	if filter.Interval == nil {
+		filter.Interval = &datapb.CaptureInterval{}
+	}
+	if filter.Interval.Start == nil || filter.Interval.Start.AsTime().Before(start) {
+		filter.Interval.Start = timestamppb.New(start)
+	}
+}
+
+// skip returns whether the binary data with the given id was already downloaded. A nil sync skips nothing.
+func (s *exportSync) skip(id string) bool {
+	return s != nil && s.downloaded[id]
+}
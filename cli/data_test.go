@@ -0,0 +1,420 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBinaryFileExt(t *testing.T) {
+	withMimeType := func(mimeType string) *datapb.BinaryMetadata {
+		return &datapb.BinaryMetadata{CaptureMetadata: &datapb.CaptureMetadata{MimeType: mimeType}}
+	}
+	extMap, err := parseExtMap([]string{"application/x-thermal=.thermal", "image/jpeg = jpeg"})
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		md       *datapb.BinaryMetadata
+		expected string
+	}{
+		{withMimeType("image/jpeg"), ".jpeg"}, // overridden by ext-map
+		{withMimeType("image/png"), ".png"},
+		{withMimeType("pointcloud/pcd"), ".pcd"},
+		{withMimeType("application/pcd"), ".pcd"},
+		{withMimeType("image/vnd.viam.rgba+lazy"), ".rgba"},
+		{withMimeType("image/vnd.viam.dep"), ".dep"},
+		{withMimeType("application/x-thermal"), ".thermal"},
+		{withMimeType("application/x-unknown-viam-type"), ""},
+		{withMimeType(""), ""},
+		{&datapb.BinaryMetadata{FileExt: ".jpeg", CaptureMetadata: &datapb.CaptureMetadata{MimeType: "image/png"}}, ".jpeg"},
+	} {
+		test.That(t, binaryFileExt(tc.md, extMap), test.ShouldEqual, tc.expected)
+	}
+	test.That(t, binaryFileExt(withMimeType("image/jpeg"), nil), test.ShouldEqual, ".jpg")
+}
+
+func TestParseExtMap(t *testing.T) {
+	extMap, err := parseExtMap(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, extMap, test.ShouldBeEmpty)
+
+	_, err = parseExtMap([]string{"image/jpeg"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = parseExtMap([]string{"=.jpg"})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPrintDataCount(t *testing.T) {
+	newClient := func(dataClient datapb.DataServiceClient) (*appClient, *bytes.Buffer) {
+		var out bytes.Buffer
+		cCtx := cli.NewContext(&cli.App{Writer: &out, ErrWriter: &bytes.Buffer{}}, nil, nil)
+		return &appClient{c: cCtx, conf: &config{}, client: &injectAppServiceClient{}, dataClient: dataClient}, &out
+	}
+
+	dataClient := &injectDataClient{ids: []string{"a", "b"}, count: 1234, totalSizeBytes: 5_600_000_000}
+	client, out := newClient(dataClient)
+	test.That(t, client.printDataCount(dataTypeBinary, &datapb.Filter{}), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldEqual, "1234 binary data items match the filters, about 5.6 GB in total\n")
+	test.That(t, dataClient.fetched, test.ShouldBeFalse)
+
+	dataClient = &injectDataClient{count: 7}
+	client, out = newClient(dataClient)
+	test.That(t, client.printDataCount(dataTypeTabular, &datapb.Filter{}), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldEqual, "7 tabular data items match the filters\n")
+	test.That(t, dataClient.fetched, test.ShouldBeFalse)
+
+	client, _ = newClient(&injectDataClient{})
+	err := client.printDataCount("video", &datapb.Filter{})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+}
+
+func TestExportManifest(t *testing.T) {
+	dst := t.TempDir()
+	command := &cli.Command{Flags: []cli.Flag{
+		&cli.PathFlag{Name: DataFlagDestination},
+		&cli.StringFlag{Name: DataFlagDataType},
+		&cli.StringSliceFlag{Name: DataFlagOrgIDs},
+		&cli.StringFlag{Name: DataFlagComponentName},
+		&cli.StringFlag{Name: DataFlagStart},
+		&cli.UintFlag{Name: DataFlagParallelDownloads, Value: 100},
+	}}
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	for _, f := range command.Flags {
+		test.That(t, f.Apply(flags), test.ShouldBeNil)
+	}
+	test.That(t, flags.Parse([]string{
+		"--destination", dst, "--data-type", "binary", "--org-ids", "org1", "--org-ids", "org2",
+		"--component-name", "camera", "--start", "2023-06-01T00:00:00Z",
+	}), test.ShouldBeNil)
+	cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &bytes.Buffer{}}, flags, nil)
+	cCtx.Command = command
+
+	filter, err := createDataFilter(cCtx)
+	test.That(t, err, test.ShouldBeNil)
+	client := &appClient{c: cCtx, conf: &config{}, client: &injectAppServiceClient{}, dataClient: &injectDataClient{ids: []string{"b", "a"}}}
+	files, err := client.binaryData(dst, filter, 1, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	exportedAt := time.Date(2023, 6, 2, 12, 0, 0, 0, time.UTC)
+	manifest, err := newExportManifest(cCtx, dataTypeBinary, filter, files, exportedAt)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, manifest.write(dst), test.ShouldBeNil)
+
+	//nolint:gosec
+	manifestBytes, err := os.ReadFile(filepath.Join(dst, exportManifestFilename))
+	test.That(t, err, test.ShouldBeNil)
+	var written struct {
+		ExportedAt time.Time              `json:"exported_at"`
+		CLIVersion string                 `json:"cli_version"`
+		DataType   string                 `json:"data_type"`
+		Flags      map[string]interface{} `json:"flags"`
+		Filter     struct {
+			OrganizationIDs []string `json:"organizationIds"`
+			ComponentName   string   `json:"componentName"`
+			Interval        struct {
+				Start time.Time `json:"start"`
+			} `json:"interval"`
+		} `json:"filter"`
+		Count       int            `json:"count"`
+		Compression string         `json:"compression"`
+		Complete    bool           `json:"complete"`
+		Files       []exportedFile `json:"files"`
+	}
+	test.That(t, json.Unmarshal(manifestBytes, &written), test.ShouldBeNil)
+	test.That(t, written.ExportedAt, test.ShouldEqual, exportedAt)
+	test.That(t, written.CLIVersion, test.ShouldEqual, cliVersion())
+	test.That(t, written.DataType, test.ShouldEqual, dataTypeBinary)
+	test.That(t, written.Flags, test.ShouldResemble, map[string]interface{}{
+		DataFlagDestination:       dst,
+		DataFlagDataType:          "binary",
+		DataFlagOrgIDs:            []interface{}{"org1", "org2"},
+		DataFlagComponentName:     "camera",
+		DataFlagStart:             "2023-06-01T00:00:00Z",
+		DataFlagParallelDownloads: "100",
+	})
+	test.That(t, written.Filter.OrganizationIDs, test.ShouldResemble, []string{"org1", "org2"})
+	test.That(t, written.Filter.ComponentName, test.ShouldEqual, "camera")
+	test.That(t, written.Filter.Interval.Start, test.ShouldEqual, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC))
+	test.That(t, written.Count, test.ShouldEqual, 2)
+	test.That(t, written.Compression, test.ShouldEqual, compressionNone)
+	test.That(t, written.Complete, test.ShouldBeTrue)
+
+	// Files are listed in a stable order, with hashes of what was written to disk.
+	test.That(t, written.Files, test.ShouldHaveLength, 2)
+	for i, id := range []string{"a", "b"} {
+		file := written.Files[i]
+		test.That(t, file.ID, test.ShouldEqual, id)
+		test.That(t, file.Items, test.ShouldEqual, 1)
+		//nolint:gosec
+		contents, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(file.Path)))
+		test.That(t, err, test.ShouldBeNil)
+		sum := sha256.Sum256(contents)
+		test.That(t, file.SHA256, test.ShouldEqual, hex.EncodeToString(sum[:]))
+	}
+}
+
+func TestDataExportSync(t *testing.T) {
+	dst := t.TempDir()
+	cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &bytes.Buffer{}}, nil, nil)
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	dataClient := &injectDataClient{
+		ids:      []string{"a", "b"},
+		captured: map[string]time.Time{"a": day, "b": day.Add(time.Hour)},
+	}
+	client := &appClient{c: cCtx, conf: &config{}, client: &injectAppServiceClient{}, dataClient: dataClient}
+
+	files, err := client.binaryData(dst, &datapb.Filter{}, 1, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	manifest, err := newExportManifest(cCtx, dataTypeBinary, &datapb.Filter{}, files, day)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, manifest.write(dst), test.ShouldBeNil)
+
+	// The second run only downloads what was captured since the first, plus what was captured at the
+	// same time as the latest data but not downloaded.
+	dataClient.ids = []string{"a", "b", "c", "d"}
+	dataClient.captured["c"] = day.Add(time.Hour)
+	dataClient.captured["d"] = day.Add(2 * time.Hour)
+	prior, err := readExportManifest(dst)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, prior, test.ShouldNotBeNil)
+	incremental, err := newExportSync(dst, prior, dataTypeBinary, compressionNone, day.Add(3*time.Hour))
+	test.That(t, err, test.ShouldBeNil)
+	filter := &datapb.Filter{}
+	incremental.applyTo(filter)
+	test.That(t, filter.GetInterval().GetStart().AsTime(), test.ShouldEqual, day.Add(time.Hour))
+
+	files, err = client.binaryData(dst, filter, 1, nil, incremental)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldHaveLength, 2)
+	test.That(t, files[0].ID, test.ShouldEqual, "c")
+	test.That(t, files[1].ID, test.ShouldEqual, "d")
+	test.That(t, *files[1].LatestCapture, test.ShouldEqual, day.Add(2*time.Hour))
+
+	t.Run("unfinished export", func(t *testing.T) {
+		unfinished := *prior
+		unfinished.Complete = false
+		incremental, err := newExportSync(dst, &unfinished, dataTypeBinary, compressionNone, day)
+		test.That(t, err, test.ShouldBeNil)
+		filter := &datapb.Filter{}
+		incremental.applyTo(filter)
+		test.That(t, filter.GetInterval(), test.ShouldBeNil)
+		test.That(t, incremental.skip("a"), test.ShouldBeTrue)
+		test.That(t, incremental.skip("d"), test.ShouldBeFalse)
+
+		_, err = newExportSync(dst, &exportManifest{DataType: dataTypeTabular, Compression: compressionNone}, dataTypeTabular,
+			compressionNone, day)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+	})
+
+	t.Run("mismatched export", func(t *testing.T) {
+		_, err := newExportSync(dst, prior, dataTypeTabular, compressionNone, day)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "which has an export of binary data")
+
+		tabular := &exportManifest{DataType: dataTypeTabular, Compression: compressionNone, Complete: true}
+		_, err = newExportSync(dst, tabular, dataTypeTabular, compressionGzip, day)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "compression")
+	})
+
+	t.Run("no prior export", func(t *testing.T) {
+		prior, err := readExportManifest(t.TempDir())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, prior, test.ShouldBeNil)
+	})
+}
+
+func TestTabularDataCompress(t *testing.T) {
+	records := []map[string]interface{}{{"celsius": 21.5}, {"celsius": 22.0}, {"celsius": 22.5}}
+	cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &bytes.Buffer{}}, nil, nil)
+	client := &appClient{c: cCtx, conf: &config{}, client: &injectAppServiceClient{}, dataClient: &injectDataClient{tabular: records}}
+
+	readRecords := func(t *testing.T, r io.Reader) []float64 {
+		t.Helper()
+		var celsius []float64
+		decoder := json.NewDecoder(r)
+		for decoder.More() {
+			var record struct {
+				Celsius       float64 `json:"celsius"`
+				MetadataIndex int     `json:"MetadataIndex"`
+			}
+			test.That(t, decoder.Decode(&record), test.ShouldBeNil)
+			test.That(t, record.MetadataIndex, test.ShouldEqual, 0)
+			celsius = append(celsius, record.Celsius)
+		}
+		return celsius
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		dst := t.TempDir()
+		files, err := client.tabularData(dst, &datapb.Filter{}, compressionGzip, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, files, test.ShouldHaveLength, 1)
+		test.That(t, files[0].Path, test.ShouldEqual, "data/data.ndjson.gz")
+		test.That(t, files[0].Items, test.ShouldEqual, 3)
+
+		//nolint:gosec
+		compressed, err := os.ReadFile(filepath.Join(dst, dataDir, "data.ndjson.gz"))
+		test.That(t, err, test.ShouldBeNil)
+		sum := sha256.Sum256(compressed)
+		test.That(t, files[0].SHA256, test.ShouldEqual, hex.EncodeToString(sum[:]))
+		gz, err := gzip.NewReader(bytes.NewReader(compressed))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readRecords(t, gz), test.ShouldResemble, []float64{21.5, 22.0, 22.5})
+		test.That(t, gz.Close(), test.ShouldBeNil)
+		_, err = os.Stat(filepath.Join(dst, dataDir, "data.ndjson"))
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	})
+
+	t.Run("uncompressed", func(t *testing.T) {
+		dst := t.TempDir()
+		files, err := client.tabularData(dst, &datapb.Filter{}, compressionNone, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, files[0].Path, test.ShouldEqual, "data/data.ndjson")
+		//nolint:gosec
+		f, err := os.Open(filepath.Join(dst, dataDir, "data.ndjson"))
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		test.That(t, readRecords(t, f), test.ShouldResemble, []float64{21.5, 22.0, 22.5})
+	})
+}
+
+func TestTabularDataColumns(t *testing.T) {
+	records := []map[string]interface{}{
+		{"celsius": 21.5, "humidity": 40.0, "raw": "0x1f"},
+		{"celsius": 22.0, "humidity": 41.0, "raw": "0x20"},
+	}
+	errOut := &bytes.Buffer{}
+	cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: errOut}, nil, nil)
+	client := &appClient{c: cCtx, conf: &config{}, client: &injectAppServiceClient{}, dataClient: &injectDataClient{tabular: records}}
+
+	readRecords := func(t *testing.T, dst string) []map[string]interface{} {
+		t.Helper()
+		//nolint:gosec
+		f, err := os.Open(filepath.Join(dst, dataDir, "data.ndjson"))
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		var read []map[string]interface{}
+		decoder := json.NewDecoder(f)
+		for decoder.More() {
+			var record map[string]interface{}
+			test.That(t, decoder.Decode(&record), test.ShouldBeNil)
+			read = append(read, record)
+		}
+		return read
+	}
+
+	t.Run("projection", func(t *testing.T) {
+		errOut.Reset()
+		dst := t.TempDir()
+		_, err := client.tabularData(dst, &datapb.Filter{}, compressionNone, []string{"celsius", "humidity"}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		read := readRecords(t, dst)
+		test.That(t, read, test.ShouldHaveLength, 2)
+		for i, record := range read {
+			test.That(t, record["celsius"], test.ShouldEqual, records[i]["celsius"])
+			test.That(t, record["humidity"], test.ShouldEqual, records[i]["humidity"])
+			test.That(t, record, test.ShouldNotContainKey, "raw")
+			test.That(t, record, test.ShouldContainKey, "MetadataIndex")
+		}
+		test.That(t, errOut.String(), test.ShouldBeEmpty)
+	})
+
+	t.Run("unknown column", func(t *testing.T) {
+		errOut.Reset()
+		dst := t.TempDir()
+		_, err := client.tabularData(dst, &datapb.Filter{}, compressionNone, []string{"celsius", "pressure"}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		read := readRecords(t, dst)
+		test.That(t, read[0]["celsius"], test.ShouldEqual, 21.5)
+		test.That(t, read[0], test.ShouldNotContainKey, "humidity")
+		// the warning is given once, not for every record
+		test.That(t, strings.Count(errOut.String(), "Warning"), test.ShouldEqual, 1)
+		test.That(t, errOut.String(), test.ShouldContainSubstring,
+			"--columns names fields that are not in the data: pressure. the first record has: celsius, humidity, raw")
+	})
+}
+
+func TestFormatBytes(t *testing.T) {
+	test.That(t, formatBytes(0), test.ShouldEqual, "0 B")
+	test.That(t, formatBytes(999), test.ShouldEqual, "999 B")
+	test.That(t, formatBytes(1000), test.ShouldEqual, "1.0 kB")
+	test.That(t, formatBytes(1_500_000), test.ShouldEqual, "1.5 MB")
+	test.That(t, formatBytes(2_000_000_000_000), test.ShouldEqual, "2.0 TB")
+}
+
+func TestParseCaptureInterval(t *testing.T) {
+	now := time.Date(2023, 6, 10, 12, 0, 0, 0, time.UTC)
+	at := func(s string) *timestamppb.Timestamp {
+		t.Helper()
+		ts, err := time.Parse(time.RFC3339, s)
+		test.That(t, err, test.ShouldBeNil)
+		return timestamppb.New(ts)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		start, end string
+		expected   *datapb.CaptureInterval
+	}{
+		{"neither", "", "", nil},
+		{"absolute", "2023-06-01T00:00:00Z", "2023-06-02T00:00:00Z", &datapb.CaptureInterval{
+			Start: at("2023-06-01T00:00:00Z"), End: at("2023-06-02T00:00:00Z"),
+		}},
+		{"absolute start only", "2023-06-01T00:00:00Z", "", &datapb.CaptureInterval{Start: at("2023-06-01T00:00:00Z")}},
+		{"absolute end only", "", "2023-06-02T00:00:00Z", &datapb.CaptureInterval{End: at("2023-06-02T00:00:00Z")}},
+		{"relative start ends now", "-24h", "", &datapb.CaptureInterval{
+			Start: at("2023-06-09T12:00:00Z"), End: at("2023-06-10T12:00:00Z"),
+		}},
+		{"relative days and weeks", "-2w", "-7d", &datapb.CaptureInterval{
+			Start: at("2023-05-27T12:00:00Z"), End: at("2023-06-03T12:00:00Z"),
+		}},
+		{"relative start, absolute end", "-1.5d", "2023-06-10T00:00:00Z", &datapb.CaptureInterval{
+			Start: at("2023-06-09T00:00:00Z"), End: at("2023-06-10T00:00:00Z"),
+		}},
+		{"absolute start, relative end", "2023-06-01T00:00:00Z", "-90m", &datapb.CaptureInterval{
+			Start: at("2023-06-01T00:00:00Z"), End: at("2023-06-10T10:30:00Z"),
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			interval, err := parseCaptureInterval(tc.start, tc.end, now)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, interval, test.ShouldResembleProto, tc.expected)
+		})
+	}
+
+	for _, tc := range []struct {
+		name       string
+		start, end string
+		expected   string
+	}{
+		{"start after end", "2023-06-02T00:00:00Z", "2023-06-01T00:00:00Z", "start 2023-06-02T00:00:00Z is after end 2023-06-01T00:00:00Z"},
+		{"relative start after relative end", "-1d", "-2d", "start 2023-06-09T12:00:00Z is after end 2023-06-08T12:00:00Z"},
+		{"start in the future", "2023-07-01T00:00:00Z", "-1h", "is after end"},
+		{"not a timestamp", "yesterday", "", "could not parse start flag"},
+		{"not a duration", "-3y", "", `"-3y" is not a valid duration`},
+		{"bad days", "", "-xd", "could not parse end flag"},
+		{"double negative", "--1h", "", "must not be negated twice"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseCaptureInterval(tc.start, tc.end, now)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fullstorydev/grpcurl"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/pkg/errors"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+// describeRobotPartRPC prints the JSON shape of the request and response of svcMethod, as found through
+// the part's reflection service, without invoking the method. The request shape is what --data expects.
+func describeRobotPartRPC(ctx context.Context, w io.Writer, conn googlegrpc.ClientConnInterface, svcMethod string) error {
+	refClient := grpcreflect.NewClientV1Alpha(metadata.NewOutgoingContext(ctx, nil), reflectpb.NewServerReflectionClient(conn))
+	defer refClient.Reset()
+	descSource := grpcurl.DescriptorSourceFromServer(ctx, refClient)
+
+	method, err := findMethod(descSource, svcMethod)
+	if err != nil {
+		if errors.Is(err, grpcurl.ErrReflectionNotSupported) {
+			return errors.Errorf("schema unavailable: the robot part does not serve gRPC reflection, so %s cannot be described", svcMethod)
+		}
+		return err
+	}
+
+	_, formatter, err := grpcurl.RequestParserAndFormatter(
+		grpcurl.Format("json"),
+		descSource,
+		strings.NewReader(""),
+		grpcurl.FormatOptions{EmitJSONDefaultFields: true})
+	if err != nil {
+		return err
+	}
+	request, err := formatter(grpcurl.MakeTemplate(method.GetInputType()))
+	if err != nil {
+		return err
+	}
+	response, err := formatter(grpcurl.MakeTemplate(method.GetOutputType()))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s\n\n", method.GetFullyQualifiedName())
+	fmt.Fprintf(w, "request %s%s:\n%s\n\n", method.GetInputType().GetFullyQualifiedName(), streamSuffix(method.IsClientStreaming()), request)
+	fmt.Fprintf(w, "response %s%s:\n%s\n", method.GetOutputType().GetFullyQualifiedName(), streamSuffix(method.IsServerStreaming()), response)
+	return nil
+}
+
+// findMethod returns the descriptor of svcMethod, which is a fully qualified service name followed by a
+// method name, separated by a dot or a slash.
+func findMethod(descSource grpcurl.DescriptorSource, svcMethod string) (*desc.MethodDescriptor, error) {
+	pos := strings.LastIndexAny(svcMethod, "./")
+	if pos <= 0 || pos == len(svcMethod)-1 {
+		return nil, newValidationError(errors.Errorf("%q must be a fully qualified service name and a method, such as %s",
+			svcMethod, "viam.robot.v1.RobotService.GetStatus"))
+	}
+	svcName, methodName := svcMethod[:pos], svcMethod[pos+1:]
+
+	dsc, err := descSource.FindSymbol(svcName)
+	if err != nil {
+		if errors.Is(err, grpcurl.ErrReflectionNotSupported) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "could not find service %q", svcName)
+	}
+	svc, ok := dsc.(*desc.ServiceDescriptor)
+	if !ok {
+		return nil, errors.Errorf("%q is not a service", svcName)
+	}
+	method := svc.FindMethodByName(methodName)
+	if method == nil {
+		return nil, errors.Errorf("service %q has no method %q", svcName, methodName)
+	}
+	return method, nil
+}
+
+// streamSuffix marks streamed messages in a method description.
+func streamSuffix(stream bool) string {
+	if stream {
+		return " (stream)"
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	pb "go.viam.com/api/component/sensor/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newSensorServiceConn serves an unimplemented sensor service, along with the reflection service if
+// withReflection is set, and returns a connection to it.
+func newSensorServiceConn(t *testing.T, withReflection bool) *googlegrpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := googlegrpc.NewServer()
+	pb.RegisterSensorServiceServer(server, &pb.UnimplementedSensorServiceServer{})
+	if withReflection {
+		reflection.Register(server)
+	}
+	go func() {
+		utils.UncheckedError(server.Serve(listener))
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := googlegrpc.DialContext(context.Background(), "bufnet",
+		googlegrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, conn.Close(), test.ShouldBeNil) })
+	return conn
+}
+
+func TestDescribeRobotPartRPC(t *testing.T) {
+	t.Run("with reflection", func(t *testing.T) {
+		conn := newSensorServiceConn(t, true)
+		var out bytes.Buffer
+		err := describeRobotPartRPC(context.Background(), &out, conn, "viam.component.sensor.v1.SensorService.GetReadings")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldStartWith, "viam.component.sensor.v1.SensorService.GetReadings\n")
+		test.That(t, out.String(), test.ShouldContainSubstring, "request viam.component.sensor.v1.GetReadingsRequest:\n{\n  \"name\": \"\",")
+		test.That(t, out.String(), test.ShouldContainSubstring, "response viam.component.sensor.v1.GetReadingsResponse:\n{\n  \"readings\": {")
+		test.That(t, out.String(), test.ShouldNotContainSubstring, "(stream)")
+
+		// a slash separates the service and method too, as in grpc's own method names.
+		out.Reset()
+		err = describeRobotPartRPC(context.Background(), &out, conn, "viam.component.sensor.v1.SensorService/GetReadings")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldContainSubstring, "GetReadingsRequest")
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		conn := newSensorServiceConn(t, true)
+		var out bytes.Buffer
+		err := describeRobotPartRPC(context.Background(), &out, conn, "viam.component.sensor.v1.SensorService.Frobnicate")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `has no method "Frobnicate"`)
+
+		err = describeRobotPartRPC(context.Background(), &out, conn, "viam.component.sensor.v1.NoService.GetReadings")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "could not find service")
+
+		err = describeRobotPartRPC(context.Background(), &out, conn, "GetReadings")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+		test.That(t, out.String(), test.ShouldBeEmpty)
+	})
+
+	t.Run("without reflection", func(t *testing.T) {
+		conn := newSensorServiceConn(t, false)
+		var out bytes.Buffer
+		err := describeRobotPartRPC(context.Background(), &out, conn, "viam.component.sensor.v1.SensorService.GetReadings")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldStartWith, "schema unavailable")
+		test.That(t, out.String(), test.ShouldBeEmpty)
+	})
+}
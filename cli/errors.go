@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes returned by the viam CLI so that scripts can tell different kinds of failures apart.
+const (
+	// ExitCodeSuccess means the command completed successfully.
+	ExitCodeSuccess = 0
+	// ExitCodeFailure means the command failed without completing any of its work.
+	ExitCodeFailure = 1
+	// ExitCodePartialFailure means some, but not all, of the command's work completed. For example,
+	// some files were exported before a download failed, or logs were printed for only some parts.
+	ExitCodePartialFailure = 2
+	// ExitCodeAuthError means the command failed because the user is not logged in or their
+	// credentials were rejected.
+	ExitCodeAuthError = 3
+)
+
+// errorCategory classifies why a CLI action failed.
+type errorCategory string
+
+const (
+	// categoryUnknown is for errors that were not classified, such as local I/O errors.
+	categoryUnknown errorCategory = "unknown"
+	// categoryValidation is for bad flags, arguments, or input files, detected before doing any work.
+	categoryValidation errorCategory = "validation"
+	// categoryAuth is for errors caused by the user not being logged in or their credentials being rejected.
+	categoryAuth errorCategory = "auth"
+	// categoryAPI is for errors returned by a Viam API.
+	categoryAPI errorCategory = "api"
+	// categoryPartialFailure is for errors that happened after some of a command's work completed.
+	categoryPartialFailure errorCategory = "partial failure"
+)
+
+// exitCode returns the code the CLI exits with for errors of the category.
+func (c errorCategory) exitCode() int {
+	switch c {
+	case categoryAuth:
+		return ExitCodeAuthError
+	case categoryPartialFailure:
+		return ExitCodePartialFailure
+	case categoryUnknown, categoryValidation, categoryAPI:
+		return ExitCodeFailure
+	default:
+		return ExitCodeFailure
+	}
+}
+
+// cliError is an error returned by a CLI action along with the category of the failure.
+type cliError struct {
+	err      error
+	category errorCategory
+}
+
+func (e *cliError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the exit code the CLI should exit with.
+func (e *cliError) ExitCode() int {
+	return e.category.exitCode()
+}
+
+// newValidationError marks err as being caused by bad flags, arguments, or input files.
+func newValidationError(err error) error {
+	return &cliError{err: err, category: categoryValidation}
+}
+
+// newPartialFailureError marks err as having happened after some of a command's work completed.
+func newPartialFailureError(err error) error {
+	return &cliError{err: err, category: categoryPartialFailure}
+}
+
+// newAuthError marks err as being caused by missing or rejected credentials.
+func newAuthError(err error) error {
+	return &cliError{err: err, category: categoryAuth}
+}
+
+// errorCategoryOf returns the category of an error returned by an action. Errors that were not
+// explicitly categorized are classified by their gRPC status, if they have one.
+func errorCategoryOf(err error) errorCategory {
+	var cliErr *cliError
+	if errors.As(err, &cliErr) {
+		return cliErr.category
+	}
+	if s, ok := status.FromError(errors.Cause(err)); ok && s.Code() != codes.OK {
+		if s.Code() == codes.Unauthenticated || s.Code() == codes.PermissionDenied {
+			return categoryAuth
+		}
+		return categoryAPI
+	}
+	return categoryUnknown
+}
+
+// ExitCode returns the code the CLI should exit with after an action returned err.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+	return errorCategoryOf(err).exitCode()
+}
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	datapb "go.viam.com/api/app/data/v1"
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// injectAppServiceClient is a logged in app client; none of its methods are called by these tests.
+type injectAppServiceClient struct {
+	apppb.AppServiceClient
+}
+
+// injectDataClient is a data service client that serves binary data with the given ids, failing
+// to download any of them that have an error in downloadErrs. Count only requests are answered with
+// count and totalSizeBytes; fetched records whether any data was requested. It serves tabular as a
+// single page of tabular data. Binary data with a time in captured is served only if it was captured
+// within the filter's interval.
+type injectDataClient struct {
+	datapb.DataServiceClient
+	ids            []string
+	captured       map[string]time.Time
+	tabular        []map[string]interface{}
+	filterErr      error
+	downloadErrs   map[string]error
+	count          uint64
+	totalSizeBytes uint64
+	fetched        bool
+}
+
+func (i *injectDataClient) BinaryDataByFilter(
+	ctx context.Context, in *datapb.BinaryDataByFilterRequest, opts ...grpc.CallOption,
+) (*datapb.BinaryDataByFilterResponse, error) {
+	if i.filterErr != nil {
+		return nil, i.filterErr
+	}
+	if in.CountOnly {
+		return &datapb.BinaryDataByFilterResponse{Count: i.count, TotalSizeBytes: i.totalSizeBytes}, nil
+	}
+	i.fetched = true
+	if in.DataRequest.Last != "" {
+		return &datapb.BinaryDataByFilterResponse{}, nil
+	}
+	resp := &datapb.BinaryDataByFilterResponse{Last: "last"}
+	start := in.DataRequest.GetFilter().GetInterval().GetStart()
+	for _, id := range i.ids {
+		if captured, ok := i.captured[id]; ok && start != nil && captured.Before(start.AsTime()) {
+			continue
+		}
+		resp.Data = append(resp.Data, &datapb.BinaryData{Metadata: i.binaryMetadata(id)})
+	}
+	return resp, nil
+}
+
+func (i *injectDataClient) TabularDataByFilter(
+	ctx context.Context, in *datapb.TabularDataByFilterRequest, opts ...grpc.CallOption,
+) (*datapb.TabularDataByFilterResponse, error) {
+	if i.filterErr != nil {
+		return nil, i.filterErr
+	}
+	if in.CountOnly {
+		return &datapb.TabularDataByFilterResponse{Count: i.count, TotalSizeBytes: i.totalSizeBytes}, nil
+	}
+	i.fetched = true
+	if in.DataRequest.Last != "" {
+		return &datapb.TabularDataByFilterResponse{}, nil
+	}
+	resp := &datapb.TabularDataByFilterResponse{Last: "last", Metadata: []*datapb.CaptureMetadata{{ComponentName: "sensor"}}}
+	for _, m := range i.tabular {
+		data, err := structpb.NewStruct(m)
+		if err != nil {
+			return nil, err
+		}
+		resp.Data = append(resp.Data, &datapb.TabularData{Data: data})
+	}
+	return resp, nil
+}
+
+func (i *injectDataClient) BinaryDataByIDs(
+	ctx context.Context, in *datapb.BinaryDataByIDsRequest, opts ...grpc.CallOption,
+) (*datapb.BinaryDataByIDsResponse, error) {
+	id := in.BinaryIds[0].FileId
+	if err := i.downloadErrs[id]; err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(id)); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return &datapb.BinaryDataByIDsResponse{
+		Data: []*datapb.BinaryData{{Binary: buf.Bytes(), Metadata: i.binaryMetadata(id)}},
+	}, nil
+}
+
+func (i *injectDataClient) binaryMetadata(id string) *datapb.BinaryMetadata {
+	md := &datapb.BinaryMetadata{Id: id, FileExt: ".txt"}
+	if captured, ok := i.captured[id]; ok {
+		md.TimeRequested = timestamppb.New(captured)
+	}
+	return md
+}
+
+func TestExitCodes(t *testing.T) {
+	newClient := func(dataClient datapb.DataServiceClient) *appClient {
+		cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &bytes.Buffer{}}, nil, nil)
+		return &appClient{c: cCtx, conf: &config{}, dataClient: dataClient}
+	}
+	errDownload := errors.New("download failed")
+
+	t.Run("success", func(t *testing.T) {
+		client := newClient(&injectDataClient{ids: []string{"a", "b", "c"}})
+		client.client = &injectAppServiceClient{}
+		_, err := client.binaryData(t.TempDir(), &datapb.Filter{}, 1, nil, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ExitCode(err), test.ShouldEqual, ExitCodeSuccess)
+	})
+
+	t.Run("total failure", func(t *testing.T) {
+		client := newClient(&injectDataClient{ids: []string{"a", "b"}, downloadErrs: map[string]error{"a": errDownload}})
+		client.client = &injectAppServiceClient{}
+		_, err := client.binaryData(t.TempDir(), &datapb.Filter{}, 1, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, ExitCode(err), test.ShouldEqual, ExitCodeFailure)
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		client := newClient(&injectDataClient{ids: []string{"a", "b", "c"}, downloadErrs: map[string]error{"b": errDownload}})
+		client.client = &injectAppServiceClient{}
+		_, err := client.binaryData(t.TempDir(), &datapb.Filter{}, 1, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, errDownload.Error())
+		test.That(t, ExitCode(err), test.ShouldEqual, ExitCodePartialFailure)
+		test.That(t, ExitCode(errors.Wrap(err, "could not export")), test.ShouldEqual, ExitCodePartialFailure)
+	})
+
+	t.Run("not logged in", func(t *testing.T) {
+		client := newClient(&injectDataClient{})
+		_, err := client.binaryData(t.TempDir(), &datapb.Filter{}, 1, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, ExitCode(err), test.ShouldEqual, ExitCodeAuthError)
+	})
+
+	t.Run("credentials rejected", func(t *testing.T) {
+		client := newClient(&injectDataClient{filterErr: status.Error(codes.Unauthenticated, "bad token")})
+		client.client = &injectAppServiceClient{}
+		_, err := client.binaryData(t.TempDir(), &datapb.Filter{}, 1, nil, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, ExitCode(err), test.ShouldEqual, ExitCodeAuthError)
+	})
+}
+
+func TestErrorCategories(t *testing.T) {
+	newContext := func(args []string, setFlags func(*flag.FlagSet)) *cli.Context {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.String("base-url", "http://127.0.0.1:1", "")
+		flags.Bool("allow-insecure", true, "")
+		if setFlags != nil {
+			setFlags(flags)
+		}
+		test.That(t, flags.Parse(args), test.ShouldBeNil)
+		out := &bytes.Buffer{}
+		return cli.NewContext(&cli.App{Writer: out, ErrWriter: out}, flags, nil)
+	}
+
+	t.Run("actions", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			action   cli.ActionFunc
+			ctx      *cli.Context
+			category errorCategory
+		}{
+			{
+				name:     "bad logs pattern",
+				action:   RobotPartLogsAction,
+				ctx:      newContext(nil, func(f *flag.FlagSet) { f.String("grep", "(", "") }),
+				category: categoryValidation,
+			},
+			{
+				name:     "missing upload package",
+				action:   UploadModuleAction,
+				ctx:      newContext(nil, nil),
+				category: categoryValidation,
+			},
+			{
+				name:     "missing service method",
+				action:   RobotPartRunAction,
+				ctx:      newContext(nil, nil),
+				category: categoryValidation,
+			},
+			{
+				name:     "bad output format",
+				action:   VersionAction,
+				ctx:      newContext(nil, func(f *flag.FlagSet) { f.String("format", "yaml", "") }),
+				category: categoryValidation,
+			},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				err := tc.action(tc.ctx)
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, errorCategoryOf(err), test.ShouldEqual, tc.category)
+				test.That(t, ExitCode(err), test.ShouldEqual, tc.category.exitCode())
+			})
+		}
+	})
+
+	t.Run("not logged in", func(t *testing.T) {
+		client := &appClient{c: newContext(nil, nil), conf: &config{}}
+		err := client.ensureLoggedIn()
+		test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryAuth)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		category errorCategory
+		code     int
+	}{
+		{"api", status.Error(codes.Unavailable, "down"), categoryAPI, ExitCodeFailure},
+		{"wrapped api", errors.Wrap(status.Error(codes.NotFound, "no robot"), "could not get robot"), categoryAPI, ExitCodeFailure},
+		{"rejected credentials", status.Error(codes.PermissionDenied, "no"), categoryAuth, ExitCodeAuthError},
+		{"partial failure", newPartialFailureError(errors.New("only 2 of 3")), categoryPartialFailure, ExitCodePartialFailure},
+		{"wrapped validation", errors.Wrap(newValidationError(errors.New("bad flag")), "could not export"), categoryValidation, ExitCodeFailure},
+		{"uncategorized", errors.New("disk full"), categoryUnknown, ExitCodeFailure},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			test.That(t, errorCategoryOf(tc.err), test.ShouldEqual, tc.category)
+			test.That(t, ExitCode(tc.err), test.ShouldEqual, tc.code)
+		})
+	}
+}
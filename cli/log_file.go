@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// logFileBackups is how many rotated files a rotatingLogFile keeps, as path.1 (the newest) to path.N.
+const logFileBackups = 2
+
+// rotatingLogFile is where 'robot part logs --output-file' writes logs. Once writing would take the file
+// past maxSize bytes, it is moved to path.1, path.1 to path.2 and so on, and a new file is started.
+//
+// A failed write, such as when the disk is full, does not end the command: the file is closed, a warning
+// is printed to warn, and later writes go to fallback instead, or are dropped if it is nil because the
+// logs are already streaming to the terminal.
+type rotatingLogFile struct {
+	path     string
+	maxSize  int64
+	warn     io.Writer
+	fallback io.Writer
+
+	file *os.File
+	size int64
+}
+
+// newRotatingLogFile opens path for appending, creating it if needed. A maxSize of 0 never rotates.
+func newRotatingLogFile(path string, maxSize int64, warn io.Writer) (*rotatingLogFile, error) {
+	if maxSize < 0 {
+		return nil, newValidationError(errors.Errorf("--max-size must not be negative, got %d", maxSize))
+	}
+	f := &rotatingLogFile{path: path, maxSize: maxSize, warn: warn}
+	if err := f.open(); err != nil {
+		return nil, errors.Wrap(err, "could not open --output-file")
+	}
+	return f, nil
+}
+
+func (f *rotatingLogFile) open() error {
+	//nolint:gosec
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return multierr.Combine(err, file.Close())
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write writes p to the file, rotating it first if p would not fit. It never fails, so that it can be
+// combined with the terminal using io.MultiWriter.
+func (f *rotatingLogFile) Write(p []byte) (int, error) {
+	if f.file == nil {
+		f.writeFallback(p)
+		return len(p), nil
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			f.fail(errors.Wrap(err, "could not rotate"))
+			f.writeFallback(p)
+			return len(p), nil
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err != nil {
+		f.fail(err)
+		f.writeFallback(p[n:])
+	}
+	return len(p), nil
+}
+
+func (f *rotatingLogFile) writeFallback(p []byte) {
+	if f.fallback != nil {
+		//nolint:errcheck,gosec
+		f.fallback.Write(p)
+	}
+}
+
+// rotate shifts the backups along by one, dropping the oldest, moves the file to path.1 and opens a new
+// one at path.
+func (f *rotatingLogFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	for i := logFileBackups - 1; i >= 1; i-- {
+		err := os.Rename(f.backupPath(i), f.backupPath(i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(f.path, f.backupPath(1)); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func (f *rotatingLogFile) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", f.path, i)
+}
+
+// fail stops writing to the file after err, warning that it has.
+func (f *rotatingLogFile) fail(err error) {
+	if f.file != nil {
+		//nolint:errcheck,gosec
+		f.file.Close()
+		f.file = nil
+	}
+	if f.fallback != nil {
+		warningf(f.warn, "stopped writing logs to %s, they will be shown here instead: %s", f.path, err)
+		return
+	}
+	warningf(f.warn, "stopped writing logs to %s, they will only be shown here: %s", f.path, err)
+}
+
+// Close closes the file, unless writing to it already failed.
+func (f *rotatingLogFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// newLogger returns the logger for an action. Nothing is logged unless --debug is set. JSON logs are
+// written to the app's error writer so that they never mix with command output, such as the output of
+// --format json.
+func newLogger(c *cli.Context) (golog.Logger, error) {
+	format := c.String("log-format")
+	switch format {
+	case "", logFormatText, logFormatJSON:
+	default:
+		return nil, newValidationError(errors.Errorf("unknown log format %q: must be %q or %q", format, logFormatText, logFormatJSON))
+	}
+	if !c.Bool("debug") {
+		return zap.NewNop().Sugar(), nil
+	}
+	if format != logFormatJSON {
+		return golog.NewDebugLogger("cli"), nil
+	}
+
+	encoderConfig := golog.NewDebugLoggerConfig().EncoderConfig
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	encoderConfig.CallerKey = zapcore.OmitKey
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(c.App.ErrWriter), zap.DebugLevel)
+	return zap.New(core).Sugar().Named("cli"), nil
+}
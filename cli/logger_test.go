@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"go.viam.com/test"
+)
+
+func TestNewLogger(t *testing.T) {
+	newContext := func(t *testing.T, debug bool, logFormat string) (*cli.Context, *bytes.Buffer, *bytes.Buffer) {
+		t.Helper()
+		flags := flag.NewFlagSet("viam", flag.ContinueOnError)
+		flags.Bool("debug", debug, "")
+		flags.String("log-format", logFormat, "")
+		var out, errOut bytes.Buffer
+		return cli.NewContext(&cli.App{Writer: &out, ErrWriter: &errOut}, flags, nil), &out, &errOut
+	}
+
+	t.Run("json", func(t *testing.T) {
+		cCtx, out, errOut := newContext(t, true, logFormatJSON)
+		logger, err := newLogger(cCtx)
+		test.That(t, err, test.ShouldBeNil)
+		logger.Debugw("dialing robot", "fqdn", "robot.viam.cloud", "attempt", 2)
+		logger.Warn("connection slow")
+		test.That(t, logger.Sync(), test.ShouldBeNil)
+
+		// logs must not end up in command output
+		test.That(t, out.String(), test.ShouldBeEmpty)
+		lines := strings.Split(strings.TrimSpace(errOut.String()), "\n")
+		test.That(t, lines, test.ShouldHaveLength, 2)
+
+		var entry map[string]interface{}
+		test.That(t, json.Unmarshal([]byte(lines[0]), &entry), test.ShouldBeNil)
+		test.That(t, entry["timestamp"], test.ShouldNotBeEmpty)
+		test.That(t, entry["level"], test.ShouldEqual, "debug")
+		test.That(t, entry["msg"], test.ShouldEqual, "dialing robot")
+		test.That(t, entry["logger"], test.ShouldEqual, "cli")
+		test.That(t, entry["fqdn"], test.ShouldEqual, "robot.viam.cloud")
+		test.That(t, entry["attempt"], test.ShouldEqual, 2)
+
+		test.That(t, json.Unmarshal([]byte(lines[1]), &entry), test.ShouldBeNil)
+		test.That(t, entry["level"], test.ShouldEqual, "warn")
+	})
+
+	t.Run("json without debug logs nothing", func(t *testing.T) {
+		cCtx, out, errOut := newContext(t, false, logFormatJSON)
+		logger, err := newLogger(cCtx)
+		test.That(t, err, test.ShouldBeNil)
+		logger.Warn("connection slow")
+		test.That(t, out.String(), test.ShouldBeEmpty)
+		test.That(t, errOut.String(), test.ShouldBeEmpty)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		cCtx, _, _ := newContext(t, true, "xml")
+		_, err := newLogger(cCtx)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+	})
+}
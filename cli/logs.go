@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	apppb "go.viam.com/api/app/v1"
+)
+
+// defaultLogReorderDelay is how long a followed log entry may be held back waiting for
+// earlier entries from other parts before it is printed.
+const defaultLogReorderDelay = time.Second
+
+// partLogEntry is a log entry along with the name of the robot part that produced it.
+type partLogEntry struct {
+	part string
+	log  *apppb.LogEntry
+}
+
+// logFilter keeps the log entries whose message matches a pattern, or with invert, the ones that don't.
+// A nil *logFilter keeps every entry.
+type logFilter struct {
+	pattern *regexp.Regexp
+	invert  bool
+}
+
+// newLogFilter returns a filter for the given pattern, or nil if there is no pattern.
+func newLogFilter(pattern string, invert bool) (*logFilter, error) {
+	if pattern == "" {
+		if invert {
+			return nil, newValidationError(errors.New("--grep-invert requires a pattern to be given with --grep"))
+		}
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, newValidationError(errors.Wrapf(err, "invalid --grep pattern %q", pattern))
+	}
+	return &logFilter{pattern: re, invert: invert}, nil
+}
+
+func (f *logFilter) keep(log *apppb.LogEntry) bool {
+	if f == nil {
+		return true
+	}
+	return f.pattern.MatchString(log.GetMessage()) != f.invert
+}
+
+// apply returns the entries of logs the filter keeps.
+func (f *logFilter) apply(logs []*apppb.LogEntry) []*apppb.LogEntry {
+	if f == nil {
+		return logs
+	}
+	var kept []*apppb.LogEntry
+	for _, log := range logs {
+		if f.keep(log) {
+			kept = append(kept, log)
+		}
+	}
+	return kept
+}
+
+// mergePartLogs combines the logs of several parts into a single slice ordered by timestamp.
+// logsByPart[i] holds the logs of parts[i]. Entries with equal timestamps keep the order of parts.
+func mergePartLogs(parts []*apppb.RobotPart, logsByPart [][]*apppb.LogEntry) []partLogEntry {
+	var merged []partLogEntry
+	for i, logs := range logsByPart {
+		for _, log := range logs {
+			merged = append(merged, partLogEntry{part: parts[i].Name, log: log})
+		}
+	}
+	sortPartLogEntries(merged)
+	return merged
+}
+
+func sortPartLogEntries(entries []partLogEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].log.Time.AsTime().Before(entries[j].log.Time.AsTime())
+	})
+}
+
+// logReorderBuffer holds followed log entries for a bounded delay so entries from
+// different parts can be emitted in roughly timestamp order.
+type logReorderBuffer struct {
+	delay   time.Duration
+	pending []bufferedLogEntry
+}
+
+type bufferedLogEntry struct {
+	partLogEntry
+	received time.Time
+}
+
+func newLogReorderBuffer(delay time.Duration) *logReorderBuffer {
+	return &logReorderBuffer{delay: delay}
+}
+
+// add buffers an entry that was received at the given time.
+func (b *logReorderBuffer) add(entry partLogEntry, received time.Time) {
+	b.pending = append(b.pending, bufferedLogEntry{partLogEntry: entry, received: received})
+}
+
+// flush returns, in timestamp order, every buffered entry that has been held for at least the
+// buffer's delay as of now, along with any held entries that are older than those. If all is
+// true, every buffered entry is returned.
+func (b *logReorderBuffer) flush(now time.Time, all bool) []partLogEntry {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	var cutoff time.Time
+	if !all {
+		for _, entry := range b.pending {
+			if now.Sub(entry.received) >= b.delay && entry.log.Time.AsTime().After(cutoff) {
+				cutoff = entry.log.Time.AsTime()
+			}
+		}
+		if cutoff.IsZero() {
+			return nil
+		}
+	}
+
+	var ready []partLogEntry
+	kept := b.pending[:0]
+	for _, entry := range b.pending {
+		if all || !entry.log.Time.AsTime().After(cutoff) {
+			ready = append(ready, entry.partLogEntry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	b.pending = kept
+	sortPartLogEntries(ready)
+	return ready
+}
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func logAt(base time.Time, offset time.Duration, msg string) *apppb.LogEntry {
+	return &apppb.LogEntry{Time: timestamppb.New(base.Add(offset)), Message: msg}
+}
+
+func messages(entries []partLogEntry) []string {
+	var out []string
+	for _, entry := range entries {
+		out = append(out, entry.part+":"+entry.log.Message)
+	}
+	return out
+}
+
+func TestMergePartLogs(t *testing.T) {
+	base := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	parts := []*apppb.RobotPart{{Name: "main"}, {Name: "arm"}}
+	logsByPart := [][]*apppb.LogEntry{
+		{logAt(base, 0, "a"), logAt(base, 2*time.Second, "c"), logAt(base, 4*time.Second, "e")},
+		{logAt(base, time.Second, "b"), logAt(base, 3*time.Second, "d"), logAt(base, 4*time.Second, "f")},
+	}
+
+	merged := mergePartLogs(parts, logsByPart)
+	test.That(t, messages(merged), test.ShouldResemble, []string{
+		"main:a", "arm:b", "main:c", "arm:d", "main:e", "arm:f",
+	})
+
+	test.That(t, mergePartLogs(parts, [][]*apppb.LogEntry{nil, nil}), test.ShouldBeEmpty)
+}
+
+func TestLogReorderBuffer(t *testing.T) {
+	base := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := time.Now()
+	buf := newLogReorderBuffer(time.Second)
+
+	// the arm's earlier log arrives after main's later one
+	buf.add(partLogEntry{part: "main", log: logAt(base, 2*time.Second, "c")}, now)
+	buf.add(partLogEntry{part: "arm", log: logAt(base, time.Second, "b")}, now.Add(100*time.Millisecond))
+	buf.add(partLogEntry{part: "main", log: logAt(base, 5*time.Second, "e")}, now.Add(900*time.Millisecond))
+
+	// nothing has been held long enough yet
+	test.That(t, buf.flush(now.Add(500*time.Millisecond), false), test.ShouldBeEmpty)
+
+	// main's first entry is due, and the arm's earlier entry goes with it
+	test.That(t, messages(buf.flush(now.Add(time.Second), false)), test.ShouldResemble, []string{"arm:b", "main:c"})
+
+	buf.add(partLogEntry{part: "arm", log: logAt(base, 4*time.Second, "d")}, now.Add(1500*time.Millisecond))
+	test.That(t, messages(buf.flush(now.Add(1500*time.Millisecond), true)), test.ShouldResemble, []string{"arm:d", "main:e"})
+	test.That(t, buf.flush(now.Add(time.Hour), true), test.ShouldBeEmpty)
+}
+
+func TestLogFilter(t *testing.T) {
+	logs := []*apppb.LogEntry{
+		{Message: "connected to arm"},
+		{Message: "failed to reach board: timeout"},
+		{Message: "board reconnected"},
+	}
+	logMessages := func(logs []*apppb.LogEntry) []string {
+		var out []string
+		for _, log := range logs {
+			out = append(out, log.Message)
+		}
+		return out
+	}
+
+	filter, err := newLogFilter("", false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter, test.ShouldBeNil)
+	test.That(t, filter.apply(logs), test.ShouldResemble, logs)
+
+	filter, err = newLogFilter("board.*(timeout|reconnected)", false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, logMessages(filter.apply(logs)), test.ShouldResemble, []string{"failed to reach board: timeout", "board reconnected"})
+
+	filter, err = newLogFilter("^board", true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, logMessages(filter.apply(logs)), test.ShouldResemble, []string{"connected to arm", "failed to reach board: timeout"})
+
+	_, err = newLogFilter("board(", false)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `invalid --grep pattern "board("`)
+
+	_, err = newLogFilter("", true)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "--grep-invert requires a pattern")
+}
+
+func TestLogsActionsRejectBadPattern(t *testing.T) {
+	for _, action := range []cli.ActionFunc{RobotLogsAction, RobotPartLogsAction} {
+		flags := flag.NewFlagSet("logs", flag.ContinueOnError)
+		flags.String("grep", "[unclosed", "")
+		flags.String("base-url", "http://127.0.0.1:1", "")
+		flags.Bool("allow-insecure", true, "")
+		out := &bytes.Buffer{}
+		err := action(cli.NewContext(&cli.App{Writer: out, ErrWriter: out}, flags, nil))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "invalid --grep pattern")
+	}
+}
+
+// injectTailClient streams the given batches of logs and then ends.
+type injectTailClient struct {
+	grpc.ClientStream
+	batches [][]*apppb.LogEntry
+}
+
+func (i *injectTailClient) Recv() (*apppb.TailRobotPartLogsResponse, error) {
+	if len(i.batches) == 0 {
+		return nil, io.EOF
+	}
+	batch := i.batches[0]
+	i.batches = i.batches[1:]
+	return &apppb.TailRobotPartLogsResponse{Logs: batch}, nil
+}
+
+type injectTailAppClient struct {
+	apppb.AppServiceClient
+	batches [][]*apppb.LogEntry
+}
+
+func (i *injectTailAppClient) TailRobotPartLogs(
+	ctx context.Context, in *apppb.TailRobotPartLogsRequest, opts ...grpc.CallOption,
+) (apppb.AppService_TailRobotPartLogsClient, error) {
+	return &injectTailClient{batches: i.batches}, nil
+}
+
+func TestTailRobotLogsFilter(t *testing.T) {
+	base := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	out := &bytes.Buffer{}
+	client := &appClient{
+		c: cli.NewContext(&cli.App{Writer: out, ErrWriter: out}, nil, nil),
+		client: &injectTailAppClient{batches: [][]*apppb.LogEntry{
+			{logAt(base, 0, "starting"), logAt(base, time.Second, "error: motor stalled")},
+			{logAt(base, 2*time.Second, "error: motor stalled again"), logAt(base, 3*time.Second, "stopped")},
+		}},
+	}
+	client.c.Context = context.Background()
+
+	filter, err := newLogFilter("^error:", false)
+	test.That(t, err, test.ShouldBeNil)
+	parts := []*apppb.RobotPart{{Id: "1", Name: "main"}}
+	test.That(t, client.tailRobotLogs(parts, false, filter, 10*time.Millisecond), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldContainSubstring, "motor stalled\n")
+	test.That(t, out.String(), test.ShouldContainSubstring, "motor stalled again\n")
+	test.That(t, out.String(), test.ShouldNotContainSubstring, "starting")
+	test.That(t, out.String(), test.ShouldNotContainSubstring, "stopped")
+}
+
+func TestRotatingLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "part.log")
+	warn := &bytes.Buffer{}
+	logFile, err := newRotatingLogFile(path, 10, warn)
+	test.That(t, err, test.ShouldBeNil)
+	readFile := func(path string) string {
+		//nolint:gosec
+		contents, err := os.ReadFile(path)
+		test.That(t, err, test.ShouldBeNil)
+		return string(contents)
+	}
+
+	write := func(line string) {
+		n, err := logFile.Write([]byte(line))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, n, test.ShouldEqual, len(line))
+	}
+	write("aaaa\n")
+	write("bbbb\n")
+	// exactly at the threshold, so nothing has rotated yet
+	test.That(t, readFile(path), test.ShouldEqual, "aaaa\nbbbb\n")
+	_, err = os.Stat(path + ".1")
+	test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+
+	write("cccc\n")
+	test.That(t, readFile(path), test.ShouldEqual, "cccc\n")
+	test.That(t, readFile(path+".1"), test.ShouldEqual, "aaaa\nbbbb\n")
+
+	write("dddddddd\n")
+	write("eeee\n")
+	test.That(t, readFile(path), test.ShouldEqual, "eeee\n")
+	test.That(t, readFile(path+".1"), test.ShouldEqual, "dddddddd\n")
+	test.That(t, readFile(path+".2"), test.ShouldEqual, "cccc\n")
+	test.That(t, logFile.Close(), test.ShouldBeNil)
+	test.That(t, warn.String(), test.ShouldBeEmpty)
+
+	// reopening continues from the size already written
+	logFile, err = newRotatingLogFile(path, 10, warn)
+	test.That(t, err, test.ShouldBeNil)
+	write("ffffff\n")
+	test.That(t, readFile(path), test.ShouldEqual, "ffffff\n")
+	test.That(t, readFile(path+".1"), test.ShouldEqual, "eeee\n")
+	test.That(t, readFile(path+".2"), test.ShouldEqual, "dddddddd\n")
+	test.That(t, logFile.Close(), test.ShouldBeNil)
+
+	_, err = newRotatingLogFile(path, -1, warn)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestRotatingLogFileFull(t *testing.T) {
+	// writing to /dev/full always fails as if the disk were full.
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("no /dev/full")
+	}
+	warn := &bytes.Buffer{}
+	logFile, err := newRotatingLogFile("/dev/full", 0, warn)
+	test.That(t, err, test.ShouldBeNil)
+	terminal := &bytes.Buffer{}
+	out := io.MultiWriter(terminal, logFile)
+
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err := out.Write([]byte(line))
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, terminal.String(), test.ShouldEqual, "first\nsecond\n")
+	test.That(t, strings.Count(warn.String(), "stopped writing logs to /dev/full"), test.ShouldEqual, 1)
+	test.That(t, warn.String(), test.ShouldContainSubstring, "they will only be shown here")
+	test.That(t, logFile.Close(), test.ShouldBeNil)
+
+	// with --quiet, the logs are shown on the terminal once the file fails.
+	warn.Reset()
+	logFile, err = newRotatingLogFile("/dev/full", 0, warn)
+	test.That(t, err, test.ShouldBeNil)
+	terminal.Reset()
+	logFile.fallback = terminal
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err := logFile.Write([]byte(line))
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, terminal.String(), test.ShouldEqual, "first\nsecond\n")
+	test.That(t, warn.String(), test.ShouldContainSubstring, "they will be shown here instead")
+	test.That(t, logFile.Close(), test.ShouldBeNil)
+}
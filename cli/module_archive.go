@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// entrypointMode is the mode 'module upload' gives the entrypoint in the archives it builds, so that the
+// module can be run wherever it is downloaded, even when it was built on a filesystem without mode bits.
+const entrypointMode = 0o755
+
+// buildModuleTarball archives the contents of dir into a temporary .tar.gz for 'module upload', with
+// entrypoint, if it is set, marked executable. The caller removes the archive when it is done with it.
+func buildModuleTarball(dir, entrypoint string) (string, error) {
+	file, err := os.CreateTemp("", "module-*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	if err := multierr.Combine(writeModuleTarball(file, dir, entrypoint), file.Close()); err != nil {
+		//nolint:errcheck,gosec
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// writeModuleTarball writes the contents of dir to w as a gzipped tar archive, with paths relative to
+// dir. entrypoint, if it is set, must be a file in dir and is given entrypointMode.
+func writeModuleTarball(w io.Writer, dir, entrypoint string) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	entrypoint = cleanArchivePath(entrypoint)
+	var foundEntrypoint bool
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case !info.Mode().IsRegular() && !info.IsDir():
+			// sockets, devices and the like cannot be part of a module.
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if entrypoint != "" && cleanArchivePath(header.Name) == entrypoint {
+			if info.IsDir() {
+				return errors.Errorf("entrypoint %q is a directory in %s", entrypoint, dir)
+			}
+			header.Mode = entrypointMode
+			foundEntrypoint = true
+		}
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		//nolint:gosec
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(archive, file)
+		return multierr.Combine(err, file.Close())
+	})
+	if err != nil {
+		return err
+	}
+	if entrypoint != "" && !foundEntrypoint {
+		return errors.Errorf("entrypoint %q from the meta.json is not in %s", entrypoint, dir)
+	}
+	return multierr.Combine(archive.Close(), gz.Close())
+}
+
+// checkTarballEntrypointExecutable returns an error if entrypoint is in the archive at tarballPath but
+// not executable, in which case the module will fail to start wherever it is downloaded.
+func checkTarballEntrypointExecutable(tarballPath, entrypoint string) error {
+	if entrypoint == "" {
+		return nil
+	}
+	entrypoint = cleanArchivePath(entrypoint)
+	return walkModuleTarball(tarballPath, func(header *tar.Header) error {
+		if cleanArchivePath(header.Name) != entrypoint || header.Typeflag != tar.TypeReg {
+			return nil
+		}
+		if header.Mode&0o111 == 0 {
+			return errors.Errorf("entrypoint %q is not executable in %s (its mode is %#o), so the module will fail to start. "+
+				"run chmod +x on it before archiving, or pass the module's directory to have the archive built for you",
+				entrypoint, tarballPath, header.Mode)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestBuildModuleTarball(t *testing.T) {
+	dir := t.TempDir()
+	test.That(t, os.MkdirAll(filepath.Join(dir, "bin"), 0o700), test.ShouldBeNil)
+	// the entrypoint is not executable, as when it was built on a filesystem without mode bits.
+	test.That(t, os.WriteFile(filepath.Join(dir, "bin", "module"), []byte("#!/bin/sh\n"), 0o644), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# module\n"), 0o644), test.ShouldBeNil)
+	tarballModes := func(tarballPath string) map[string]int64 {
+		modes := map[string]int64{}
+		test.That(t, walkModuleTarball(tarballPath, func(header *tar.Header) error {
+			modes[header.Name] = header.Mode
+			return nil
+		}), test.ShouldBeNil)
+		return modes
+	}
+
+	tarballPath, err := buildModuleTarball(dir, "./bin/module")
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, os.Remove(tarballPath), test.ShouldBeNil)
+	}()
+	modes := tarballModes(tarballPath)
+	test.That(t, modes["bin/module"], test.ShouldEqual, 0o755)
+	test.That(t, modes["README.md"], test.ShouldEqual, 0o644)
+	test.That(t, modes, test.ShouldContainKey, "bin/")
+	test.That(t, checkModuleTarball(tarballPath, "./bin/module"), test.ShouldBeNil)
+	test.That(t, checkTarballEntrypointExecutable(tarballPath, "./bin/module"), test.ShouldBeNil)
+
+	t.Run("prebuilt tarball with a non-executable entrypoint", func(t *testing.T) {
+		// without an entrypoint, the archive keeps the modes the files have.
+		prebuilt, err := buildModuleTarball(dir, "")
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, os.Remove(prebuilt), test.ShouldBeNil)
+		}()
+		test.That(t, tarballModes(prebuilt)["bin/module"], test.ShouldEqual, 0o644)
+
+		err = checkTarballEntrypointExecutable(prebuilt, "bin/module")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `entrypoint "bin/module" is not executable`)
+		test.That(t, checkTarballEntrypointExecutable(prebuilt, ""), test.ShouldBeNil)
+	})
+
+	t.Run("missing entrypoint", func(t *testing.T) {
+		_, err := buildModuleTarball(dir, "bin/other")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `entrypoint "bin/other" from the meta.json is not in`)
+
+		_, err = buildModuleTarball(dir, "bin")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "is a directory")
+	})
+}
@@ -8,18 +8,29 @@ import (
 	"io/fs"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/multierr"
 	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // moduleUploadChunkSize sets the number of bytes included in each chunk of the upload stream.
 var moduleUploadChunkSize = 32 * 1024
 
+// moduleUploadAttempts is the number of times an upload is tried before giving up on a transient error.
+const moduleUploadAttempts = 4
+
+// moduleUploadInitialBackoff is how long to wait before retrying a failed upload. It doubles after every attempt.
+var moduleUploadInitialBackoff = time.Second
+
 // moduleVisibility determines whether modules are public or private.
 type moduleVisibility string
 
@@ -57,11 +68,34 @@ const (
 
 // CreateModuleAction is the corresponding Action for 'module create'. It runs
 // the command to create a module. This includes both a gRPC call to register
-// the module on app.viam.com and creating the manifest file.
+// the module on app.viam.com and creating the manifest file. With --language,
+// a starter project for the module is also written to the current directory.
 func CreateModuleAction(c *cli.Context) error {
 	moduleNameArg := c.String("name")
 	publicNamespaceArg := c.String("public-namespace")
 	orgIDArg := c.String("org-id")
+	languageArg := c.String("language")
+	force := c.Bool("force")
+
+	// Check to make sure the user doesn't accidentally overwrite a module manifest or any other files,
+	// before the module is registered.
+	if _, err := os.Stat(defaultManifestFilename); err == nil && !force {
+		return errors.New("another module's meta.json already exists in the current directory. delete it and try again")
+	}
+	if languageArg != "" {
+		scaffoldNames, err := scaffoldFilenames(languageArg, moduleNameArg)
+		if err != nil {
+			return err
+		}
+		if err := checkScaffoldTargets(".", scaffoldNames, force); err != nil {
+			return err
+		}
+		if c.String("model") != "" {
+			if _, err := parseScaffoldModel(c.String("model")); err != nil {
+				return err
+			}
+		}
+	}
 
 	client, err := newAppClient(c)
 	if err != nil {
@@ -74,10 +108,6 @@ func CreateModuleAction(c *cli.Context) error {
 	if org == nil {
 		return errors.Errorf("unable to determine org from org-id (%q) and namespace (%q)", orgIDArg, publicNamespaceArg)
 	}
-	// Check to make sure the user doesn't accidentally overwrite a module manifest
-	if _, err := os.Stat(defaultManifestFilename); err == nil {
-		return errors.New("another module's meta.json already exists in the current directory. delete it and try again")
-	}
 
 	response, err := client.createModule(moduleNameArg, org.GetId())
 	if err != nil {
@@ -107,24 +137,48 @@ func CreateModuleAction(c *cli.Context) error {
 			{},
 		},
 	}
-	if err := writeManifest(defaultManifestFilename, emptyManifest); err != nil {
+	if languageArg == "" {
+		if err := writeManifest(defaultManifestFilename, emptyManifest); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.App.Writer, "configuration for the module has been written to meta.json\n")
+		return nil
+	}
+
+	modelArg := c.String("model")
+	if modelArg == "" {
+		namespace := returnedModuleID.prefix
+		if namespace == "" {
+			namespace = returnedModuleID.name
+		}
+		modelArg = fmt.Sprintf("%s:%s:%s", namespace, returnedModuleID.name, returnedModuleID.name)
+	}
+	model, err := parseScaffoldModel(modelArg)
+	if err != nil {
 		return err
 	}
-	fmt.Fprintf(c.App.Writer, "configuration for the module has been written to meta.json\n")
-	return nil
+	return scaffoldModule(c.App.Writer, ".", languageArg, returnedModuleID, model)
 }
 
 // UpdateModuleAction is the corresponding Action for 'module update'. It runs
 // the command to update a module. This includes updating the meta.json to
-// include the public namespace (if set on the org).
+// include the public namespace (if set on the org). The meta.json is validated
+// before anything is sent, and with --validate-only nothing else is done. With
+// --diff the differences from the module on the server are printed instead of
+// being applied.
 func UpdateModuleAction(c *cli.Context) error {
 	publicNamespaceArg := c.String("public-namespace")
 	orgIDArg := c.String("org-id")
 	manifestPathArg := c.String("module")
 
-	manifestPath := defaultManifestFilename
-	if manifestPathArg != "" {
-		manifestPath = manifestPathArg
+	manifestPath := resolveManifestPath(manifestPathArg)
+
+	if err := validateManifestFile(c.App.ErrWriter, manifestPath); err != nil {
+		return err
+	}
+	if c.Bool("validate-only") {
+		fmt.Fprintf(c.App.Writer, "%s is valid\n", manifestPath)
+		return nil
 	}
 
 	client, err := newAppClient(c)
@@ -142,6 +196,24 @@ func UpdateModuleAction(c *cli.Context) error {
 		return err
 	}
 
+	if c.Bool("diff") {
+		remote, err := client.getModule(moduleID)
+		if err != nil {
+			return err
+		}
+		changes := diffModuleManifest(remote, manifest)
+		if len(changes) == 0 {
+			fmt.Fprintf(c.App.Writer, "%s matches %s on app.viam.com, there is nothing to update\n", manifestPath, moduleID.String())
+			return nil
+		}
+		fmt.Fprintf(c.App.Writer, "updating %s with %s would make these changes:\n", moduleID.String(), manifestPath)
+		for _, change := range changes {
+			fmt.Fprintf(c.App.Writer, "  %s\n", change)
+		}
+		infof(c.App.Writer, "run the command again without --diff to apply them")
+		return nil
+	}
+
 	response, err := client.updateModule(moduleID, manifest)
 	if err != nil {
 		return err
@@ -182,13 +254,15 @@ func UploadModuleAction(c *cli.Context) error {
 	nameArg := c.String("name")
 	versionArg := c.String("version")
 	platformArg := c.String("platform")
+	checkOnly := c.Bool("check")
 	tarballPath := c.Args().First()
 	if c.Args().Len() > 1 {
-		return errors.New("too many arguments passed to upload command. " +
-			"make sure to specify flag and optional arguments before the required positional package argument")
+		return newValidationError(errors.New("too many arguments passed to upload command. " +
+			"make sure to specify flag and optional arguments before the required positional package argument"))
 	}
 	if tarballPath == "" {
-		return errors.New("no package to upload -- please provide an archive containing your module. use --help for more information")
+		return newValidationError(errors.New(
+			"no package to upload -- please provide an archive containing your module. use --help for more information"))
 	}
 
 	client, err := newAppClient(c)
@@ -196,18 +270,16 @@ func UploadModuleAction(c *cli.Context) error {
 		return err
 	}
 
-	manifestPath := defaultManifestFilename
-	if manifestPathArg != "" {
-		manifestPath = manifestPathArg
-	}
+	manifestPath := resolveManifestPath(manifestPathArg)
 	var moduleID moduleID
+	var manifest *moduleManifest
 	// if the manifest cant be found
 	if _, err := os.Stat(manifestPath); err != nil {
 		// no manifest found.
 		if nameArg == "" || (publicNamespaceArg == "" && orgIDArg == "") {
-			return errors.New("unable to find the meta.json. " +
+			return newValidationError(errors.New("unable to find the meta.json. " +
 				"if you want to upload a version without a meta.json, you must supply a module name and namespace (or module name and org-id)",
-			)
+			))
 		}
 		moduleID, err = updateManifestModuleIDWithArgs(c, client, nameArg, publicNamespaceArg, orgIDArg)
 		if err != nil {
@@ -215,10 +287,11 @@ func UploadModuleAction(c *cli.Context) error {
 		}
 	} else {
 		// if we can find a manifest, use that
-		manifest, err := loadManifest(manifestPath)
+		loaded, err := loadManifest(manifestPath)
 		if err != nil {
 			return err
 		}
+		manifest = &loaded
 
 		moduleID, err = updateManifestModuleIDWithArgs(c, client, manifest.Name, publicNamespaceArg, orgIDArg)
 		if err != nil {
@@ -226,9 +299,33 @@ func UploadModuleAction(c *cli.Context) error {
 		}
 		if nameArg != "" && nameArg != moduleID.name {
 			// This is almost certainly a mistake we want to catch
-			return errors.Errorf("module name %q was supplied on the command line but the meta.json has a module name of %q",
-				nameArg, moduleID.name)
+			return newValidationError(errors.Errorf("module name %q was supplied on the command line but the meta.json has a module name of %q",
+				nameArg, moduleID.name))
+		}
+	}
+
+	var entrypoint string
+	if manifest != nil {
+		entrypoint = manifest.Entrypoint
+	}
+	if info, err := os.Stat(tarballPath); err == nil && info.IsDir() {
+		// archive the directory ourselves, which also makes sure the entrypoint is executable.
+		built, err := buildModuleTarball(tarballPath, entrypoint)
+		if err != nil {
+			return errors.Wrapf(err, "could not archive %s", tarballPath)
 		}
+		defer utils.UncheckedErrorFunc(func() error { return os.Remove(built) })
+		tarballPath = built
+	} else if err := checkTarballEntrypointExecutable(tarballPath, entrypoint); err != nil {
+		warningf(c.App.ErrWriter, "%s", err)
+	}
+
+	if checkOnly {
+		checks := client.checkModuleUpload(moduleID, manifest, versionArg, platformArg, tarballPath)
+		return printModuleUploadChecks(c.App.Writer, checks)
+	}
+	if err := checkModulePlatform(platformArg); err != nil {
+		return newValidationError(err)
 	}
 
 	//nolint:gosec
@@ -238,7 +335,7 @@ func UploadModuleAction(c *cli.Context) error {
 	}
 	// TODO(APP-2226): support .tar.xz
 	if !strings.HasSuffix(file.Name(), ".tar.gz") {
-		return errors.New("you must upload your module in the form of a .tar.gz")
+		return newValidationError(errors.New("you must upload your module in the form of a .tar.gz"))
 	}
 	response, err := client.uploadModuleFile(moduleID, versionArg, platformArg, file)
 	if err != nil {
@@ -284,6 +381,53 @@ func (c *appClient) updateModule(moduleID moduleID, manifest moduleManifest) (*a
 	return c.client.UpdateModule(c.c.Context, &req)
 }
 
+func (c *appClient) getModule(moduleID moduleID) (*apppb.Module, error) {
+	if err := c.ensureLoggedIn(); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.GetModule(c.c.Context, &apppb.GetModuleRequest{ModuleId: moduleID.String()})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get module %s", moduleID.String())
+	}
+	return resp.GetModule(), nil
+}
+
+// diffModuleManifest returns the field-level changes that updating remote with local would make, one
+// line per change. Models are compared as a set, so reordering them in meta.json is not a change.
+func diffModuleManifest(remote *apppb.Module, local moduleManifest) []string {
+	var changes []string
+	diffField := func(name, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", name, from, to))
+		}
+	}
+	diffField("visibility", string(visibilityFromProto(remote.GetVisibility())), string(local.Visibility))
+	diffField("url", remote.GetUrl(), local.URL)
+	diffField("description", remote.GetDescription(), local.Description)
+	diffField("entrypoint", remote.GetEntrypoint(), local.Entrypoint)
+
+	remoteModels := make(map[moduleComponent]bool)
+	for _, model := range remote.GetModels() {
+		remoteModels[moduleComponent{API: model.GetApi(), Model: model.GetModel()}] = true
+	}
+	localModels := make(map[moduleComponent]bool)
+	for _, model := range local.Models {
+		localModels[model] = true
+		if !remoteModels[model] {
+			changes = append(changes, fmt.Sprintf("+ model %s %s", model.API, model.Model))
+		}
+	}
+	for _, model := range remote.GetModels() {
+		if mc := (moduleComponent{API: model.GetApi(), Model: model.GetModel()}); !localModels[mc] {
+			changes = append(changes, fmt.Sprintf("- model %s %s", mc.API, mc.Model))
+		}
+	}
+	return changes
+}
+
+// uploadModuleFile uploads file as the given version of a module, retrying with backoff if the upload
+// fails with a transient error. The registry has no resumable uploads, so every attempt restarts the
+// upload stream from the beginning of the file.
 func (c *appClient) uploadModuleFile(
 	moduleID moduleID,
 	version,
@@ -295,6 +439,43 @@ func (c *appClient) uploadModuleFile(
 	}
 	ctx := c.c.Context
 
+	backoff := moduleUploadInitialBackoff
+	for attempt := 1; ; attempt++ {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", file.Name())
+		}
+		resp, err := c.uploadModuleFileOnce(ctx, moduleID, version, platform, file)
+		if err == nil || attempt == moduleUploadAttempts || !isTransientUploadError(err) {
+			return resp, err
+		}
+		warningf(c.c.App.ErrWriter, "upload attempt %d of %d failed: %v; retrying in %s",
+			attempt, moduleUploadAttempts, err, backoff)
+		if !utils.SelectContextOrWait(ctx, backoff) {
+			return nil, multierr.Combine(err, ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientUploadError returns whether an upload failed for a reason that retrying may fix.
+func isTransientUploadError(err error) bool {
+	for _, err := range multierr.Errors(err) {
+		switch status.Code(errors.Cause(err)) {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+func (c *appClient) uploadModuleFileOnce(
+	ctx context.Context,
+	moduleID moduleID,
+	version,
+	platform string,
+	file *os.File,
+) (*apppb.UploadModuleFileResponse, error) {
 	stream, err := c.client.UploadModuleFile(ctx)
 	if err != nil {
 		return nil, err
@@ -391,6 +572,21 @@ func visibilityToProto(visibility moduleVisibility) (apppb.Visibility, error) {
 	}
 }
 
+// visibilityFromProto is the inverse of visibilityToProto. An unspecified visibility is returned as an
+// empty string.
+func visibilityFromProto(visibility apppb.Visibility) moduleVisibility {
+	switch visibility {
+	case apppb.Visibility_VISIBILITY_PRIVATE:
+		return moduleVisibilityPrivate
+	case apppb.Visibility_VISIBILITY_PUBLIC:
+		return moduleVisibilityPublic
+	case apppb.Visibility_VISIBILITY_UNSPECIFIED:
+		return ""
+	default:
+		return moduleVisibility(visibility.String())
+	}
+}
+
 func moduleComponentToProto(moduleComponent moduleComponent) *apppb.Model {
 	return &apppb.Model{
 		Api:   moduleComponent.API,
@@ -473,10 +669,10 @@ func updateManifestModuleIDWithArgs(
 func resolveOrg(client *appClient, publicNamespace, orgID string) (*apppb.Organization, error) {
 	if orgID != "" {
 		if publicNamespace != "" {
-			return nil, errors.New("cannot specify both org-id and public-namespace")
+			return nil, newValidationError(errors.New("cannot specify both org-id and public-namespace"))
 		}
 		if !isValidOrgID(orgID) {
-			return nil, errors.Errorf("provided org-id %q is not a valid org-id", orgID)
+			return nil, newValidationError(errors.Errorf("provided org-id %q is not a valid org-id", orgID))
 		}
 		org, err := client.getOrg(orgID)
 		if err != nil {
@@ -486,7 +682,7 @@ func resolveOrg(client *appClient, publicNamespace, orgID string) (*apppb.Organi
 	}
 	// Use publicNamespace to back-derive what the org is
 	if publicNamespace == "" {
-		return nil, errors.New("must provide either org-id or public-namespace")
+		return nil, newValidationError(errors.New("must provide either org-id or public-namespace"))
 	}
 	org, err := client.getUserOrgByPublicNamespace(publicNamespace)
 	if err != nil {
@@ -508,6 +704,43 @@ func isValidOrgID(str string) bool {
 	return err == nil
 }
 
+// resolveManifestPath returns the meta.json a module command should use: manifestPathArg if it was given,
+// and otherwise the nearest meta.json in the current directory or one of its parents, the way git finds
+// .git, so that module commands work from anywhere inside a module. If there is none, it returns the
+// default path so that callers report it as missing.
+func resolveManifestPath(manifestPathArg string) string {
+	if manifestPathArg != "" {
+		return manifestPathArg
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return defaultManifestFilename
+	}
+	found, ok := findManifest(wd)
+	if !ok {
+		return defaultManifestFilename
+	}
+	if rel, err := filepath.Rel(wd, found); err == nil {
+		return rel
+	}
+	return found
+}
+
+// findManifest looks for a meta.json in dir and then in each of its parents in turn.
+func findManifest(dir string) (string, bool) {
+	for {
+		candidate := filepath.Join(dir, defaultManifestFilename)
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
 func loadManifest(manifestPath string) (moduleManifest, error) {
 	//nolint:gosec
 	manifestBytes, err := os.ReadFile(manifestPath)
@@ -0,0 +1,240 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// injectUploadStream records what is sent on a module upload stream. If failAfter is positive, the
+// stream breaks with failErr after that many file chunks, the way a dropped connection would.
+type injectUploadStream struct {
+	grpc.ClientStream
+	info      *apppb.ModuleFileInfo
+	data      bytes.Buffer
+	chunks    int
+	failAfter int
+	failErr   error
+}
+
+func (s *injectUploadStream) Send(req *apppb.UploadModuleFileRequest) error {
+	if info := req.GetModuleFileInfo(); info != nil {
+		s.info = info
+		return nil
+	}
+	if s.failAfter > 0 && s.chunks == s.failAfter {
+		return io.EOF
+	}
+	s.chunks++
+	s.data.Write(req.GetFile())
+	return nil
+}
+
+func (s *injectUploadStream) CloseSend() error {
+	return nil
+}
+
+func (s *injectUploadStream) CloseAndRecv() (*apppb.UploadModuleFileResponse, error) {
+	if s.failAfter > 0 && s.chunks == s.failAfter {
+		return nil, s.failErr
+	}
+	return &apppb.UploadModuleFileResponse{Url: "https://app.viam.com/module/acme/my-module"}, nil
+}
+
+// injectUploadAppClient hands out the given streams, one per upload attempt.
+type injectUploadAppClient struct {
+	apppb.AppServiceClient
+	streams []*injectUploadStream
+	opened  int
+}
+
+func (i *injectUploadAppClient) UploadModuleFile(
+	ctx context.Context, opts ...grpc.CallOption,
+) (apppb.AppService_UploadModuleFileClient, error) {
+	stream := i.streams[i.opened]
+	i.opened++
+	return stream, nil
+}
+
+func TestUploadModuleFileRetries(t *testing.T) {
+	prevBackoff := moduleUploadInitialBackoff
+	moduleUploadInitialBackoff = time.Millisecond
+	defer func() { moduleUploadInitialBackoff = prevBackoff }()
+
+	contents := bytes.Repeat([]byte("module bytes "), 3*moduleUploadChunkSize/10)
+	tarballPath := filepath.Join(t.TempDir(), "module.tar.gz")
+	test.That(t, os.WriteFile(tarballPath, contents, 0o600), test.ShouldBeNil)
+	moduleID := moduleID{prefix: "acme", name: "my-module"}
+
+	upload := func(t *testing.T, streams ...*injectUploadStream) (*injectUploadAppClient, string, error) {
+		t.Helper()
+		appServiceClient := &injectUploadAppClient{streams: streams}
+		var errOut bytes.Buffer
+		cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &errOut}, nil, nil)
+		cCtx.Context = context.Background()
+		client := &appClient{c: cCtx, conf: &config{}, client: appServiceClient}
+
+		//nolint:gosec
+		file, err := os.Open(tarballPath)
+		test.That(t, err, test.ShouldBeNil)
+		defer file.Close()
+		resp, err := client.uploadModuleFile(moduleID, "0.1.0", "linux/amd64", file)
+		if err == nil {
+			test.That(t, resp.GetUrl(), test.ShouldEqual, "https://app.viam.com/module/acme/my-module")
+		}
+		return appServiceClient, errOut.String(), err
+	}
+
+	t.Run("transient failure midway restarts the upload", func(t *testing.T) {
+		broken := &injectUploadStream{failAfter: 2, failErr: status.Error(codes.Unavailable, "connection reset")}
+		retried := &injectUploadStream{}
+		appServiceClient, errOut, err := upload(t, broken, retried)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, appServiceClient.opened, test.ShouldEqual, 2)
+		test.That(t, errOut, test.ShouldContainSubstring, "upload attempt 1 of 4 failed")
+
+		// the registry can't resume an upload, so the retry must send the whole file again
+		test.That(t, retried.info.GetModuleId(), test.ShouldEqual, "acme:my-module")
+		test.That(t, retried.info.GetVersion(), test.ShouldEqual, "0.1.0")
+		test.That(t, retried.data.Bytes(), test.ShouldResemble, contents)
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		var streams []*injectUploadStream
+		for i := 0; i < moduleUploadAttempts; i++ {
+			streams = append(streams, &injectUploadStream{failAfter: 1, failErr: status.Error(codes.Unavailable, "down")})
+		}
+		appServiceClient, _, err := upload(t, streams...)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "down")
+		test.That(t, appServiceClient.opened, test.ShouldEqual, moduleUploadAttempts)
+	})
+
+	t.Run("permanent failures are not retried", func(t *testing.T) {
+		rejected := &injectUploadStream{failAfter: 1, failErr: status.Error(codes.AlreadyExists, "version 0.1.0 already exists")}
+		appServiceClient, errOut, err := upload(t, rejected, &injectUploadStream{})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "already exists")
+		test.That(t, appServiceClient.opened, test.ShouldEqual, 1)
+		test.That(t, errOut, test.ShouldBeEmpty)
+	})
+}
+
+func TestDiffModuleManifest(t *testing.T) {
+	manifestPath := filepath.Join(t.TempDir(), "meta.json")
+	test.That(t, os.WriteFile(manifestPath, []byte(`{
+  "name": "acme:my-module",
+  "visibility": "public",
+  "url": "https://github.com/acme/my-module",
+  "description": "a better module",
+  "models": [
+    {"api": "rdk:component:sensor", "model": "acme:my-module:thermometer"},
+    {"api": "rdk:component:base", "model": "acme:my-module:rover"}
+  ],
+  "entrypoint": "./run.sh"
+}`), 0o600), test.ShouldBeNil)
+	manifest, err := loadManifest(manifestPath)
+	test.That(t, err, test.ShouldBeNil)
+
+	remote := &apppb.Module{
+		ModuleId:    "acme:my-module",
+		Visibility:  apppb.Visibility_VISIBILITY_PRIVATE,
+		Url:         "https://github.com/acme/my-module",
+		Description: "a module",
+		Models: []*apppb.Model{
+			{Api: "rdk:component:base", Model: "acme:my-module:rover"},
+			{Api: "rdk:component:arm", Model: "acme:my-module:gripper"},
+		},
+		Entrypoint: "./run.sh",
+	}
+	cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}}, nil, nil)
+	cCtx.Context = context.Background()
+	client := &appClient{c: cCtx, conf: &config{}, client: &injectModuleAppClient{module: remote}}
+
+	got, err := client.getModule(moduleID{prefix: "acme", name: "my-module"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diffModuleManifest(got, manifest), test.ShouldResemble, []string{
+		`visibility: "private" -> "public"`,
+		`description: "a module" -> "a better module"`,
+		"+ model rdk:component:sensor acme:my-module:thermometer",
+		"- model rdk:component:arm acme:my-module:gripper",
+	})
+
+	// once the update is applied there is nothing left to change.
+	applied := &apppb.Module{
+		ModuleId:    remote.ModuleId,
+		Visibility:  apppb.Visibility_VISIBILITY_PUBLIC,
+		Url:         manifest.URL,
+		Description: manifest.Description,
+		Models: []*apppb.Model{
+			{Api: "rdk:component:base", Model: "acme:my-module:rover"},
+			{Api: "rdk:component:sensor", Model: "acme:my-module:thermometer"},
+		},
+		Entrypoint: manifest.Entrypoint,
+	}
+	test.That(t, diffModuleManifest(applied, manifest), test.ShouldBeEmpty)
+
+	client.client = &injectModuleAppClient{err: status.Error(codes.NotFound, "no such module")}
+	_, err = client.getModule(moduleID{prefix: "acme", name: "my-module"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "could not get module acme:my-module")
+}
+
+func TestResolveManifestPath(t *testing.T) {
+	root := t.TempDir()
+	subdir := filepath.Join(root, "src", "models")
+	test.That(t, os.MkdirAll(subdir, 0o700), test.ShouldBeNil)
+	manifestPath := filepath.Join(root, defaultManifestFilename)
+	test.That(t, os.WriteFile(manifestPath, []byte(validManifest), 0o600), test.ShouldBeNil)
+
+	found, ok := findManifest(subdir)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, found, test.ShouldEqual, manifestPath)
+
+	found, ok = findManifest(root)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, found, test.ShouldEqual, manifestPath)
+
+	// a directory named meta.json is not a manifest
+	other := t.TempDir()
+	test.That(t, os.Mkdir(filepath.Join(other, defaultManifestFilename), 0o700), test.ShouldBeNil)
+	_, ok = findManifest(other)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	wd, err := os.Getwd()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.Chdir(subdir), test.ShouldBeNil)
+	defer func() {
+		test.That(t, os.Chdir(wd), test.ShouldBeNil)
+	}()
+
+	t.Run("run from a subdirectory", func(t *testing.T) {
+		resolved := resolveManifestPath("")
+		test.That(t, resolved, test.ShouldEqual, filepath.Join("..", "..", defaultManifestFilename))
+
+		out := &bytes.Buffer{}
+		flags := flag.NewFlagSet("update", flag.ContinueOnError)
+		flags.String("module", "", "")
+		flags.Bool("validate-only", true, "")
+		err := UpdateModuleAction(cli.NewContext(&cli.App{Writer: out, ErrWriter: out}, flags, nil))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldContainSubstring, resolved+" is valid")
+	})
+
+	t.Run("explicit path", func(t *testing.T) {
+		explicit := filepath.Join(other, "custom.json")
+		test.That(t, resolveManifestPath(explicit), test.ShouldEqual, explicit)
+	})
+}
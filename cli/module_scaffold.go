@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	moduleLanguageGo     = "go"
+	moduleLanguagePython = "python"
+)
+
+// scaffoldAPI is the API of the stub model in a scaffolded module. Generic components only have
+// DoCommand, so the stub is short and works as a starting point for any kind of resource.
+const scaffoldAPI = "rdk:component:generic"
+
+// scaffoldModule writes a starter project in the given language to dir, along with a meta.json that
+// lists its model and entrypoint.
+func scaffoldModule(w io.Writer, dir, language string, moduleID moduleID, model resource.Model) error {
+	scaffold, err := newModuleScaffold(language, moduleID.name, model)
+	if err != nil {
+		return err
+	}
+	if err := scaffold.write(dir); err != nil {
+		return err
+	}
+	manifest := moduleManifest{
+		Name:       moduleID.String(),
+		Visibility: moduleVisibilityPrivate,
+		Models:     []moduleComponent{{API: scaffoldAPI, Model: model.String()}},
+		Entrypoint: scaffold.entrypoint,
+	}
+	if err := writeManifest(filepath.Join(dir, defaultManifestFilename), manifest); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "a %s module serving %s has been written to the current directory, configured by meta.json\n", language, model)
+	if language == moduleLanguageGo {
+		fmt.Fprintf(w, "run 'go mod tidy' to fetch its dependencies, then 'make' to build it\n")
+	}
+	return nil
+}
+
+// moduleScaffold is a starter project for a module. Files maps paths relative to the module's
+// directory to their contents.
+type moduleScaffold struct {
+	entrypoint string
+	files      map[string]string
+	// executable lists the files that need to be executable.
+	executable []string
+}
+
+// scaffoldFilenames returns the files a scaffold for the language would create, so that they can be
+// checked before the module is registered.
+func scaffoldFilenames(language, moduleName string) ([]string, error) {
+	scaffold, err := newModuleScaffold(language, moduleName, resource.NewModel("acme", "demo", "stub"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(scaffold.files))
+	for name := range scaffold.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// newModuleScaffold returns a minimal module in the given language that serves model as a generic
+// component.
+func newModuleScaffold(language, moduleName string, model resource.Model) (*moduleScaffold, error) {
+	switch language {
+	case moduleLanguageGo:
+		return &moduleScaffold{
+			entrypoint: "bin/" + moduleName,
+			files: map[string]string{
+				"main.go":  fmt.Sprintf(goScaffoldMain, model.Family.Namespace, model.Family.Name, model.Name),
+				"go.mod":   fmt.Sprintf(goScaffoldMod, moduleName),
+				"Makefile": strings.ReplaceAll(goScaffoldMakefile, "MODULE", moduleName),
+			},
+		}, nil
+	case moduleLanguagePython:
+		return &moduleScaffold{
+			entrypoint: "run.sh",
+			files: map[string]string{
+				"main.py":          fmt.Sprintf(pythonScaffoldMain, model.Family.Namespace, model.Family.Name, model.Name),
+				"requirements.txt": "viam-sdk\n",
+				"run.sh":           pythonScaffoldRun,
+			},
+			executable: []string{"run.sh"},
+		}, nil
+	default:
+		return nil, newValidationError(
+			errors.Errorf("unknown language %q: must be %q or %q", language, moduleLanguageGo, moduleLanguagePython))
+	}
+}
+
+// parseScaffoldModel parses the model a scaffolded module serves, which must be a full
+// namespace:family:name triplet outside of the reserved rdk namespace.
+func parseScaffoldModel(model string) (resource.Model, error) {
+	parsed, err := resource.NewModelFromString(model)
+	if err != nil {
+		return resource.Model{}, newValidationError(err)
+	}
+	if strings.Count(model, ":") != 2 || parsed.Family.Namespace == resource.ModelNamespaceRDK {
+		return resource.Model{}, newValidationError(
+			errors.Errorf("model %q must be namespace:family:name, in a namespace other than %q", model, resource.ModelNamespaceRDK))
+	}
+	return parsed, nil
+}
+
+// checkScaffoldTargets returns an error if any of the named files exist in dir, unless force is set.
+func checkScaffoldTargets(dir string, names []string, force bool) error {
+	if force {
+		return nil
+	}
+	var existing []string
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			existing = append(existing, name)
+		}
+	}
+	if len(existing) > 0 {
+		return newValidationError(errors.Errorf(
+			"%s already exist in the current directory. delete them or pass --force to overwrite them",
+			strings.Join(existing, ", ")))
+	}
+	return nil
+}
+
+// write writes the scaffold's files into dir.
+func (s *moduleScaffold) write(dir string) error {
+	for name, contents := range s.files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			return errors.Wrapf(err, "failed to write %s", name)
+		}
+	}
+	for _, name := range s.executable {
+		//nolint:gosec
+		if err := os.Chmod(filepath.Join(dir, name), 0o755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const goScaffoldMain = `// Package main is a module that serves a single generic component.
+package main
+
+import (
+	"context"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/module"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.NewModel(%q, %q, %q)
+
+func main() {
+	utils.ContextualMain(mainWithArgs, golog.NewDevelopmentLogger(model.String()))
+}
+
+func mainWithArgs(ctx context.Context, args []string, logger golog.Logger) error {
+	resource.RegisterComponent(generic.API, model, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: newComponent,
+	})
+
+	mod, err := module.NewModuleFromArgs(ctx, logger)
+	if err != nil {
+		return err
+	}
+	if err := mod.AddModelFromRegistry(ctx, generic.API, model); err != nil {
+		return err
+	}
+	err = mod.Start(ctx)
+	defer mod.Close(ctx)
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+type component struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+	logger golog.Logger
+}
+
+func newComponent(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger golog.Logger,
+) (resource.Resource, error) {
+	return &component{Named: conf.ResourceName().AsNamed(), logger: logger}, nil
+}
+
+// DoCommand echoes back the command it receives. Replace it with your component's behavior.
+func (c *component) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	return cmd, nil
+}
+`
+
+const goScaffoldMod = `module %s
+
+go 1.19
+`
+
+const goScaffoldMakefile = `bin/MODULE: go.mod *.go
+	go build -o bin/MODULE .
+
+module.tar.gz: bin/MODULE
+	tar czf module.tar.gz bin/MODULE
+`
+
+const pythonScaffoldMain = `import asyncio
+from typing import Any, ClassVar, Mapping, Optional
+
+from typing_extensions import Self
+from viam.components.generic import Generic
+from viam.module.module import Module
+from viam.proto.app.robot import ComponentConfig
+from viam.proto.common import ResourceName
+from viam.resource.base import ResourceBase
+from viam.resource.registry import Registry, ResourceCreatorRegistration
+from viam.resource.types import Model, ModelFamily
+from viam.utils import ValueTypes
+
+
+class Component(Generic):
+    MODEL: ClassVar[Model] = Model(ModelFamily(%q, %q), %q)
+
+    @classmethod
+    def new(cls, config: ComponentConfig, dependencies: Mapping[ResourceName, ResourceBase]) -> Self:
+        return cls(config.name)
+
+    async def do_command(
+        self, command: Mapping[str, ValueTypes], *, timeout: Optional[float] = None, **kwargs: Any
+    ) -> Mapping[str, ValueTypes]:
+        """Echoes back the command it receives. Replace it with your component's behavior."""
+        return command
+
+
+async def main():
+    Registry.register_resource_creator(Generic.SUBTYPE, Component.MODEL, ResourceCreatorRegistration(Component.new))
+    module = Module.from_args()
+    module.add_model_from_registry(Generic.SUBTYPE, Component.MODEL)
+    await module.start()
+
+
+if __name__ == "__main__":
+    asyncio.run(main())
+`
+
+const pythonScaffoldRun = `#!/bin/sh
+cd "$(dirname "$0")"
+if [ ! -d .venv ]; then
+	python3 -m venv .venv
+fi
+. .venv/bin/activate
+pip install -q -r requirements.txt
+exec python3 main.py "$@"
+`
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	gotoken "go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestScaffoldModule(t *testing.T) {
+	moduleID := moduleID{prefix: "acme", name: "my-module"}
+	model, err := parseScaffoldModel("acme:sensors:thermometer")
+	test.That(t, err, test.ShouldBeNil)
+
+	readManifest := func(t *testing.T, dir string) moduleManifest {
+		t.Helper()
+		manifestBytes, err := os.ReadFile(filepath.Join(dir, defaultManifestFilename))
+		test.That(t, err, test.ShouldBeNil)
+		var manifest moduleManifest
+		test.That(t, json.Unmarshal(manifestBytes, &manifest), test.ShouldBeNil)
+		return manifest
+	}
+	readFile := func(t *testing.T, path string) string {
+		t.Helper()
+		//nolint:gosec
+		contents, err := os.ReadFile(path)
+		test.That(t, err, test.ShouldBeNil)
+		return string(contents)
+	}
+
+	t.Run("go", func(t *testing.T) {
+		dir := t.TempDir()
+		var out bytes.Buffer
+		test.That(t, scaffoldModule(&out, dir, moduleLanguageGo, moduleID, model), test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldContainSubstring, "go mod tidy")
+
+		mainGo := readFile(t, filepath.Join(dir, "main.go"))
+		test.That(t, mainGo, test.ShouldContainSubstring, `resource.NewModel("acme", "sensors", "thermometer")`)
+		_, err := parser.ParseFile(gotoken.NewFileSet(), "main.go", mainGo, parser.AllErrors)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readFile(t, filepath.Join(dir, "go.mod")), test.ShouldStartWith, "module my-module\n")
+		test.That(t, readFile(t, filepath.Join(dir, "Makefile")), test.ShouldContainSubstring, "go build -o bin/my-module .")
+
+		manifest := readManifest(t, dir)
+		test.That(t, manifest.Name, test.ShouldEqual, "acme:my-module")
+		test.That(t, manifest.Models, test.ShouldResemble, []moduleComponent{{API: "rdk:component:generic", Model: "acme:sensors:thermometer"}})
+		test.That(t, manifest.Entrypoint, test.ShouldEqual, "bin/my-module")
+	})
+
+	t.Run("python", func(t *testing.T) {
+		dir := t.TempDir()
+		test.That(t, scaffoldModule(&bytes.Buffer{}, dir, moduleLanguagePython, moduleID, model), test.ShouldBeNil)
+
+		test.That(t, readFile(t, filepath.Join(dir, "main.py")), test.ShouldContainSubstring,
+			`Model(ModelFamily("acme", "sensors"), "thermometer")`)
+		test.That(t, readFile(t, filepath.Join(dir, "requirements.txt")), test.ShouldContainSubstring, "viam-sdk")
+		info, err := os.Stat(filepath.Join(dir, "run.sh"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, info.Mode().Perm()&0o100, test.ShouldNotBeZeroValue)
+
+		manifest := readManifest(t, dir)
+		test.That(t, manifest.Models[0].Model, test.ShouldEqual, "acme:sensors:thermometer")
+		test.That(t, manifest.Entrypoint, test.ShouldEqual, "run.sh")
+	})
+
+	t.Run("unknown language", func(t *testing.T) {
+		err := scaffoldModule(&bytes.Buffer{}, t.TempDir(), "rust", moduleID, model)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+	})
+}
+
+func TestCheckScaffoldTargets(t *testing.T) {
+	names, err := scaffoldFilenames(moduleLanguagePython, "my-module")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []string{"main.py", "requirements.txt", "run.sh"})
+
+	dir := t.TempDir()
+	test.That(t, checkScaffoldTargets(dir, names, false), test.ShouldBeNil)
+
+	test.That(t, os.WriteFile(filepath.Join(dir, "main.py"), []byte("print('mine')\n"), 0o600), test.ShouldBeNil)
+	err = checkScaffoldTargets(dir, names, false)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "main.py already exist")
+	test.That(t, checkScaffoldTargets(dir, names, true), test.ShouldBeNil)
+}
+
+func TestParseScaffoldModel(t *testing.T) {
+	model, err := parseScaffoldModel("acme:demo:stub")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model.String(), test.ShouldEqual, "acme:demo:stub")
+
+	for _, bad := range []string{"stub", "rdk:builtin:stub", "acme:demo", "acme:demo:stub:extra"} {
+		_, err := parseScaffoldModel(bad)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
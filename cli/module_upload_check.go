@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	apppb "go.viam.com/api/app/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// moduleUploadPlatforms are the platforms the registry accepts module uploads for, each an os/arch pair
+// or, for architectures with variants, an os/arch/variant triple. linux/arm/v7 is 32-bit Raspberry Pi
+// OS, which Go targets with GOARCH=arm GOARM=7.
+var moduleUploadPlatforms = []string{"linux/amd64", "linux/arm64", "linux/arm/v7", "darwin/amd64", "darwin/arm64"}
+
+// moduleUploadCheck is the outcome of a single check run by 'module upload --check'.
+type moduleUploadCheck struct {
+	name string
+	err  error
+}
+
+// checkModuleUpload runs every pre-upload check, so that all problems are reported at once rather than
+// one per attempt. manifest is nil when the upload is done without a meta.json.
+func (c *appClient) checkModuleUpload(
+	moduleID moduleID,
+	manifest *moduleManifest,
+	version,
+	platform,
+	tarballPath string,
+) []moduleUploadCheck {
+	var entrypoint string
+	if manifest != nil {
+		entrypoint = manifest.Entrypoint
+	}
+	checks := []moduleUploadCheck{
+		{name: "platform", err: checkModulePlatform(platform)},
+		{name: "archive", err: checkModuleTarball(tarballPath, entrypoint)},
+	}
+	versionCheck := moduleUploadCheck{name: "version"}
+	published, err := c.publishedModuleVersions(moduleID)
+	if err != nil {
+		versionCheck.err = errors.Wrap(err, "cannot fetch the published versions of the module")
+	} else {
+		versionCheck.err = checkModuleVersion(version, platform, published)
+	}
+	return append(checks, versionCheck)
+}
+
+// printModuleUploadChecks prints a pass/fail line per check and returns an error if any check failed.
+func printModuleUploadChecks(w io.Writer, checks []moduleUploadCheck) error {
+	var failed int
+	for _, check := range checks {
+		if check.err != nil {
+			failed++
+			fmt.Fprintf(w, "FAIL  %s: %s\n", check.name, check.err)
+			continue
+		}
+		fmt.Fprintf(w, "ok    %s\n", check.name)
+	}
+	if failed > 0 {
+		return newValidationError(errors.Errorf("%d of %d upload checks failed", failed, len(checks)))
+	}
+	fmt.Fprintln(w, "all upload checks passed; nothing was uploaded")
+	return nil
+}
+
+// checkModulePlatform checks that platform is one of moduleUploadPlatforms. The whole platform must
+// match, so linux/arm, which leaves out the variant, is rejected along with unknown variants.
+func checkModulePlatform(platform string) error {
+	for _, p := range moduleUploadPlatforms {
+		if platform == p {
+			return nil
+		}
+	}
+	return errors.Errorf("platform %q is not supported, must be one of: %s", platform, strings.Join(moduleUploadPlatforms, ", "))
+}
+
+// checkModuleTarball checks that tarballPath is a gzipped tar archive and, if entrypoint is set, that the
+// entrypoint is a file inside it.
+func checkModuleTarball(tarballPath, entrypoint string) error {
+	entrypoint = cleanArchivePath(entrypoint)
+	var foundEntrypoint bool
+	err := walkModuleTarball(tarballPath, func(header *tar.Header) error {
+		if entrypoint != "" && cleanArchivePath(header.Name) == entrypoint {
+			if header.Typeflag == tar.TypeDir {
+				return errors.Errorf("entrypoint %q is a directory in %s", entrypoint, tarballPath)
+			}
+			foundEntrypoint = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if entrypoint != "" && !foundEntrypoint {
+		return errors.Errorf("entrypoint %q from the meta.json is not in %s", entrypoint, tarballPath)
+	}
+	return nil
+}
+
+// walkModuleTarball calls fn with the header of each entry in the gzipped tar archive at tarballPath,
+// stopping at the first error.
+func walkModuleTarball(tarballPath string, fn func(header *tar.Header) error) error {
+	// TODO(APP-2226): support .tar.xz
+	if !strings.HasSuffix(tarballPath, ".tar.gz") {
+		return errors.New("you must upload your module in the form of a .tar.gz")
+	}
+	//nolint:gosec
+	file, err := os.Open(tarballPath)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.Wrapf(err, "%s is not a valid gzip file", tarballPath)
+	}
+	//nolint:errcheck
+	defer gz.Close()
+
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "%s is not a valid tar archive", tarballPath)
+		}
+		if err := fn(header); err != nil {
+			return err
+		}
+	}
+}
+
+func cleanArchivePath(name string) string {
+	if name == "" {
+		return ""
+	}
+	return path.Clean(strings.TrimPrefix(name, "./"))
+}
+
+// checkModuleVersion checks that version is a semantic version that can be uploaded for platform. Each
+// version holds an upload per platform, so version must either be newer than every published version or
+// be a published version that has no upload for platform yet.
+func checkModuleVersion(version, platform string, published []*apppb.VersionHistory) error {
+	if _, err := parseVersion(version); err != nil {
+		return err
+	}
+	var latest string
+	for _, v := range published {
+		p := v.GetVersion()
+		if _, err := parseVersion(p); err != nil {
+			// versions the registry accepted before it required semver can't be compared against
+			continue
+		}
+		if cmp, _ := compareVersions(version, p); cmp == 0 {
+			for _, file := range v.GetFiles() {
+				if file.GetPlatform() == platform {
+					return errors.Errorf("version %s has already been published for %s", version, platform)
+				}
+			}
+			return nil
+		}
+		if cmp, _ := compareVersions(p, latest); latest == "" || cmp > 0 {
+			latest = p
+		}
+	}
+	if latest == "" {
+		return nil
+	}
+	if cmp, _ := compareVersions(version, latest); cmp < 0 {
+		return errors.Errorf("version %s is older than the latest published version %s", version, latest)
+	}
+	return nil
+}
+
+// publishedModuleVersions returns the versions of a module in the registry, with the platforms uploaded
+// for each, which is none if the module has not been created yet.
+func (c *appClient) publishedModuleVersions(moduleID moduleID) ([]*apppb.VersionHistory, error) {
+	if err := c.ensureLoggedIn(); err != nil {
+		return nil, err
+	}
+	resp, err := c.client.GetModule(c.c.Context, &apppb.GetModuleRequest{ModuleId: moduleID.String()})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.GetModule().GetVersions(), nil
+}
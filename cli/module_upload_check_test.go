@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	apppb "go.viam.com/api/app/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// injectModuleAppClient is a logged in app client that serves a single module from GetModule.
+type injectModuleAppClient struct {
+	apppb.AppServiceClient
+	module *apppb.Module
+	err    error
+}
+
+func (i *injectModuleAppClient) GetModule(
+	ctx context.Context, in *apppb.GetModuleRequest, opts ...grpc.CallOption,
+) (*apppb.GetModuleResponse, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	return &apppb.GetModuleResponse{Module: i.module}, nil
+}
+
+// writeTarball writes a .tar.gz containing a file for each of the given names and returns its path.
+func writeTarball(t *testing.T, names ...string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	for _, name := range names {
+		contents := []byte("#!/bin/sh\n")
+		test.That(t, archive.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(contents))}), test.ShouldBeNil)
+		_, err := archive.Write(contents)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, archive.Close(), test.ShouldBeNil)
+	test.That(t, gz.Close(), test.ShouldBeNil)
+	tarballPath := filepath.Join(t.TempDir(), "module.tar.gz")
+	test.That(t, os.WriteFile(tarballPath, buf.Bytes(), 0o600), test.ShouldBeNil)
+	return tarballPath
+}
+
+func TestCheckModuleUpload(t *testing.T) {
+	moduleID := moduleID{prefix: "acme", name: "my-module"}
+	manifest := &moduleManifest{Name: "acme:my-module", Entrypoint: "./bin/module"}
+	published := &apppb.Module{Versions: []*apppb.VersionHistory{
+		{Version: "0.1.0", Files: []*apppb.Uploads{{Platform: "linux/amd64"}}},
+		{Version: "0.2.0", Files: []*apppb.Uploads{{Platform: "linux/amd64"}, {Platform: "darwin/arm64"}}},
+		{Version: "0.1.5", Files: []*apppb.Uploads{{Platform: "linux/amd64"}}},
+	}}
+	newClient := func(appServiceClient apppb.AppServiceClient) *appClient {
+		cCtx := cli.NewContext(&cli.App{Writer: &bytes.Buffer{}, ErrWriter: &bytes.Buffer{}}, nil, nil)
+		return &appClient{c: cCtx, conf: &config{}, client: appServiceClient}
+	}
+	failures := func(checks []moduleUploadCheck) map[string]string {
+		failed := map[string]string{}
+		for _, check := range checks {
+			if check.err != nil {
+				failed[check.name] = check.err.Error()
+			}
+		}
+		return failed
+	}
+	validTarball := writeTarball(t, "bin/module", "README.md")
+
+	t.Run("passes", func(t *testing.T) {
+		client := newClient(&injectModuleAppClient{module: published})
+		checks := client.checkModuleUpload(moduleID, manifest, "0.3.0", "linux/arm64", validTarball)
+		test.That(t, failures(checks), test.ShouldBeEmpty)
+
+		var out bytes.Buffer
+		test.That(t, printModuleUploadChecks(&out, checks), test.ShouldBeNil)
+		test.That(t, out.String(), test.ShouldEqual,
+			"ok    platform\nok    archive\nok    version\nall upload checks passed; nothing was uploaded\n")
+	})
+
+	t.Run("passes for a 32-bit raspberry pi", func(t *testing.T) {
+		client := newClient(&injectModuleAppClient{module: published})
+		checks := client.checkModuleUpload(moduleID, manifest, "0.3.0", "linux/arm/v7", validTarball)
+		test.That(t, failures(checks), test.ShouldBeEmpty)
+	})
+
+	t.Run("passes for a new platform of a published version", func(t *testing.T) {
+		client := newClient(&injectModuleAppClient{module: published})
+		test.That(t, failures(client.checkModuleUpload(moduleID, manifest, "0.2.0", "linux/arm64", validTarball)), test.ShouldBeEmpty)
+		test.That(t, failures(client.checkModuleUpload(moduleID, manifest, "0.1.0", "darwin/arm64", validTarball)), test.ShouldBeEmpty)
+	})
+
+	t.Run("passes for an unpublished module without a meta.json", func(t *testing.T) {
+		client := newClient(&injectModuleAppClient{err: status.Error(codes.NotFound, "no such module")})
+		checks := client.checkModuleUpload(moduleID, nil, "0.0.1", "darwin/amd64", writeTarball(t, "anything"))
+		test.That(t, failures(checks), test.ShouldBeEmpty)
+	})
+
+	t.Run("reports every failure", func(t *testing.T) {
+		client := newClient(&injectModuleAppClient{module: published})
+		checks := client.checkModuleUpload(moduleID, manifest, "0.2", "windows/amd64", validTarball+".missing")
+		test.That(t, failures(checks), test.ShouldHaveLength, 3)
+
+		var out bytes.Buffer
+		err := printModuleUploadChecks(&out, checks)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldEqual, "3 of 3 upload checks failed")
+		test.That(t, out.String(), test.ShouldContainSubstring, "FAIL  platform: ")
+		test.That(t, out.String(), test.ShouldContainSubstring, "FAIL  version: ")
+	})
+
+	for _, tc := range []struct {
+		name     string
+		version  string
+		platform string
+		tarball  func(t *testing.T) string
+		appErr   error
+		check    string
+		expected string
+	}{
+		{
+			name:     "unsupported platform",
+			platform: "linux/386",
+			check:    "platform",
+			expected: `platform "linux/386" is not supported, must be one of: linux/amd64, linux/arm64, linux/arm/v7, darwin/amd64, darwin/arm64`,
+		},
+		{
+			name:     "platform without its variant",
+			platform: "linux/arm",
+			check:    "platform",
+			expected: `platform "linux/arm" is not supported`,
+		},
+		{
+			name:     "unsupported platform variant",
+			platform: "linux/arm/v6",
+			check:    "platform",
+			expected: `platform "linux/arm/v6" is not supported`,
+		},
+		{
+			name:     "wrong extension",
+			tarball:  func(t *testing.T) string { return filepath.Join(t.TempDir(), "module.zip") },
+			check:    "archive",
+			expected: "you must upload your module in the form of a .tar.gz",
+		},
+		{
+			name: "not gzip",
+			tarball: func(t *testing.T) string {
+				p := filepath.Join(t.TempDir(), "module.tar.gz")
+				test.That(t, os.WriteFile(p, []byte("not a gzip file"), 0o600), test.ShouldBeNil)
+				return p
+			},
+			check:    "archive",
+			expected: "is not a valid gzip file",
+		},
+		{
+			name: "not tar",
+			tarball: func(t *testing.T) string {
+				var buf bytes.Buffer
+				gz := gzip.NewWriter(&buf)
+				_, err := gz.Write([]byte("plain text that is not a tar archive, but long enough to be read as a header"))
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, gz.Close(), test.ShouldBeNil)
+				p := filepath.Join(t.TempDir(), "module.tar.gz")
+				test.That(t, os.WriteFile(p, buf.Bytes(), 0o600), test.ShouldBeNil)
+				return p
+			},
+			check:    "archive",
+			expected: "is not a valid tar archive",
+		},
+		{
+			name:     "missing entrypoint",
+			tarball:  func(t *testing.T) string { return writeTarball(t, "bin/other") },
+			check:    "archive",
+			expected: `entrypoint "bin/module" from the meta.json is not in`,
+		},
+		{
+			name:     "not semver",
+			version:  "latest",
+			check:    "version",
+			expected: `"latest" is not a semantic version`,
+		},
+		{
+			name:     "already published",
+			version:  "0.2.0",
+			check:    "version",
+			expected: "version 0.2.0 has already been published for linux/amd64",
+		},
+		{
+			name:     "older than published",
+			version:  "0.1.9",
+			check:    "version",
+			expected: "version 0.1.9 is older than the latest published version 0.2.0",
+		},
+		{
+			name:     "published versions unavailable",
+			appErr:   status.Error(codes.Unavailable, "try again"),
+			check:    "version",
+			expected: "cannot fetch the published versions of the module",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			version, platform, tarball := "0.3.0", "linux/amd64", validTarball
+			if tc.version != "" {
+				version = tc.version
+			}
+			if tc.platform != "" {
+				platform = tc.platform
+			}
+			if tc.tarball != nil {
+				tarball = tc.tarball(t)
+			}
+			client := newClient(&injectModuleAppClient{module: published, err: tc.appErr})
+			failed := failures(client.checkModuleUpload(moduleID, manifest, version, platform, tarball))
+			test.That(t, failed, test.ShouldHaveLength, 1)
+			test.That(t, failed[tc.check], test.ShouldContainSubstring, tc.expected)
+		})
+	}
+}
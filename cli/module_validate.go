@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// manifestProblemKind is how a manifestProblem affects the meta.json.
+type manifestProblemKind int
+
+const (
+	// manifestError makes the meta.json unusable.
+	manifestError manifestProblemKind = iota
+	// manifestPlaceholder is a field still empty as 'module create' wrote it, which must be filled in.
+	manifestPlaceholder
+	// manifestWarning is worth pointing out, but does not stop the meta.json from being used.
+	manifestWarning
+)
+
+// manifestProblem is a single thing wrong with a meta.json.
+type manifestProblem struct {
+	// line is the 1-based line of the problem, or 0 if it does not belong to a line (e.g. a missing field).
+	line  int
+	field string
+	msg   string
+	kind  manifestProblemKind
+}
+
+func (p manifestProblem) String() string {
+	var sb strings.Builder
+	if p.line > 0 {
+		fmt.Fprintf(&sb, "line %d: ", p.line)
+	}
+	if p.field != "" {
+		fmt.Fprintf(&sb, "%s: ", p.field)
+	}
+	sb.WriteString(p.msg)
+	return sb.String()
+}
+
+// manifestValidationError lists everything wrong with a meta.json.
+type manifestValidationError struct {
+	manifestPath string
+	problems     []manifestProblem
+}
+
+func (e *manifestValidationError) Error() string {
+	lines := make([]string, 0, len(e.problems)+1)
+	header := fmt.Sprintf("%s has placeholders to fill in:", e.manifestPath)
+	for _, p := range e.problems {
+		if p.kind == manifestError {
+			header = fmt.Sprintf("%s is invalid:", e.manifestPath)
+			break
+		}
+	}
+	lines = append(lines, header)
+	for _, p := range e.problems {
+		lines = append(lines, "\t"+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validateManifestFile checks the meta.json at manifestPath, returning a validation error wrapping a
+// *manifestValidationError that lists every problem found rather than stopping at the first one.
+// Warnings are printed to w instead.
+func validateManifestFile(w io.Writer, manifestPath string) error {
+	//nolint:gosec
+	manifestBytes, err := os.ReadFile(manifestPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return errors.Wrapf(err, "cannot find %s", manifestPath)
+		}
+		return err
+	}
+	var problems []manifestProblem
+	for _, p := range validateManifest(manifestBytes) {
+		if p.kind == manifestWarning {
+			warningf(w, "%s: %s", manifestPath, p)
+			continue
+		}
+		problems = append(problems, p)
+	}
+	if len(problems) > 0 {
+		return newValidationError(&manifestValidationError{manifestPath: manifestPath, problems: problems})
+	}
+	return nil
+}
+
+// validateManifest returns all of the problems with the given meta.json contents.
+func validateManifest(manifestBytes []byte) []manifestProblem {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(manifestBytes, &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return []manifestProblem{{line: lineOfOffset(manifestBytes, syntaxErr.Offset), msg: syntaxErr.Error()}}
+		}
+		return []manifestProblem{{line: 1, msg: "must be a JSON object"}}
+	}
+	lines, err := jsonValueLines(manifestBytes)
+	if err != nil {
+		return []manifestProblem{{msg: err.Error()}}
+	}
+
+	var problems []manifestProblem
+	addProblemKind := func(kind manifestProblemKind, field, format string, a ...interface{}) {
+		problems = append(problems, manifestProblem{line: lines[field], field: field, msg: fmt.Sprintf(format, a...), kind: kind})
+	}
+	addProblem := func(field, format string, a ...interface{}) {
+		addProblemKind(manifestError, field, format, a...)
+	}
+	requireString := func(field string) (string, bool) {
+		v, ok := raw[field]
+		if !ok {
+			addProblem(field, "is required")
+			return "", false
+		}
+		s, ok := v.(string)
+		if !ok {
+			addProblem(field, "must be a string")
+			return "", false
+		}
+		return s, true
+	}
+
+	known := map[string]bool{"name": true, "visibility": true, "url": true, "description": true, "models": true, "entrypoint": true}
+	var unknown []string
+	for field := range raw {
+		if !known[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+	// fields that are not known are ignored, as they always have been, but may be misspellings.
+	for _, field := range unknown {
+		addProblemKind(manifestWarning, field, "unknown field, it is ignored")
+	}
+
+	if name, ok := requireString("name"); ok {
+		if name == "" {
+			addProblem("name", "must not be empty")
+		} else if _, err := parseModuleID(name); err != nil {
+			addProblem("name", "%s", err)
+		}
+	}
+
+	if visibility, ok := requireString("visibility"); ok {
+		if _, err := visibilityToProto(moduleVisibility(visibility)); err != nil {
+			addProblem("visibility", "must be either %q or %q", moduleVisibilityPublic, moduleVisibilityPrivate)
+		}
+	}
+
+	for _, field := range []string{"url", "description"} {
+		if v, ok := raw[field]; ok {
+			if _, ok := v.(string); !ok {
+				addProblem(field, "must be a string")
+			}
+		}
+	}
+
+	if entrypoint, ok := requireString("entrypoint"); ok {
+		switch cleaned := path.Clean(entrypoint); {
+		case entrypoint == "":
+			addProblemKind(manifestPlaceholder, "entrypoint", "fill in the path of the module's executable in its archive, e.g. ./bin/module")
+		case path.IsAbs(entrypoint):
+			addProblem("entrypoint", "must be a path relative to the root of the module archive, not %q", entrypoint)
+		case cleaned == ".." || strings.HasPrefix(cleaned, "../"):
+			addProblem("entrypoint", "must be inside the module archive, not %q", entrypoint)
+		}
+	}
+
+	switch models := raw["models"].(type) {
+	case nil:
+		if _, ok := raw["models"]; ok {
+			addProblem("models", "must be a list")
+		} else {
+			addProblem("models", "is required")
+		}
+	case []interface{}:
+		for i, m := range models {
+			field := fmt.Sprintf("models[%d]", i)
+			model, ok := m.(map[string]interface{})
+			if !ok {
+				addProblem(field, "must be an object with an api and model")
+				continue
+			}
+			api, _ := model["api"].(string)
+			if api == "" {
+				addProblemKind(manifestPlaceholder, field+".api", "fill in the api the model implements, e.g. rdk:component:base")
+			} else if _, err := resource.NewAPIFromString(api); err != nil {
+				addProblem(field+".api", "%q is not a valid api, it must be in the form namespace:type:subtype (e.g. rdk:component:base)",
+					api)
+			}
+			modelName, _ := model["model"].(string)
+			if modelName == "" {
+				addProblemKind(manifestPlaceholder, field+".model", "fill in the name of the model, e.g. acme:demo:mybase")
+			} else if parsed, err := resource.NewModelFromString(modelName); err != nil || parsed.String() != modelName {
+				// a bare model name parses as a builtin rdk model, which modules cannot provide.
+				addProblem(field+".model", "%q is not a valid model, it must be in the form namespace:family:name (e.g. acme:demo:mybase)",
+					modelName)
+			}
+		}
+	default:
+		addProblem("models", "must be a list")
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].line < problems[j].line
+	})
+	return problems
+}
+
+// jsonValueLines walks a valid JSON document and returns the line each value is on, keyed by its path
+// (e.g. "models[1].api"). Paths of values inside lists are indexed by position.
+func jsonValueLines(data []byte) (map[string]int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	lines := map[string]int{}
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		offset := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		// the offset is before any whitespace preceding the token, so skip past it.
+		for offset < int64(len(data)) && strings.ContainsRune(" \t\r\n:,", rune(data[offset])) {
+			offset++
+		}
+		if path != "" {
+			lines[path] = lineOfOffset(data, offset)
+		}
+
+		delim, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		switch delim {
+		case '{':
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				childPath := key
+				if path != "" {
+					childPath = path + "." + key
+				}
+				if err := walk(childPath); err != nil {
+					return err
+				}
+			}
+		case '[':
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+		// consume the closing delimiter.
+		_, err = dec.Token()
+		return err
+	}
+
+	if err := walk(""); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// lineOfOffset returns the 1-based line of the given byte offset in data.
+func lineOfOffset(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"go.viam.com/test"
+)
+
+const validManifest = `{
+  "name": "acme:my-module",
+  "visibility": "public",
+  "url": "https://github.com/acme/my-module",
+  "description": "a module",
+  "models": [
+    {
+      "api": "rdk:component:base",
+      "model": "acme:demo:mybase"
+    }
+  ],
+  "entrypoint": "./bin/module"
+}`
+
+func TestValidateManifest(t *testing.T) {
+	test.That(t, validateManifest([]byte(validManifest)), test.ShouldBeEmpty)
+
+	for _, tc := range []struct {
+		name     string
+		manifest string
+		expected []string
+	}{
+		{
+			name:     "syntax error",
+			manifest: "{\n  \"name\": \"acme:my-module\",\n  \"visibility\": \"public\"\n  \"models\": []\n}",
+			expected: []string{"line 4: invalid character '\"' after object key:value pair"},
+		},
+		{
+			name:     "not an object",
+			manifest: `["acme:my-module"]`,
+			expected: []string{"line 1: must be a JSON object"},
+		},
+		{
+			name:     "missing fields",
+			manifest: `{}`,
+			expected: []string{
+				"name: is required",
+				"visibility: is required",
+				"entrypoint: is required",
+				"models: is required",
+			},
+		},
+		{
+			name: "all problems are reported with lines",
+			manifest: `{
+  "name": "a:b:c",
+  "visibility": "secret",
+  "description": 5,
+  "models": [
+    {
+      "api": "base",
+      "model": "mybase"
+    },
+    "rdk:component:base"
+  ],
+  "entrypoint": "/usr/bin/module",
+  "visiblity": "public"
+}`,
+			expected: []string{
+				`line 2: name: invalid module name 'a:b:c'`,
+				`line 3: visibility: must be either "public" or "private"`,
+				"line 4: description: must be a string",
+				`line 7: models[0].api: "base" is not a valid api`,
+				`line 8: models[0].model: "mybase" is not a valid model`,
+				"line 10: models[1]: must be an object with an api and model",
+				`line 12: entrypoint: must be a path relative to the root of the module archive, not "/usr/bin/module"`,
+				"line 13: visiblity: unknown field, it is ignored",
+			},
+		},
+		{
+			name: "wrong types",
+			manifest: `{
+  "name": 1,
+  "visibility": "private",
+  "models": {"api": "rdk:component:base"},
+  "entrypoint": "../module"
+}`,
+			expected: []string{
+				"line 2: name: must be a string",
+				"line 4: models: must be a list",
+				`line 5: entrypoint: must be inside the module archive, not "../module"`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := validateManifest([]byte(tc.manifest))
+			test.That(t, problems, test.ShouldHaveLength, len(tc.expected))
+			for i, expected := range tc.expected {
+				test.That(t, problems[i].String(), test.ShouldStartWith, expected)
+			}
+		})
+	}
+
+	t.Run("unknown fields are warnings", func(t *testing.T) {
+		problems := validateManifest([]byte(strings.Replace(validManifest, "{", `{"build": "make module.tar.gz",`, 1)))
+		test.That(t, problems, test.ShouldHaveLength, 1)
+		test.That(t, problems[0].field, test.ShouldEqual, "build")
+		test.That(t, problems[0].kind, test.ShouldEqual, manifestWarning)
+	})
+
+	t.Run("the template from module create has placeholders", func(t *testing.T) {
+		// this is what 'module create' writes without --language.
+		template, err := json.MarshalIndent(moduleManifest{
+			Name:       "acme:my-module",
+			Visibility: moduleVisibilityPrivate,
+			Models:     []moduleComponent{{}},
+		}, "", "  ")
+		test.That(t, err, test.ShouldBeNil)
+		problems := validateManifest(template)
+		test.That(t, problems, test.ShouldHaveLength, 3)
+		for _, p := range problems {
+			test.That(t, p.kind, test.ShouldEqual, manifestPlaceholder)
+			test.That(t, p.msg, test.ShouldStartWith, "fill in")
+		}
+		msg := (&manifestValidationError{manifestPath: "meta.json", problems: problems}).Error()
+		test.That(t, msg, test.ShouldStartWith, "meta.json has placeholders to fill in:")
+	})
+}
+
+func TestUpdateModuleValidateOnly(t *testing.T) {
+	dir := t.TempDir()
+	validPath := filepath.Join(dir, "valid.json")
+	test.That(t, os.WriteFile(validPath, []byte(validManifest), 0o600), test.ShouldBeNil)
+	invalidPath := filepath.Join(dir, "invalid.json")
+	test.That(t, os.WriteFile(invalidPath, []byte(`{"name": "acme:my-module", "visibility": "secret"}`), 0o600), test.ShouldBeNil)
+
+	newContext := func(manifestPath string) (*cli.Context, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		flags := flag.NewFlagSet("update", flag.ContinueOnError)
+		flags.String("module", manifestPath, "")
+		flags.Bool("validate-only", true, "")
+		return cli.NewContext(&cli.App{Writer: out, ErrWriter: out}, flags, nil), out
+	}
+
+	// neither of these need to be logged in since nothing is sent.
+	c, out := newContext(validPath)
+	test.That(t, UpdateModuleAction(c), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldContainSubstring, "valid.json is valid")
+
+	c, _ = newContext(invalidPath)
+	err := UpdateModuleAction(c)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid.json is invalid")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "visibility: must be either")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "entrypoint: is required")
+
+	// unknown fields, which were always ignored, do not stop an update.
+	extraPath := filepath.Join(dir, "extra.json")
+	extra := strings.Replace(validManifest, "{", `{"build": "make module.tar.gz",`, 1)
+	test.That(t, os.WriteFile(extraPath, []byte(extra), 0o600), test.ShouldBeNil)
+	c, out = newContext(extraPath)
+	test.That(t, UpdateModuleAction(c), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldContainSubstring, "build: unknown field, it is ignored")
+	test.That(t, out.String(), test.ShouldContainSubstring, "extra.json is valid")
+
+	c, _ = newContext(filepath.Join(dir, "missing.json"))
+	err = UpdateModuleAction(c)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot find")
+}
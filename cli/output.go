@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/multierr"
+)
+
+const (
+	outputFlag = "output"
+	forceFlag  = "force"
+)
+
+// noOutputCommands are the commands, by their full names, that do not get output flags, nor do their
+// subcommands: interactive ones, whose output is meant for a terminal, and ones that log in or out, whose
+// prompts must be seen and whose tokens should not end up in a file.
+var noOutputCommands = map[string]bool{
+	"login":            true,
+	"logout":           true,
+	"robot part shell": true,
+}
+
+// AddOutputFlags gives every command with an action, at any depth, an --output flag that writes what the
+// command prints to a file instead of stdout, and a --force flag to overwrite that file. Logs and errors
+// still go to stderr. Commands that already have a --force flag keep it, and it also allows overwriting
+// the output file. The commands in noOutputCommands are skipped.
+func AddOutputFlags(cmds []*cli.Command) {
+	addOutputFlags(cmds, "")
+}
+
+func addOutputFlags(cmds []*cli.Command, parent string) {
+	for _, cmd := range cmds {
+		name := strings.TrimPrefix(parent+" "+cmd.Name, " ")
+		if noOutputCommands[name] {
+			continue
+		}
+		addOutputFlags(cmd.Subcommands, name)
+		if cmd.Action == nil {
+			continue
+		}
+		cmd.Flags = append(cmd.Flags, &cli.PathFlag{
+			Name:  outputFlag,
+			Usage: "write output to `FILE` instead of stdout. logs are still written to stderr",
+		})
+		if !hasFlag(cmd, forceFlag) {
+			cmd.Flags = append(cmd.Flags, &cli.BoolFlag{
+				Name:  forceFlag,
+				Usage: "overwrite the --output file if it already exists",
+			})
+		}
+		cmd.Action = withOutputFile(cmd.Action)
+	}
+}
+
+func hasFlag(cmd *cli.Command, name string) bool {
+	for _, f := range cmd.Flags {
+		for _, n := range f.Names() {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// withOutputFile runs action with the app's writer replaced by the --output file, if one is given.
+func withOutputFile(action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) (err error) {
+		path := c.Path(outputFlag)
+		if path == "" {
+			return action(c)
+		}
+		f, err := createOutputFile(path, c.Bool(forceFlag))
+		if err != nil {
+			return err
+		}
+		stdout := c.App.Writer
+		c.App.Writer = f
+		defer func() {
+			c.App.Writer = stdout
+			err = multierr.Combine(err, f.Close())
+		}()
+		return action(c)
+	}
+}
+
+// createOutputFile creates the file at path for output, refusing to replace an existing file unless force is set.
+func createOutputFile(path string, force bool) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	//nolint:gosec
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, newValidationError(errors.Errorf("%s already exists. pass --%s to overwrite it", path, forceFlag))
+		}
+		return nil, errors.Wrap(err, "could not create output file")
+	}
+	return f, nil
+}
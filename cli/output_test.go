@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/urfave/cli/v2"
+	"go.viam.com/test"
+)
+
+func TestAddOutputFlags(t *testing.T) {
+	newApp := func() (*cli.App, *bytes.Buffer, *bytes.Buffer) {
+		var out, errOut bytes.Buffer
+		app := &cli.App{
+			Writer:         &out,
+			ErrWriter:      &errOut,
+			ExitErrHandler: func(*cli.Context, error) {},
+			Commands: []*cli.Command{{
+				Name: "robots",
+				Subcommands: []*cli.Command{{
+					Name: "list",
+					Action: func(c *cli.Context) error {
+						warningf(c.App.ErrWriter, "this is a log")
+						fmt.Fprintln(c.App.Writer, `[{"name":"robot1"}]`)
+						return nil
+					},
+				}},
+			}},
+		}
+		AddOutputFlags(app.Commands)
+		return app, &out, &errOut
+	}
+	path := filepath.Join(t.TempDir(), "robots.json")
+
+	app, out, errOut := newApp()
+	test.That(t, app.Run([]string{"viam", "robots", "list", "--output", path}), test.ShouldBeNil)
+	//nolint:gosec
+	written, err := os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(written), test.ShouldEqual, "[{\"name\":\"robot1\"}]\n")
+	test.That(t, out.String(), test.ShouldBeEmpty)
+	test.That(t, errOut.String(), test.ShouldContainSubstring, "this is a log")
+	test.That(t, app.Writer, test.ShouldEqual, out)
+
+	// an existing file is only replaced with --force.
+	app, out, _ = newApp()
+	err = app.Run([]string{"viam", "robots", "list", "--output", path})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, errorCategoryOf(err), test.ShouldEqual, categoryValidation)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "pass --force")
+	test.That(t, out.String(), test.ShouldBeEmpty)
+
+	test.That(t, os.WriteFile(path, []byte("stale contents that are longer than the output\n"), 0o600), test.ShouldBeNil)
+	app, _, _ = newApp()
+	test.That(t, app.Run([]string{"viam", "robots", "list", "--output", path, "--force"}), test.ShouldBeNil)
+	//nolint:gosec
+	written, err = os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(written), test.ShouldEqual, "[{\"name\":\"robot1\"}]\n")
+
+	// without --output, output still goes to stdout.
+	app, out, _ = newApp()
+	test.That(t, app.Run([]string{"viam", "robots", "list"}), test.ShouldBeNil)
+	test.That(t, out.String(), test.ShouldEqual, "[{\"name\":\"robot1\"}]\n")
+
+	// commands with their own --force keep it.
+	cmd := &cli.Command{
+		Name:   "create",
+		Flags:  []cli.Flag{&cli.BoolFlag{Name: "force", Usage: "overwrite files"}},
+		Action: func(c *cli.Context) error { return nil },
+	}
+	AddOutputFlags([]*cli.Command{cmd})
+	test.That(t, cmd.Flags, test.ShouldHaveLength, 2)
+	test.That(t, cmd.Flags[0].Names(), test.ShouldResemble, []string{"force"})
+	test.That(t, cmd.Flags[1].Names(), test.ShouldResemble, []string{"output"})
+
+	// interactive and auth commands, and their subcommands, are left alone.
+	action := func(c *cli.Context) error { return nil }
+	login := &cli.Command{
+		Name:        "login",
+		Flags:       []cli.Flag{&cli.BoolFlag{Name: LoginFlagDevice}},
+		Action:      action,
+		Subcommands: []*cli.Command{{Name: "print-access-token", Action: action}},
+	}
+	shell := &cli.Command{Name: "shell", Action: action}
+	status := &cli.Command{Name: "status", Action: action}
+	AddOutputFlags([]*cli.Command{login, {
+		Name:        "robot",
+		Subcommands: []*cli.Command{{Name: "part", Subcommands: []*cli.Command{shell, status}}},
+	}})
+	test.That(t, login.Flags, test.ShouldHaveLength, 1)
+	test.That(t, login.Subcommands[0].Flags, test.ShouldBeEmpty)
+	test.That(t, shell.Flags, test.ShouldBeEmpty)
+	test.That(t, status.Flags, test.ShouldHaveLength, 2)
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	apppb "go.viam.com/api/app/v1"
+	commonpb "go.viam.com/api/common/v1"
+	robotpb "go.viam.com/api/robot/v1"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/resource"
+)
+
+// partStatusDialTimeout bounds how long 'robot part status' waits to connect to a part, so that an
+// offline part is reported as unhealthy rather than hanging the command.
+const partStatusDialTimeout = 10 * time.Second
+
+// The statuses of a resource in a part's health.
+const (
+	resourceStatusConfigured = "configured"
+	resourceStatusError      = "error"
+)
+
+// partHealthJSON is how 'robot part status' reports a part with --format json.
+type partHealthJSON struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Main       bool      `json:"main"`
+	LastAccess time.Time `json:"last_access"`
+	// Healthy is true if the part could be reached and every resource on it is configured.
+	Healthy bool `json:"healthy"`
+	// Errors are problems with the part as a whole, such as not being able to connect to it.
+	Errors    []string             `json:"errors,omitempty"`
+	Resources []resourceHealthJSON `json:"resources"`
+}
+
+// resourceHealthJSON is the health of a single resource on a part.
+type resourceHealthJSON struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"`
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// partHealth connects to part and checks the health of its resources. Failing to connect makes the part
+// unhealthy rather than being an error, since monitoring an offline part should report it as such.
+func (c *appClient) partHealth(
+	ctx context.Context,
+	orgStr, locStr, robotStr string,
+	part *apppb.RobotPart,
+	logger golog.Logger,
+) (partHealthJSON, error) {
+	ctx, cancel := context.WithTimeout(ctx, partStatusDialTimeout)
+	defer cancel()
+
+	dialCtx, fqdn, rpcOpts, err := c.prepareDial(ctx, orgStr, locStr, robotStr, part.GetId(), false)
+	if err != nil {
+		return partHealthJSON{}, err
+	}
+	conn, err := grpc.Dial(dialCtx, fqdn, logger, rpcOpts...)
+	if err != nil {
+		return newUnreachablePartHealth(part, err), nil
+	}
+	defer func() {
+		utils.UncheckedError(conn.Close())
+	}()
+	return newPartHealth(ctx, part, robotpb.NewRobotServiceClient(conn)), nil
+}
+
+// newPartHealth returns the health of part, for which robotClient is connected to the part. A resource
+// is in error if the part cannot report its status, or if it is in the part's config but not running
+// on the part, which is what happens when it fails to build.
+func newPartHealth(ctx context.Context, part *apppb.RobotPart, robotClient robotpb.RobotServiceClient) partHealthJSON {
+	health := newUnreachablePartHealth(part, nil)
+	resp, err := robotClient.ResourceNames(ctx, &robotpb.ResourceNamesRequest{})
+	if err != nil {
+		health.Errors = append(health.Errors, fmt.Sprintf("could not list the part's resources: %s", err))
+		return health
+	}
+
+	running := make(map[string]bool)
+	for _, name := range resp.GetResources() {
+		if name.GetNamespace() == string(resource.APINamespaceRDKInternal) {
+			continue
+		}
+		running[name.GetName()] = true
+		resourceHealth := resourceHealthJSON{
+			Name:   name.GetName(),
+			Type:   strings.Join([]string{name.GetNamespace(), name.GetType(), name.GetSubtype()}, ":"),
+			Status: resourceStatusConfigured,
+		}
+		if _, err := robotClient.GetStatus(ctx, &robotpb.GetStatusRequest{ResourceNames: []*commonpb.ResourceName{name}}); err != nil {
+			resourceHealth.Status = resourceStatusError
+			resourceHealth.Errors = []string{err.Error()}
+		}
+		health.Resources = append(health.Resources, resourceHealth)
+	}
+	for _, configured := range configuredResources(part) {
+		if running[configured.Name] {
+			continue
+		}
+		configured.Status = resourceStatusError
+		configured.Errors = []string{"configured but not running on the part, check the part's logs for why it failed to build"}
+		health.Resources = append(health.Resources, configured)
+	}
+	sort.Slice(health.Resources, func(i, j int) bool {
+		return health.Resources[i].Name < health.Resources[j].Name
+	})
+
+	health.Healthy = true
+	for _, resourceHealth := range health.Resources {
+		if resourceHealth.Status != resourceStatusConfigured {
+			health.Healthy = false
+		}
+	}
+	return health
+}
+
+// newUnreachablePartHealth returns the health of a part that could not be checked because of err. A nil
+// err leaves the health for the caller to fill in.
+func newUnreachablePartHealth(part *apppb.RobotPart, err error) partHealthJSON {
+	health := partHealthJSON{
+		ID:         part.GetId(),
+		Name:       part.GetName(),
+		Main:       part.GetMainPart(),
+		LastAccess: part.GetLastAccess().AsTime(),
+		Resources:  []resourceHealthJSON{},
+	}
+	if err != nil {
+		health.Errors = []string{fmt.Sprintf("could not connect to the part: %s", err)}
+	}
+	return health
+}
+
+// configuredResources returns the components and services in the part's config, as app has it.
+func configuredResources(part *apppb.RobotPart) []resourceHealthJSON {
+	var configured []resourceHealthJSON
+	conf := part.GetRobotConfig().AsMap()
+	for _, kind := range []string{resource.APITypeComponentName, resource.APITypeServiceName} {
+		resources, _ := conf[kind+"s"].([]interface{})
+		for _, r := range resources {
+			attrs, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := attrs["name"].(string)
+			if name == "" {
+				continue
+			}
+			namespace, _ := attrs["namespace"].(string)
+			if namespace == "" {
+				namespace = string(resource.APINamespaceRDK)
+			}
+			subtype, _ := attrs["type"].(string)
+			resourceType, _ := attrs["api"].(string)
+			if resourceType == "" {
+				resourceType = strings.Join([]string{namespace, kind, subtype}, ":")
+			}
+			configured = append(configured, resourceHealthJSON{Name: name, Type: resourceType})
+		}
+	}
+	return configured
+}
+
+// printPartHealth prints the health of a part as part of 'robot part status'.
+func printPartHealth(w io.Writer, health partHealthJSON) {
+	if health.Healthy {
+		fmt.Fprintln(w, "healthy: yes")
+	} else {
+		fmt.Fprintln(w, "healthy: no")
+	}
+	for _, err := range health.Errors {
+		fmt.Fprintf(w, "error: %s\n", err)
+	}
+	if len(health.Resources) > 0 {
+		fmt.Fprintln(w, "resources:")
+	}
+	for _, r := range health.Resources {
+		fmt.Fprintf(w, "\t%s (%s): %s\n", r.Name, r.Type, r.Status)
+		for _, err := range r.Errors {
+			fmt.Fprintf(w, "\t\t%s\n", err)
+		}
+	}
+}
+
+// errPartUnhealthy returns the error 'robot part status --fail-on-unhealthy' fails with.
+func errPartUnhealthy(health partHealthJSON) error {
+	return errors.Errorf("robot part %s is unhealthy", health.Name)
+}
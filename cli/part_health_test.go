@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	apppb "go.viam.com/api/app/v1"
+	commonpb "go.viam.com/api/common/v1"
+	robotpb "go.viam.com/api/robot/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// injectRobotServiceClient serves the given resources, failing GetStatus for those with an error in
+// statusErrs.
+type injectRobotServiceClient struct {
+	robotpb.RobotServiceClient
+	resources  []*commonpb.ResourceName
+	statusErrs map[string]error
+}
+
+func (i *injectRobotServiceClient) ResourceNames(
+	ctx context.Context, in *robotpb.ResourceNamesRequest, opts ...grpc.CallOption,
+) (*robotpb.ResourceNamesResponse, error) {
+	return &robotpb.ResourceNamesResponse{Resources: i.resources}, nil
+}
+
+func (i *injectRobotServiceClient) GetStatus(
+	ctx context.Context, in *robotpb.GetStatusRequest, opts ...grpc.CallOption,
+) (*robotpb.GetStatusResponse, error) {
+	if err := i.statusErrs[in.ResourceNames[0].Name]; err != nil {
+		return nil, err
+	}
+	return &robotpb.GetStatusResponse{}, nil
+}
+
+func TestPartHealth(t *testing.T) {
+	robotConfig, err := structpb.NewStruct(map[string]interface{}{
+		"components": []interface{}{
+			map[string]interface{}{"name": "left", "type": "motor", "model": "gpio"},
+			map[string]interface{}{"name": "cam", "type": "camera", "model": "webcam"},
+			map[string]interface{}{"name": "lidar", "type": "camera", "model": "rplidar"},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	part := &apppb.RobotPart{Id: "part-id", Name: "rover-main", MainPart: true, RobotConfig: robotConfig}
+	running := []*commonpb.ResourceName{
+		{Namespace: "rdk", Type: "component", Subtype: "motor", Name: "left"},
+		{Namespace: "rdk", Type: "component", Subtype: "camera", Name: "cam"},
+		{Namespace: "rdk-internal", Type: "service", Subtype: "web", Name: "builtin"},
+	}
+
+	t.Run("unhealthy", func(t *testing.T) {
+		robotClient := &injectRobotServiceClient{
+			resources:  running,
+			statusErrs: map[string]error{"cam": errors.New("camera unplugged")},
+		}
+		health := newPartHealth(context.Background(), part, robotClient)
+		test.That(t, health.Healthy, test.ShouldBeFalse)
+		test.That(t, health.Errors, test.ShouldBeEmpty)
+		test.That(t, health.Resources, test.ShouldResemble, []resourceHealthJSON{
+			{Name: "cam", Type: "rdk:component:camera", Status: resourceStatusError, Errors: []string{"camera unplugged"}},
+			{Name: "left", Type: "rdk:component:motor", Status: resourceStatusConfigured},
+			{
+				Name: "lidar", Type: "rdk:component:camera", Status: resourceStatusError,
+				Errors: []string{"configured but not running on the part, check the part's logs for why it failed to build"},
+			},
+		})
+
+		var out bytes.Buffer
+		test.That(t, printJSON(&out, health), test.ShouldBeNil)
+		var written map[string]interface{}
+		test.That(t, json.Unmarshal(out.Bytes(), &written), test.ShouldBeNil)
+		test.That(t, written["healthy"], test.ShouldEqual, false)
+		test.That(t, written["resources"], test.ShouldHaveLength, 3)
+
+		err := errPartUnhealthy(health)
+		test.That(t, err.Error(), test.ShouldEqual, "robot part rover-main is unhealthy")
+		test.That(t, ExitCode(err), test.ShouldNotEqual, ExitCodeSuccess)
+	})
+
+	t.Run("healthy", func(t *testing.T) {
+		healthyPart := &apppb.RobotPart{Id: "part-id", Name: "rover-main"}
+		health := newPartHealth(context.Background(), healthyPart, &injectRobotServiceClient{resources: running})
+		test.That(t, health.Healthy, test.ShouldBeTrue)
+		test.That(t, health.Resources, test.ShouldHaveLength, 2)
+
+		var out bytes.Buffer
+		printPartHealth(&out, health)
+		test.That(t, out.String(), test.ShouldEqual,
+			"healthy: yes\nresources:\n\tcam (rdk:component:camera): configured\n\tleft (rdk:component:motor): configured\n")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		health := newUnreachablePartHealth(part, errors.New("context deadline exceeded"))
+		test.That(t, health.Healthy, test.ShouldBeFalse)
+		test.That(t, health.Errors, test.ShouldResemble, []string{"could not connect to the part: context deadline exceeded"})
+		test.That(t, health.Resources, test.ShouldBeEmpty)
+	})
+}
@@ -1,12 +1,17 @@
 package cli
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
 )
 
 const asciiViam = `
@@ -19,6 +24,58 @@ const asciiViam = `
 
 `
 
+const (
+	// formatText is the default, human readable output format.
+	formatText = "text"
+	// formatJSON prints command output as JSON for use in scripts.
+	formatJSON = "json"
+)
+
+// outputFormat returns the validated value of the "format" flag.
+func outputFormat(c *cli.Context) (string, error) {
+	switch format := c.String("format"); format {
+	case "", formatText:
+		return formatText, nil
+	case formatJSON:
+		return formatJSON, nil
+	default:
+		return "", newValidationError(errors.Errorf("unknown format %q: must be %q or %q", format, formatText, formatJSON))
+	}
+}
+
+// printJSON writes v to w as indented JSON.
+func printJSON(w io.Writer, v interface{}) error {
+	md, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(md))
+	return err
+}
+
+// confirmf asks the user a yes/no question on the app's writer and reads the answer from its reader.
+// Anything other than "y" or "yes" counts as no.
+func confirmf(c *cli.Context, format string, a ...interface{}) (bool, error) {
+	fmt.Fprintf(c.App.Writer, format+" [y/N]: ", a...)
+	reader := c.App.Reader
+	if reader == nil {
+		reader = os.Stdin
+	}
+	answer, err := bufio.NewReader(reader).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// clearScreen moves the cursor to the top left of the terminal and clears it.
+const clearScreen = "\033[H\033[2J"
+
 // infof prints a message prefixed with a bold cyan "Info: ".
 func infof(w io.Writer, format string, a ...interface{}) {
 	// NOTE(benjirewis): for some reason, both errcheck and gosec complain about
@@ -40,11 +97,21 @@ func warningf(w io.Writer, format string, a ...interface{}) {
 
 // Errorf prints a message prefixed with a bold red "Error: " prefix and exits with 1.
 func Errorf(w io.Writer, format string, a ...interface{}) {
+	errorf(w, ExitCodeFailure, format, a...)
+}
+
+// ExitWithError prints err prefixed with a bold red "Error: " prefix and exits with the
+// exit code that corresponds to it.
+func ExitWithError(w io.Writer, err error) {
+	errorf(w, ExitCode(err), "%s", err.Error())
+}
+
+func errorf(w io.Writer, code int, format string, a ...interface{}) {
 	if _, err := color.New(color.Bold, color.FgRed).Fprint(w, "Error: "); err != nil {
 		log.Fatal(err)
 	}
 	fmt.Fprintf(w, format+"\n", a...)
-	os.Exit(1)
+	os.Exit(code)
 }
 
 // viamLogo prints an ASCII Viam logo.
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// latestReleaseURL is where the latest published release of the CLI is looked up.
+	latestReleaseURL = "https://api.github.com/repos/viamrobotics/rdk/releases/latest"
+	// checkUpdateTimeout bounds how long version --check-update waits for the release endpoint.
+	checkUpdateTimeout = 3 * time.Second
+)
+
+// versionJSON is the output of 'version --format json'.
+type versionJSON struct {
+	Version         string `json:"version"`
+	Git             string `json:"git"`
+	API             string `json:"api"`
+	LatestVersion   string `json:"latest_version,omitempty"`
+	UpdateAvailable *bool  `json:"update_available,omitempty"`
+}
+
+// fetchLatestVersion returns the version of the latest published release found at releaseURL.
+func fetchLatestVersion(ctx context.Context, httpClient *http.Client, releaseURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releaseURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected status %q from release endpoint", res.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&release); err != nil {
+		return "", errors.Wrap(err, "could not decode latest release")
+	}
+	if release.TagName == "" {
+		return "", errors.New("latest release has no version")
+	}
+	return release.TagName, nil
+}
+
+// checkForUpdate looks up the latest published version and reports whether it is newer than current.
+func checkForUpdate(ctx context.Context, httpClient *http.Client, releaseURL, current string) (string, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, checkUpdateTimeout)
+	defer cancel()
+	latest, err := fetchLatestVersion(ctx, httpClient, releaseURL)
+	if err != nil {
+		return "", false, err
+	}
+	cmp, err := compareVersions(current, latest)
+	if err != nil {
+		return latest, false, err
+	}
+	return latest, cmp < 0, nil
+}
+
+// upgradeCommand returns the command that installs the latest stable CLI for this platform.
+func upgradeCommand() string {
+	binURL := fmt.Sprintf("https://storage.googleapis.com/packages.viam.com/apps/viam-cli/viam-cli-stable-%s-%s",
+		runtime.GOOS, runtime.GOARCH)
+	return fmt.Sprintf("sudo curl -o /usr/local/bin/viam %s && sudo chmod a+rx /usr/local/bin/viam", binURL)
+}
+
+// parseVersion returns version, with or without a leading v, in the form golang.org/x/mod/semver expects.
+// It is an error if version is not a full semantic version, including the shorthands such as v1.2 that
+// semver accepts.
+func parseVersion(version string) (string, error) {
+	v := "v" + strings.TrimPrefix(version, "v")
+	withoutBuild := v
+	if i := strings.Index(v, "+"); i >= 0 {
+		withoutBuild = v[:i]
+	}
+	if !semver.IsValid(v) || semver.Canonical(v) != withoutBuild {
+		return "", errors.Errorf("%q is not a semantic version", version)
+	}
+	return v, nil
+}
+
+// compareVersions returns -1, 0, or 1 if semantic version a has lower, equal, or higher precedence than b.
+func compareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return semver.Compare(va, vb), nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"v0.5.0", "v0.5.0", 0},
+		{"0.5.0", "v0.5.0", 0},
+		{"v0.5.0", "v0.6.0", -1},
+		{"v0.10.0", "v0.9.0", 1},
+		{"v1.0.0", "v0.99.99", 1},
+		{"v0.5.1", "v0.5.0", 1},
+		{"v0.5.0-rc1", "v0.5.0", -1},
+		{"v0.5.0-rc.2", "v0.5.0-rc.10", -1},
+		{"v0.5.0-rc.1", "v0.5.0-rc", 1},
+		{"v0.5.0-1", "v0.5.0-alpha", -1},
+		{"v0.5.0+build.1", "v0.5.0+build.2", 0},
+	} {
+		t.Run(fmt.Sprintf("%s vs %s", tc.a, tc.b), func(t *testing.T) {
+			c, err := compareVersions(tc.a, tc.b)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, c, test.ShouldEqual, tc.expected)
+		})
+	}
+
+	_, err := compareVersions("(dev)", "v0.5.0")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = compareVersions("v0.5", "v0.5.0")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v0.6.0", "name": "v0.6.0"}`)
+	}))
+	defer srv.Close()
+
+	latest, available, err := checkForUpdate(context.Background(), srv.Client(), srv.URL, "v0.5.0")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, latest, test.ShouldEqual, "v0.6.0")
+	test.That(t, available, test.ShouldBeTrue)
+
+	latest, available, err = checkForUpdate(context.Background(), srv.Client(), srv.URL, "v0.6.0")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, latest, test.ShouldEqual, "v0.6.0")
+	test.That(t, available, test.ShouldBeFalse)
+
+	_, _, err = checkForUpdate(context.Background(), srv.Client(), srv.URL, "(dev)")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("unavailable endpoint", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failing.Close()
+		_, _, err := checkForUpdate(context.Background(), failing.Client(), failing.URL, "v0.5.0")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("slow endpoint times out", func(t *testing.T) {
+		done := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+		}))
+		defer slow.Close()
+		defer close(done)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, _, err := checkForUpdate(ctx, slow.Client(), slow.URL, "v0.5.0")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, time.Since(start), test.ShouldBeLessThan, checkUpdateTimeout)
+	})
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
@@ -15,6 +16,14 @@ func main() {
 		Name:            "viam",
 		Usage:           "interact with your Viam robots",
 		HideHelpCommand: true,
+		Description: fmt.Sprintf(`Exit codes:
+   %d  success
+   %d  failure
+   %d  partial failure: some of the work completed, such as some files being exported
+   %d  authentication error: not logged in or credentials were rejected`,
+			rdkcli.ExitCodeSuccess, rdkcli.ExitCodeFailure, rdkcli.ExitCodePartialFailure, rdkcli.ExitCodeAuthError),
+		// errors are handled below so that they are printed consistently and exit with the right code.
+		ExitErrHandler: func(*cli.Context, error) {},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:   "base-url",
@@ -22,6 +31,11 @@ func main() {
 				Value:  "https://app.viam.com:443",
 				Usage:  "base URL of app",
 			},
+			&cli.BoolFlag{
+				Name:   "allow-insecure",
+				Hidden: true,
+				Usage:  "allow an http:// base URL, which sends credentials unencrypted, such as to a local app instance",
+			},
 			&cli.StringFlag{
 				Name:    "config",
 				Aliases: []string{"c"},
@@ -32,6 +46,11 @@ func main() {
 				Aliases: []string{"vvv"},
 				Usage:   "enable debug logging",
 			},
+			&cli.StringFlag{
+				Name:  "log-format",
+				Value: "text",
+				Usage: "format of debug logs: text or json. json logs are written to stderr, one object per line",
+			},
 		},
 		Commands: []*cli.Command{
 			{
@@ -40,7 +59,19 @@ func main() {
 				Aliases:         []string{"auth"},
 				Usage:           "login to app.viam.com",
 				HideHelpCommand: true,
-				Action:          rdkcli.LoginAction,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name: rdkcli.LoginFlagCredentialStore,
+						Usage: "where to keep your credentials: file, or keychain for the operating system's keychain. " +
+							"defaults to the store used last, or file",
+					},
+					&cli.BoolFlag{
+						Name: rdkcli.LoginFlagDevice,
+						Usage: "log in with a browser on another device, such as from a remote shell on a robot: " +
+							"print a URL and code to enter there instead of opening a browser here",
+					},
+				},
+				Action: rdkcli.LoginAction,
 				Subcommands: []*cli.Command{
 					{
 						Name:   "print-access-token",
@@ -55,8 +86,14 @@ func main() {
 				Action: rdkcli.LogoutAction,
 			},
 			{
-				Name:   "whoami",
-				Usage:  "get currently logged-in user",
+				Name:  "whoami",
+				Usage: "get currently logged-in user",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "token-info",
+						Usage: "print details of the stored access token without contacting the server",
+					},
+				},
 				Action: rdkcli.WhoAmIAction,
 			},
 			{
@@ -69,6 +106,23 @@ func main() {
 						Usage:  "list organizations for the current user",
 						Action: rdkcli.ListOrganizationsAction,
 					},
+					{
+						Name:      "create",
+						Usage:     "create an organization owned by the current user",
+						UsageText: "viam organizations create --name <name> [other options]",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "name",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Value: "text",
+								Usage: "output format: text or json",
+							},
+						},
+						Action: rdkcli.CreateOrganizationAction,
+					},
 				},
 			},
 			{
@@ -82,6 +136,50 @@ func main() {
 						ArgsUsage: "[organization]",
 						Action:    rdkcli.ListLocationsAction,
 					},
+					{
+						Name:      "create",
+						Usage:     "create a location in an organization",
+						UsageText: "viam locations create --name <name> [other options]",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "organization",
+								DefaultText: "first organization alphabetically",
+							},
+							&cli.StringFlag{
+								Name:     "name",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Value: "text",
+								Usage: "output format: text or json",
+							},
+						},
+						Action: rdkcli.CreateLocationAction,
+					},
+					{
+						Name:      "delete",
+						Usage:     "delete a location from an organization",
+						UsageText: "viam locations delete [other options] <location>",
+						ArgsUsage: "<location>",
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:        "organization",
+								DefaultText: "first organization alphabetically",
+							},
+							&cli.BoolFlag{
+								Name:    "yes",
+								Aliases: []string{"y"},
+								Usage:   "delete without asking for confirmation",
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Value: "text",
+								Usage: "output format: text or json",
+							},
+						},
+						Action: rdkcli.DeleteLocationAction,
+					},
 				},
 			},
 			{
@@ -96,9 +194,8 @@ func main() {
 							rdkcli.DataFlagDestination, rdkcli.DataFlagDataType),
 						Flags: []cli.Flag{
 							&cli.PathFlag{
-								Name:     rdkcli.DataFlagDestination,
-								Required: true,
-								Usage:    "output directory for downloaded data",
+								Name:  rdkcli.DataFlagDestination,
+								Usage: "output directory for downloaded data and its export.json manifest. required unless --count-only is set",
 							},
 							&cli.StringFlag{
 								Name:     rdkcli.DataFlagDataType,
@@ -152,11 +249,12 @@ func main() {
 							},
 							&cli.StringFlag{
 								Name:  rdkcli.DataFlagStart,
-								Usage: "ISO-8601 timestamp indicating the start of the interval filter",
+								Usage: "ISO-8601 timestamp, or a duration before now such as -24h or -7d, indicating the start of the interval filter",
 							},
 							&cli.StringFlag{
-								Name:  rdkcli.DataFlagEnd,
-								Usage: "ISO-8601 timestamp indicating the end of the interval filter",
+								Name: rdkcli.DataFlagEnd,
+								Usage: "ISO-8601 timestamp, or a duration before now, indicating the end of the interval filter. " +
+									"defaults to now if --start is relative",
 							},
 							&cli.StringSliceFlag{
 								Name: rdkcli.DataFlagTags,
@@ -168,6 +266,27 @@ func main() {
 								Usage: "bbox labels filter. " +
 									"accepts string labels corresponding to bounding boxes within images",
 							},
+							&cli.StringSliceFlag{
+								Name: rdkcli.DataFlagExtMap,
+								Usage: "file extensions to use for binary data of uncommon mime types. " +
+									"accepts a list of mime/type=.ext pairs",
+							},
+							&cli.BoolFlag{
+								Name:  rdkcli.DataFlagCountOnly,
+								Usage: "print how much data matches the filters without downloading it",
+							},
+							&cli.BoolFlag{
+								Name:  rdkcli.DataFlagCompress,
+								Usage: "gzip tabular data as it downloads, writing data.ndjson.gz. binary data is not compressed",
+							},
+							&cli.StringSliceFlag{
+								Name:  rdkcli.DataFlagColumns,
+								Usage: "only export these top-level fields of each tabular data record, such as readings",
+							},
+							&cli.BoolFlag{
+								Name:  rdkcli.DataFlagSync,
+								Usage: "only export data captured since the export already in the destination, adding it to that export",
+							},
 						},
 						Action: rdkcli.DataExportAction,
 					},
@@ -223,11 +342,12 @@ func main() {
 							},
 							&cli.StringFlag{
 								Name:  rdkcli.DataFlagStart,
-								Usage: "ISO-8601 timestamp indicating the start of the interval filter",
+								Usage: "ISO-8601 timestamp, or a duration before now such as -24h or -7d, indicating the start of the interval filter",
 							},
 							&cli.StringFlag{
-								Name:  rdkcli.DataFlagEnd,
-								Usage: "ISO-8601 timestamp indicating the end of the interval filter",
+								Name: rdkcli.DataFlagEnd,
+								Usage: "ISO-8601 timestamp, or a duration before now, indicating the end of the interval filter. " +
+									"defaults to now if --start is relative",
 							},
 						},
 						Action: rdkcli.DataDeleteAction,
@@ -251,6 +371,25 @@ func main() {
 								Name:        "location",
 								DefaultText: "first location alphabetically",
 							},
+							&cli.BoolFlag{
+								Name:  "online-only",
+								Usage: "only list robots that have been online recently, and show when they were last online",
+							},
+							&cli.DurationFlag{
+								Name:  "offline-after",
+								Value: 5 * time.Minute,
+								Usage: "how long since it was last online before a robot is left out by --online-only",
+							},
+							&cli.IntFlag{
+								Name:        "limit",
+								Usage:       "list at most this many robots, first by name",
+								DefaultText: "all of them",
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Value: "text",
+								Usage: "output format: text or json",
+							},
 						},
 						Action: rdkcli.ListRobotsAction,
 					},
@@ -278,6 +417,16 @@ func main() {
 								Name:     "robot",
 								Required: true,
 							},
+							&cli.BoolFlag{
+								Name:    "watch",
+								Aliases: []string{"w"},
+								Usage:   "refresh the status until interrupted",
+							},
+							&cli.DurationFlag{
+								Name:  "interval",
+								Value: 2 * time.Second,
+								Usage: "how often to refresh the status when watching",
+							},
 						},
 						Action: rdkcli.RobotStatusAction,
 					},
@@ -302,6 +451,19 @@ func main() {
 								Name:  "errors",
 								Usage: "show only errors",
 							},
+							&cli.BoolFlag{
+								Name:    "tail",
+								Aliases: []string{"f"},
+								Usage:   "follow logs",
+							},
+							&cli.StringFlag{
+								Name:  "grep",
+								Usage: "show only logs whose message matches this regular expression",
+							},
+							&cli.BoolFlag{
+								Name:  "grep-invert",
+								Usage: "show only logs whose message does not match the --grep pattern",
+							},
 						},
 						Action: rdkcli.RobotLogsAction,
 					},
@@ -331,6 +493,16 @@ func main() {
 										Name:     "part",
 										Required: true,
 									},
+									&cli.StringFlag{
+										Name:  "format",
+										Value: "text",
+										Usage: "output format: text or json, which includes the health of the part's resources",
+									},
+									&cli.BoolFlag{
+										Name: "fail-on-unhealthy",
+										Usage: "check the health of the part's resources, and exit with a non-zero code " +
+											"if the part or any of them is unhealthy",
+									},
 								},
 								Action: rdkcli.RobotPartStatusAction,
 							},
@@ -364,6 +536,27 @@ func main() {
 										Aliases: []string{"f"},
 										Usage:   "follow logs",
 									},
+									&cli.StringFlag{
+										Name:  "grep",
+										Usage: "show only logs whose message matches this regular expression",
+									},
+									&cli.BoolFlag{
+										Name:  "grep-invert",
+										Usage: "show only logs whose message does not match the --grep pattern",
+									},
+									&cli.StringFlag{
+										Name:  "output-file",
+										Usage: "also write logs to this file",
+									},
+									&cli.Int64Flag{
+										Name:        "max-size",
+										Usage:       "rotate --output-file to <file>.1 and <file>.2 once it would grow past this many megabytes",
+										DefaultText: "never rotate",
+									},
+									&cli.BoolFlag{
+										Name:  "quiet",
+										Usage: "write logs only to --output-file, not the terminal",
+									},
 								},
 								Action: rdkcli.RobotPartLogsAction,
 							},
@@ -391,11 +584,21 @@ func main() {
 									&cli.StringFlag{
 										Name:    "data",
 										Aliases: []string{"d"},
+										Usage:   "JSON payload, @file to read it from a file, or - to read it from stdin",
+									},
+									&cli.BoolFlag{
+										Name:  "describe",
+										Usage: "print the JSON shape of the method's request and response instead of running it",
 									},
 									&cli.DurationFlag{
 										Name:    "stream",
 										Aliases: []string{"s"},
 									},
+									&cli.DurationFlag{
+										Name:        "timeout",
+										DefaultText: "1m, or none when --stream is set",
+										Usage:       "maximum time to wait for the command, or to keep streaming when --stream is set",
+									},
 								},
 								Action: rdkcli.RobotPartRunAction,
 							},
@@ -439,6 +642,8 @@ func main() {
 						Description: `Creates a module in app.viam.com to simplify code deployment.
 Ex: 'viam module create --name my-great-module --org-id <my org id>'
 Will create the module and a corresponding meta.json file in the current directory.
+With --language go or --language python, a minimal working module that serves a single generic component
+is also written to the current directory, and the meta.json is filled in to match it.
 
 If your org has set a namespace in app.viam.com then your module name will be 'my-namespace:my-great-module' and
 you won't have to pass a namespace or org-id in future commands. Otherwise there will be no namespace
@@ -460,6 +665,19 @@ After creation, use 'viam module update' to push your new module to app.viam.com
 								Name:  "org-id",
 								Usage: "id of the organization that will host the module",
 							},
+							&cli.StringFlag{
+								Name:  "language",
+								Usage: "also write a starter project for the module in this language: go or python",
+							},
+							&cli.StringFlag{
+								Name:        "model",
+								Usage:       "namespace:family:name of the model served by the starter project",
+								DefaultText: "<namespace>:<module name>:<module name>",
+							},
+							&cli.BoolFlag{
+								Name:  "force",
+								Usage: "overwrite an existing meta.json and starter project files, and the --output file",
+							},
 						},
 						Action: rdkcli.CreateModuleAction,
 					},
@@ -470,7 +688,7 @@ After creation, use 'viam module update' to push your new module to app.viam.com
 							&cli.StringFlag{
 								Name:        "module",
 								Usage:       "path to meta.json",
-								DefaultText: "./meta.json",
+								DefaultText: "the nearest meta.json in the current directory or its parents",
 								TakesFile:   true,
 							},
 							&cli.StringFlag{
@@ -481,6 +699,14 @@ After creation, use 'viam module update' to push your new module to app.viam.com
 								Name:  "org-id",
 								Usage: "id of the organization that hosts the module",
 							},
+							&cli.BoolFlag{
+								Name:  "validate-only",
+								Usage: "check the meta.json for problems without updating the module",
+							},
+							&cli.BoolFlag{
+								Name:  "diff",
+								Usage: "print how the meta.json differs from the module on app.viam.com without updating the module",
+							},
 						},
 						Action: rdkcli.UpdateModuleAction,
 					},
@@ -492,13 +718,18 @@ After creation, use 'viam module update' to push your new module to app.viam.com
 Example for linux/amd64:
 tar -czf packaged-module.tar.gz my-binary   # the meta.json entrypoint is relative to the root of the archive, so it should be "./my-binary"
 viam module upload --version "0.1.0" --platform "linux/amd64" packaged-module.tar.gz
+
+Example for a 32-bit Raspberry Pi (linux/arm/v7), building a Go module:
+GOOS=linux GOARCH=arm GOARM=7 go build -o my-binary
+tar -czf packaged-module.tar.gz my-binary
+viam module upload --version "0.1.0" --platform "linux/arm/v7" packaged-module.tar.gz
                         `,
-						UsageText: "viam module upload <version> <platform> [other options] <packaged-module.tar.gz>",
+						UsageText: "viam module upload <version> <platform> [other options] <packaged-module.tar.gz or module directory>",
 						Flags: []cli.Flag{
 							&cli.StringFlag{
 								Name:        "module",
 								Usage:       "path to meta.json",
-								DefaultText: "./meta.json",
+								DefaultText: "the nearest meta.json in the current directory or its parents",
 								TakesFile:   true,
 							},
 							&cli.StringFlag{
@@ -523,24 +754,42 @@ viam module upload --version "0.1.0" --platform "linux/amd64" packaged-module.ta
 								Usage: `platform of the binary you are uploading. Must be one of:
                         linux/amd64
                         linux/arm64
+                        linux/arm/v7 (for 32-bit raspberry pi os; go builds need GOARCH=arm GOARM=7)
                         darwin/amd64 (for intel macs)
                         darwin/arm64 (for non-intel macs)`,
 								Required: true,
 							},
+							&cli.BoolFlag{
+								Name:  "check",
+								Usage: "check the platform, package, and version without uploading anything",
+							},
 						},
 						Action: rdkcli.UploadModuleAction,
 					},
 				},
 			},
 			{
-				Name:   "version",
-				Usage:  "print version info for this program",
+				Name:  "version",
+				Usage: "print version info for this program",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "check-update",
+						Usage: "check whether a newer version of this program has been released",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "output format: text or json",
+					},
+				},
 				Action: rdkcli.VersionAction,
 			},
 		},
 	}
 
+	rdkcli.AddOutputFlags(app.Commands)
+
 	if err := app.Run(os.Args); err != nil {
-		rdkcli.Errorf(app.ErrWriter, err.Error())
+		rdkcli.ExitWithError(app.ErrWriter, err)
 	}
 }
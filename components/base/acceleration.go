@@ -0,0 +1,47 @@
+package base
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrAccelerationLimitsUnimplemented is returned by bases that cannot limit their acceleration. It is an
+// Unimplemented gRPC status, so that clients of remote bases see the same code.
+var ErrAccelerationLimitsUnimplemented = status.Error(codes.Unimplemented, "base does not support acceleration limits")
+
+// The base API has no dedicated RPCs for acceleration limits, so the client and server exchange them
+// over DoCommand using reserved commands that the server handles before the base sees them.
+const (
+	reservedCommandKey           = "command"
+	setAccelerationLimitsCommand = "rdk:base:set_acceleration_limits"
+	getAccelerationLimitsCommand = "rdk:base:get_acceleration_limits"
+	linearAccelerationKey        = "linear_millis_per_sec2"
+	angularAccelerationKey       = "angular_degs_per_sec2"
+)
+
+// ValidateAccelerationLimits checks limits passed to SetAccelerationLimits. Zero means no limit, so only
+// negative limits are invalid.
+func ValidateAccelerationLimits(linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	if linearMillisPerSec2 < 0 || angularDegsPerSec2 < 0 {
+		return errors.Errorf("acceleration limits must not be negative, got %v mm/sec^2 and %v degs/sec^2",
+			linearMillisPerSec2, angularDegsPerSec2)
+	}
+	return nil
+}
+
+func accelerationLimitsToMap(linearMillisPerSec2, angularDegsPerSec2 float64) map[string]interface{} {
+	return map[string]interface{}{linearAccelerationKey: linearMillisPerSec2, angularAccelerationKey: angularDegsPerSec2}
+}
+
+func accelerationLimitsFromMap(m map[string]interface{}) (float64, float64, error) {
+	linear, ok := m[linearAccelerationKey].(float64)
+	if !ok {
+		return 0, 0, errors.Errorf("acceleration limits missing %q", linearAccelerationKey)
+	}
+	angular, ok := m[angularAccelerationKey].(float64)
+	if !ok {
+		return 0, 0, errors.Errorf("acceleration limits missing %q", angularAccelerationKey)
+	}
+	return linear, angular, nil
+}
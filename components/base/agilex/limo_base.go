@@ -28,8 +28,9 @@ import (
 // default port for limo serial comm.
 const (
 	defaultSerialPath  = "/dev/ttyTHS1"
-	minTurningRadiusM  = 0.4 // from datasheet at: https://www.wevolver.com/specs/agilex-limo
-	defaultBaseTreadMm = 172 // "Tread" from datasheet at: https://www.wevolver.com/specs/agilex-limo
+	minTurningRadiusM  = 0.4  // from datasheet at: https://www.wevolver.com/specs/agilex-limo
+	defaultBaseTreadMm = 172  // "Tread" from datasheet at: https://www.wevolver.com/specs/agilex-limo
+	maxLinearSpeedMmPS = 1000 // "Max speed" from datasheet at: https://www.wevolver.com/specs/agilex-limo
 )
 
 // valid steering modes for limo.
@@ -136,6 +137,7 @@ func createLimoBase(ctx context.Context, _ resource.Dependencies, conf resource.
 	geometries, err := kinematicbase.CollisionGeometry(conf.Frame)
 	if err != nil {
 		logger.Warnf("base %v %s", lb.Name(), err.Error())
+		geometries = []spatialmath.Geometry{}
 	}
 	lb.geometries = geometries
 
@@ -438,15 +440,28 @@ func (lb *limoBase) Properties(ctx context.Context, extra map[string]interface{}
 	}
 
 	return base.Properties{
-		TurningRadiusMeters: lbTurnRadiusM,
-		WidthMeters:         float64(lb.width) * 0.001, // convert from mm to meters
+		TurningRadiusMeters:           lbTurnRadiusM,
+		WidthMeters:                   float64(lb.width) * 0.001, // convert from mm to meters
+		MaxLinearVelocityMillisPerSec: maxLinearSpeedMmPS,
+		IsHolonomic:                   lb.driveMode == OMNI.String(),
 	}, nil
 }
 
+// Geometries returns the collision geometry of the base from its frame, or an empty slice if it has none.
 func (lb *limoBase) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
 	return lb.geometries, nil
 }
 
+// SetAccelerationLimits is unimplemented, since the limo's serial protocol only takes target velocities.
+func (lb *limoBase) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	return base.ErrAccelerationLimitsUnimplemented
+}
+
+// GetAccelerationLimits is unimplemented, since the limo's serial protocol only takes target velocities.
+func (lb *limoBase) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	return 0, 0, base.ErrAccelerationLimitsUnimplemented
+}
+
 // DoCommand executes additional commands beyond the Base{} interface.
 func (lb *limoBase) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	name, ok := cmd["command"]
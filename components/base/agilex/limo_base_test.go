@@ -47,6 +47,7 @@ func TestLimoBaseConstructor(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, props.WidthMeters, test.ShouldEqual, expectedWidth)
 	test.That(t, props.TurningRadiusMeters, test.ShouldEqual, 0) // not ackerman, so zero
+	test.That(t, props.IsHolonomic, test.ShouldBeFalse)
 	lb.Close(ctx)
 
 	cfg = &Config{
@@ -59,5 +60,6 @@ func TestLimoBaseConstructor(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, props.WidthMeters, test.ShouldEqual, expectedWidth)
 	test.That(t, props.TurningRadiusMeters, test.ShouldEqual, 0) // not ackerman, so zero
+	test.That(t, props.IsHolonomic, test.ShouldBeTrue)
 	lb.Close(ctx)
 }
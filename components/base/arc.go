@@ -0,0 +1,59 @@
+package base
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// arcSegmentDeg is the most a base turns in each segment of a SoftwareArc. Smaller segments follow
+// the arc more closely at the cost of more stops and starts.
+const arcSegmentDeg = 5.0
+
+// SoftwareArc drives the base along an arc for drivers that cannot do so natively. The base travels
+// distanceMm at mmPerSec while turning at degsPerSec, so the arc turns the base by
+// |distanceMm / mmPerSec| * degsPerSec degrees in total, counterclockwise for positive degsPerSec.
+// The arc is approximated by alternating straight moves and spins of at most arcSegmentDeg each.
+// If a segment fails or the context is cancelled between segments, the base is stopped and the error
+// is returned.
+func SoftwareArc(
+	ctx context.Context,
+	b Base,
+	distanceMm int,
+	mmPerSec,
+	degsPerSec float64,
+	extra map[string]interface{},
+) error {
+	if mmPerSec == 0 {
+		return errors.New("mmPerSec must not be zero")
+	}
+	if distanceMm == 0 {
+		return nil
+	}
+	if degsPerSec == 0 {
+		return b.MoveStraight(ctx, distanceMm, mmPerSec, extra)
+	}
+
+	angleDeg := math.Abs(float64(distanceMm)/mmPerSec) * degsPerSec
+	segments := int(math.Ceil(math.Abs(angleDeg) / arcSegmentDeg))
+	segment := func(segmentMm int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := b.MoveStraight(ctx, segmentMm, mmPerSec, extra); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return b.Spin(ctx, angleDeg/float64(segments), math.Abs(degsPerSec), extra)
+	}
+	for i := 0; i < segments; i++ {
+		// split the distance so that the segments add up to exactly distanceMm.
+		if err := segment(distanceMm*(i+1)/segments - distanceMm*i/segments); err != nil {
+			return stopOnError(b, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package base_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestSoftwareArc(t *testing.T) {
+	newBase := func() (*inject.Base, *[]string) {
+		var calls []string
+		b := inject.NewBase(testBaseName)
+		b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			calls = append(calls, fmt.Sprintf("straight %d at %v", distanceMm, mmPerSec))
+			return nil
+		}
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			calls = append(calls, fmt.Sprintf("spin %v at %v", angleDeg, degsPerSec))
+			return nil
+		}
+		b.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			calls = append(calls, "stop")
+			return nil
+		}
+		return b, &calls
+	}
+
+	t.Run("quarter circle", func(t *testing.T) {
+		// 1000mm at 100mm/s takes 10s, turning 9 degrees a second for 90 degrees in all.
+		b, calls := newBase()
+		test.That(t, base.SoftwareArc(context.Background(), b, 1000, 100, 9, nil), test.ShouldBeNil)
+
+		var expected []string
+		for i := 0; i < 18; i++ {
+			expected = append(expected, fmt.Sprintf("straight %d at 100", 1000*(i+1)/18-1000*i/18), "spin 5 at 9")
+		}
+		test.That(t, *calls, test.ShouldResemble, expected)
+	})
+
+	t.Run("backwards and clockwise", func(t *testing.T) {
+		b, calls := newBase()
+		test.That(t, base.SoftwareArc(context.Background(), b, -100, 100, -10, nil), test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldResemble, []string{
+			"straight -50 at 100", "spin -5 at 10",
+			"straight -50 at 100", "spin -5 at 10",
+		})
+	})
+
+	t.Run("straight", func(t *testing.T) {
+		b, calls := newBase()
+		test.That(t, base.SoftwareArc(context.Background(), b, 100, 50, 0, nil), test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldResemble, []string{"straight 100 at 50"})
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		errSpin := errors.New("wheel slipped")
+		b, calls := newBase()
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			return errSpin
+		}
+		err := base.SoftwareArc(context.Background(), b, 1000, 100, 9, nil)
+		test.That(t, errors.Is(err, errSpin), test.ShouldBeTrue)
+		test.That(t, *calls, test.ShouldResemble, []string{"straight 55 at 100", "stop"})
+	})
+
+	t.Run("cancelled between segments", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		b, calls := newBase()
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			*calls = append(*calls, "spin")
+			cancel()
+			return nil
+		}
+		err := base.SoftwareArc(ctx, b, 1000, 100, 9, nil)
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+		test.That(t, *calls, test.ShouldResemble, []string{"straight 55 at 100", "spin", "stop"})
+	})
+
+	t.Run("zero speed", func(t *testing.T) {
+		b, calls := newBase()
+		test.That(t, base.SoftwareArc(context.Background(), b, 100, 0, 10, nil), test.ShouldNotBeNil)
+		test.That(t, *calls, test.ShouldBeEmpty)
+	})
+}
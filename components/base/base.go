@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"github.com/golang/geo/r3"
+	"go.uber.org/multierr"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/base/v1"
 
@@ -47,6 +48,8 @@ type Base interface {
 	// Spin spins the robot by a given angle in degrees at a given speed.
 	// If a speed of 0 the base will stop.
 	// Given a positive speed and a positive angle, the base turns to the left (for built-in RDK drivers)
+	// The angle is not normalized: its magnitude is how far the base turns, so 720 spins it around
+	// twice and 360 once, rather than not at all. Use SpinTo to turn to an absolute heading instead.
 	// This method blocks until completed or cancelled
 	Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error
 
@@ -59,6 +62,14 @@ type Base interface {
 	SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error
 
 	Properties(ctx context.Context, extra map[string]interface{}) (Properties, error)
+
+	// SetAccelerationLimits limits how quickly the base may speed up or slow down in every move after it,
+	// in mm/sec^2 for linear motion and degs/sec^2 for spins. Zero removes a limit. Bases that cannot
+	// limit their acceleration return ErrAccelerationLimitsUnimplemented.
+	SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error
+
+	// GetAccelerationLimits returns the limits last set with SetAccelerationLimits, linear first.
+	GetAccelerationLimits(ctx context.Context) (float64, float64, error)
 }
 
 // FromDependencies is a helper for getting the named base from a collection of
@@ -85,3 +96,29 @@ func CreateStatus(ctx context.Context, b Base) (*commonpb.ActuatorStatus, error)
 	}
 	return &commonpb.ActuatorStatus{IsMoving: isMoving}, nil
 }
+
+// StopIfMoving stops the base only if it reports that it is moving, and returns whether it was stopped.
+// This avoids calling Stop on a base that is already stopped, which some bases treat as an error, such as
+// when polling for a base to be stopped in a loop.
+func StopIfMoving(ctx context.Context, b Base) (bool, error) {
+	isMoving, err := b.IsMoving(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !isMoving {
+		return false, nil
+	}
+	if err := b.Stop(ctx, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// stopOnError stops b if err is not nil, returning err along with any error stopping the base. A fresh
+// context is used so that the base still stops when the error is that the caller's context was cancelled.
+func stopOnError(b Base, err error) error {
+	if err == nil {
+		return nil
+	}
+	return multierr.Combine(err, b.Stop(context.Background(), nil))
+}
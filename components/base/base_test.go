@@ -2,6 +2,7 @@ package base_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/mitchellh/mapstructure"
@@ -78,3 +79,72 @@ func TestCreateStatus(t *testing.T) {
 		test.That(t, status1, test.ShouldResemble, status)
 	})
 }
+
+func TestStopIfMoving(t *testing.T) {
+	var isMoving bool
+	stopCount := 0
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.IsMovingFunc = func(ctx context.Context) (bool, error) {
+		return isMoving, nil
+	}
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stopCount++
+		if !isMoving {
+			return errors.New("base is already stopped")
+		}
+		isMoving = false
+		return nil
+	}
+
+	stopped, err := base.StopIfMoving(context.Background(), injectBase)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stopped, test.ShouldBeFalse)
+	test.That(t, stopCount, test.ShouldEqual, 0)
+
+	isMoving = true
+	stopped, err = base.StopIfMoving(context.Background(), injectBase)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stopped, test.ShouldBeTrue)
+	test.That(t, stopCount, test.ShouldEqual, 1)
+
+	// polling again once stopped does not call Stop
+	stopped, err = base.StopIfMoving(context.Background(), injectBase)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stopped, test.ShouldBeFalse)
+	test.That(t, stopCount, test.ShouldEqual, 1)
+
+	errIsMoving := errors.New("can't tell")
+	injectBase.IsMovingFunc = func(ctx context.Context) (bool, error) {
+		return false, errIsMoving
+	}
+	stopped, err = base.StopIfMoving(context.Background(), injectBase)
+	test.That(t, err, test.ShouldBeError, errIsMoving)
+	test.That(t, stopped, test.ShouldBeFalse)
+	test.That(t, stopCount, test.ShouldEqual, 1)
+}
+
+func TestWidthGet(t *testing.T) {
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+		return base.Properties{WidthMeters: 0.3815}, nil
+	}
+	props, err := injectBase.Properties(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the deprecation warning is only logged the first time, and calling it again must still work
+	for i := 0; i < 2; i++ {
+		//nolint:staticcheck
+		width, err := base.WidthGet(context.Background(), injectBase)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, width, test.ShouldEqual, 382)
+		test.That(t, float64(width), test.ShouldAlmostEqual, props.WidthMeters*1000, 1)
+	}
+
+	errProperties := errors.New("no properties")
+	injectBase.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+		return base.Properties{}, errProperties
+	}
+	//nolint:staticcheck
+	_, err = base.WidthGet(context.Background(), injectBase)
+	test.That(t, err, test.ShouldBeError, errProperties)
+}
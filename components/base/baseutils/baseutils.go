@@ -0,0 +1,85 @@
+// Package baseutils contains typed units that convert to the raw values expected by the methods of a base.
+package baseutils
+
+import (
+	"math"
+
+	"go.viam.com/rdk/components/base"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// A LinearSpeed is a speed that can be passed to a base as millimeters per second.
+type LinearSpeed interface {
+	MmPerSec() float64
+}
+
+// An AngularSpeed is a speed that can be passed to a base as degrees per second.
+type AngularSpeed interface {
+	DegsPerSec() float64
+}
+
+// MetersPerSec is a linear speed in meters per second.
+type MetersPerSec float64
+
+// MmPerSec returns the speed in millimeters per second.
+func (s MetersPerSec) MmPerSec() float64 {
+	return float64(s) * 1000
+}
+
+// KmPerHour is a linear speed in kilometers per hour.
+type KmPerHour float64
+
+// MmPerSec returns the speed in millimeters per second.
+func (s KmPerHour) MmPerSec() float64 {
+	return float64(s) * 1e6 / 3600
+}
+
+// MmPerSec is a linear speed in millimeters per second, the unit a base uses natively.
+type MmPerSec float64
+
+// MmPerSec returns the speed in millimeters per second.
+func (s MmPerSec) MmPerSec() float64 {
+	return float64(s)
+}
+
+// RadiansPerSec is an angular speed in radians per second.
+type RadiansPerSec float64
+
+// DegsPerSec returns the speed in degrees per second.
+func (s RadiansPerSec) DegsPerSec() float64 {
+	return rdkutils.RadToDeg(float64(s))
+}
+
+// DegsPerSec is an angular speed in degrees per second, the unit a base uses natively.
+type DegsPerSec float64
+
+// DegsPerSec returns the speed in degrees per second.
+func (s DegsPerSec) DegsPerSec() float64 {
+	return float64(s)
+}
+
+// Meters is a distance in meters.
+type Meters float64
+
+// Mm returns the distance rounded to the nearest whole millimeter.
+func (d Meters) Mm() int {
+	return int(math.Round(float64(d) * 1000))
+}
+
+// Radians is an angle in radians.
+type Radians float64
+
+// Deg returns the angle in degrees.
+func (a Radians) Deg() float64 {
+	return rdkutils.RadToDeg(float64(a))
+}
+
+// StraightMove returns a base.Move that drives the given distance at the given speed without spinning.
+func StraightMove(distance Meters, speed LinearSpeed) base.Move {
+	return base.Move{DistanceMm: distance.Mm(), MmPerSec: speed.MmPerSec()}
+}
+
+// SpinMove returns a base.Move that spins by the given angle at the given speed without driving.
+func SpinMove(angle Radians, speed AngularSpeed) base.Move {
+	return base.Move{AngleDeg: angle.Deg(), DegsPerSec: speed.DegsPerSec()}
+}
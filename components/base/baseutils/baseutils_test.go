@@ -0,0 +1,36 @@
+package baseutils
+
+import (
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+)
+
+func TestConversions(t *testing.T) {
+	test.That(t, MetersPerSec(0.5).MmPerSec(), test.ShouldAlmostEqual, 500)
+	test.That(t, KmPerHour(3.6).MmPerSec(), test.ShouldAlmostEqual, 1000)
+	test.That(t, MmPerSec(42).MmPerSec(), test.ShouldEqual, 42)
+	test.That(t, RadiansPerSec(math.Pi).DegsPerSec(), test.ShouldAlmostEqual, 180)
+	test.That(t, DegsPerSec(30).DegsPerSec(), test.ShouldEqual, 30)
+	test.That(t, Meters(1.2345).Mm(), test.ShouldEqual, 1235)
+	test.That(t, Meters(-0.25).Mm(), test.ShouldEqual, -250)
+	test.That(t, Radians(-math.Pi/2).Deg(), test.ShouldAlmostEqual, -90)
+}
+
+func TestMoves(t *testing.T) {
+	straight := StraightMove(Meters(2), KmPerHour(1.8))
+	test.That(t, straight, test.ShouldResemble, base.Move{DistanceMm: 2000, MmPerSec: 500})
+
+	spin := SpinMove(Radians(math.Pi), RadiansPerSec(math.Pi/4))
+	test.That(t, spin.DistanceMm, test.ShouldEqual, 0)
+	test.That(t, spin.MmPerSec, test.ShouldEqual, 0)
+	test.That(t, spin.AngleDeg, test.ShouldAlmostEqual, 180)
+	test.That(t, spin.DegsPerSec, test.ShouldAlmostEqual, 45)
+
+	// converting back from a move's raw values gives the original units
+	test.That(t, float64(straight.DistanceMm)/1000, test.ShouldAlmostEqual, 2)
+	test.That(t, straight.MmPerSec*3600/1e6, test.ShouldAlmostEqual, 1.8)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/base/v1"
 	"go.viam.com/utils/protoutils"
@@ -150,6 +151,26 @@ func (c *client) Properties(ctx context.Context, extra map[string]interface{}) (
 	return ProtoFeaturesToProperties(resp), nil
 }
 
+// extraProperties returns the base's Properties along with the ones the properties API has no fields
+// for, which takes a reserved DoCommand on top of GetProperties. A server from before the command
+// existed passes it on to the base's DoCommand, which is expected to fail.
+func (c *client) extraProperties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	props, err := c.Properties(ctx, extra)
+	if err != nil {
+		return Properties{}, err
+	}
+	cmd := map[string]interface{}{reservedCommandKey: getExtraPropertiesCommand}
+	if extra != nil {
+		cmd[extraPropertiesExtraKey] = extra
+	}
+	extraProps, err := rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
+	if err != nil {
+		return Properties{}, errors.Wrap(err, "could not get the base properties missing from the properties API")
+	}
+	addExtraPropertiesFromMap(&props, extraProps)
+	return props, nil
+}
+
 func (c *client) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
 	ext, err := protoutils.StructToStructPb(extra)
 	if err != nil {
@@ -164,3 +185,19 @@ func (c *client) Geometries(ctx context.Context, extra map[string]interface{}) (
 	}
 	return spatialmath.NewGeometriesFromProto(resp.GetGeometries())
 }
+
+func (c *client) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	cmd := accelerationLimitsToMap(linearMillisPerSec2, angularDegsPerSec2)
+	cmd[reservedCommandKey] = setAccelerationLimitsCommand
+	_, err := rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
+	return err
+}
+
+func (c *client) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	cmd := map[string]interface{}{reservedCommandKey: getAccelerationLimitsCommand}
+	resp, err := rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
+	if err != nil {
+		return 0, 0, err
+	}
+	return accelerationLimitsFromMap(resp)
+}
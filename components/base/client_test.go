@@ -9,6 +9,8 @@ import (
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/components/base"
 	viamgrpc "go.viam.com/rdk/grpc"
@@ -41,6 +43,7 @@ func setupWorkingBase(
 	}
 
 	workingBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		argsReceived["Stop"] = []interface{}{extra}
 		return nil
 	}
 
@@ -48,9 +51,27 @@ func setupWorkingBase(
 		return expectedFeatures, nil
 	}
 
+	workingBase.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+		argsReceived["SetVelocity"] = []interface{}{linear, angular, extra}
+		return nil
+	}
+
 	workingBase.GeometriesFunc = func(ctx context.Context) ([]spatialmath.Geometry, error) {
 		return geometries, nil
 	}
+
+	workingBase.SetAccelerationLimitsFunc = func(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+		argsReceived["SetAccelerationLimits"] = []interface{}{linearMillisPerSec2, angularDegsPerSec2}
+		return nil
+	}
+
+	workingBase.GetAccelerationLimitsFunc = func(ctx context.Context) (float64, float64, error) {
+		limits := argsReceived["SetAccelerationLimits"]
+		if limits == nil {
+			return 0, 0, nil
+		}
+		return limits[0].(float64), limits[1].(float64), nil
+	}
 }
 
 func setupBrokenBase(brokenBase *inject.Base) {
@@ -75,6 +96,10 @@ func setupBrokenBase(brokenBase *inject.Base) {
 	brokenBase.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
 		return base.Properties{}, errPropertiesFailed
 	}
+
+	brokenBase.SetAccelerationLimitsFunc = func(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+		return base.ErrAccelerationLimitsUnimplemented
+	}
 }
 
 func TestClient(t *testing.T) {
@@ -88,10 +113,14 @@ func TestClient(t *testing.T) {
 
 	workingBase := &inject.Base{}
 	expectedFeatures := base.Properties{
-		TurningRadiusMeters: 1.2,
-		WidthMeters:         float64(100) * 0.001,
+		TurningRadiusMeters:           1.2,
+		WidthMeters:                   float64(100) * 0.001,
+		MaxLinearVelocityMillisPerSec: 300,
+		IsHolonomic:                   true,
 	}
-	expectedGeometries := []spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{1, 2, 3}, "")}
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 300, Y: 200, Z: 100}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	expectedGeometries := []spatialmath.Geometry{spatialmath.NewPoint(r3.Vector{1, 2, 3}, ""), box}
 	setupWorkingBase(workingBase, argsReceived, expectedFeatures, expectedGeometries)
 
 	brokenBase := &inject.Base{}
@@ -148,6 +177,17 @@ func TestClient(t *testing.T) {
 			test.That(t, argsReceived["MoveStraight"], test.ShouldResemble, expectedArgs)
 		})
 
+		t.Run("working MoveStraightWithOptions", func(t *testing.T) {
+			opts := base.MoveOptions{AccelerationMmPerSecPerSec: 100, MaxJerkMmPerSecCubed: 500}
+			err = base.MoveStraightWithOptions(context.Background(), workingBaseClient, 42, 42.0, opts, expectedExtra)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, argsReceived["MoveStraight"], test.ShouldHaveLength, 3)
+			extra, ok := argsReceived["MoveStraight"][2].(map[string]interface{})
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, extra["foo"], test.ShouldEqual, "bar")
+			test.That(t, base.MoveOptionsFromExtra(extra), test.ShouldResemble, opts)
+		})
+
 		t.Run("working DoCommand", func(t *testing.T) {
 			resp, err := workingBaseClient.DoCommand(context.Background(), testutils.TestCommand)
 			test.That(t, err, test.ShouldBeNil)
@@ -169,23 +209,62 @@ func TestClient(t *testing.T) {
 		})
 
 		t.Run("working Properties", func(t *testing.T) {
+			// the properties the properties API has no fields for are only gotten by the helpers that need them.
 			features, err := workingBaseClient.Properties(context.Background(), expectedExtra)
 			test.That(t, err, test.ShouldBeNil)
-			test.That(t, features, test.ShouldResemble, expectedFeatures)
+			test.That(t, features, test.ShouldResemble, base.Properties{
+				TurningRadiusMeters: expectedFeatures.TurningRadiusMeters,
+				WidthMeters:         expectedFeatures.WidthMeters,
+			})
+		})
+
+		t.Run("working MoveStraightClamped", func(t *testing.T) {
+			err := base.MoveStraightClamped(context.Background(), workingBaseClient, 100, 1000, nil, nil)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, argsReceived["MoveStraight"][1], test.ShouldEqual, expectedFeatures.MaxLinearVelocityMillisPerSec)
+		})
+
+		t.Run("working SetVelocityVector", func(t *testing.T) {
+			// the base is holonomic, which the client must know to let it move sideways.
+			err := base.SetVelocityVector(context.Background(), workingBaseClient, r3.Vector{X: 100, Y: 50}, 10)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, argsReceived["SetVelocity"][0], test.ShouldResemble, r3.Vector{X: 100, Y: 50})
 		})
 
 		t.Run("working Stop", func(t *testing.T) {
 			err = workingBaseClient.Stop(context.Background(), nil)
 			test.That(t, err, test.ShouldBeNil)
+			test.That(t, base.StopOptionsFromExtra(argsReceived["Stop"][0].(map[string]interface{})), test.ShouldResemble, base.StopOptions{})
+		})
+
+		t.Run("working StopWithOptions", func(t *testing.T) {
+			opts := base.StopOptions{DecelRateDegsPerSec2: 90, DecelRateMillisPerSec2: 250}
+			err = base.StopWithOptions(context.Background(), workingBaseClient, opts, expectedExtra)
+			test.That(t, err, test.ShouldBeNil)
+			extra := argsReceived["Stop"][0].(map[string]interface{})
+			test.That(t, base.StopOptionsFromExtra(extra), test.ShouldResemble, opts)
+			test.That(t, extra["foo"], test.ShouldEqual, "bar")
 		})
 
 		t.Run("working Geometries", func(t *testing.T) {
 			geometries, err := workingBaseClient.Geometries(context.Background(), nil)
 			test.That(t, err, test.ShouldBeNil)
+			test.That(t, geometries, test.ShouldHaveLength, len(expectedGeometries))
 			for i, geometry := range geometries {
 				test.That(t, geometry.AlmostEqual(expectedGeometries[i]), test.ShouldBeTrue)
 			}
 		})
+
+		t.Run("working acceleration limits", func(t *testing.T) {
+			err = workingBaseClient.SetAccelerationLimits(context.Background(), 500, 90)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, argsReceived["SetAccelerationLimits"], test.ShouldResemble, []interface{}{500., 90.})
+
+			linear, angular, err := workingBaseClient.GetAccelerationLimits(context.Background())
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, linear, test.ShouldEqual, 500)
+			test.That(t, angular, test.ShouldEqual, 90)
+		})
 	})
 
 	t.Run("working base client by dialing", func(t *testing.T) {
@@ -224,6 +303,9 @@ func TestClient(t *testing.T) {
 		err = failingBaseClient.Stop(context.Background(), nil)
 		test.That(t, err.Error(), test.ShouldContainSubstring, errStopFailed.Error())
 
+		err = failingBaseClient.SetAccelerationLimits(context.Background(), 500, 90)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+
 		test.That(t, failingBaseClient.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
@@ -0,0 +1,121 @@
+package base
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// ErrEStopped is returned when a base that has been emergency stopped with EStop is asked to move.
+var ErrEStopped = errors.New("base is emergency stopped, call ClearEStop before moving it again")
+
+// EStopBase wraps a base with an emergency stop latch: once EStop is called, the base is stopped and
+// every MoveStraight, Spin, SetPower and SetVelocity fails with ErrEStopped until ClearEStop is called.
+// Everything else, including Stop, is passed through to the wrapped base.
+//
+// Nothing wraps bases in an EStopBase for you: robots, the base server and the base client all use the
+// base unwrapped, so callers must opt in by wrapping the base themselves. The latch is also local to
+// the process that holds the EStopBase, so it does not stop other clients of the same base.
+type EStopBase struct {
+	Base
+
+	mu      sync.Mutex
+	latched bool
+	// moves cancels the moves in progress by id, so that EStop ends moves that began before it.
+	moves  map[uint64]context.CancelFunc
+	nextID uint64
+}
+
+// NewEStopBase wraps b with an emergency stop latch, which starts cleared. b may be any base, including a
+// client of a remote one.
+func NewEStopBase(b Base) *EStopBase {
+	return &EStopBase{Base: b, moves: map[uint64]context.CancelFunc{}}
+}
+
+// EStop latches the emergency stop, cancels any moves in progress and stops the base. The latch stays
+// set even if stopping the base fails.
+func (b *EStopBase) EStop(ctx context.Context) error {
+	b.mu.Lock()
+	b.latched = true
+	for _, cancel := range b.moves {
+		cancel()
+	}
+	b.mu.Unlock()
+	return b.Base.Stop(ctx, nil)
+}
+
+// ClearEStop clears the emergency stop latch so that the base can move again.
+func (b *EStopBase) ClearEStop(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latched = false
+	return nil
+}
+
+// EStopped returns whether the emergency stop is latched.
+func (b *EStopBase) EStopped() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.latched
+}
+
+// MoveStraight moves the wrapped base unless the emergency stop is latched.
+func (b *EStopBase) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	return b.move(ctx, func(ctx context.Context) error {
+		return b.Base.MoveStraight(ctx, distanceMm, mmPerSec, extra)
+	})
+}
+
+// Spin spins the wrapped base unless the emergency stop is latched.
+func (b *EStopBase) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	return b.move(ctx, func(ctx context.Context) error {
+		return b.Base.Spin(ctx, angleDeg, degsPerSec, extra)
+	})
+}
+
+// SetPower sets the power of the wrapped base unless the emergency stop is latched.
+func (b *EStopBase) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	return b.move(ctx, func(ctx context.Context) error {
+		return b.Base.SetPower(ctx, linear, angular, extra)
+	})
+}
+
+// SetVelocity sets the velocity of the wrapped base unless the emergency stop is latched.
+func (b *EStopBase) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	return b.move(ctx, func(ctx context.Context) error {
+		return b.Base.SetVelocity(ctx, linear, angular, extra)
+	})
+}
+
+// move runs fn with a context that EStop cancels, or fails with ErrEStopped if the latch is set.
+func (b *EStopBase) move(ctx context.Context, fn func(ctx context.Context) error) error {
+	b.mu.Lock()
+	if b.latched {
+		b.mu.Unlock()
+		return ErrEStopped
+	}
+	moveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	id := b.nextID
+	b.nextID++
+	b.moves[id] = cancel
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		delete(b.moves, id)
+		b.mu.Unlock()
+	}()
+	err := fn(moveCtx)
+	if b.EStopped() {
+		// EStop may have stopped the base before fn reached it, and SetPower and SetVelocity return
+		// without waiting for their context, so stop the base again in case fn left it moving. A fresh
+		// context is used since ctx may be what EStop cancelled. A move cut short by EStop reports why it
+		// was, rather than as cancelled.
+		return multierr.Combine(ErrEStopped, b.Base.Stop(context.Background(), nil))
+	}
+	return err
+}
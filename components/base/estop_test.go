@@ -0,0 +1,158 @@
+package base_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestEStopBase(t *testing.T) {
+	injectBase := inject.NewBase(testBaseName)
+	var moves, stops int
+	// EStop and a move it cuts short may both stop the base at once.
+	var stopsMu sync.Mutex
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		moves++
+		return nil
+	}
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		moves++
+		return nil
+	}
+	injectBase.SetPowerFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+		moves++
+		return nil
+	}
+	injectBase.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+		moves++
+		return nil
+	}
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stopsMu.Lock()
+		defer stopsMu.Unlock()
+		stops++
+		return nil
+	}
+	b := base.NewEStopBase(injectBase)
+	test.That(t, b.Name(), test.ShouldResemble, injectBase.Name())
+
+	moveAll := func() []error {
+		ctx := context.Background()
+		return []error{
+			b.MoveStraight(ctx, 100, 100, nil),
+			b.Spin(ctx, 90, 45, nil),
+			b.SetPower(ctx, r3.Vector{Y: 1}, r3.Vector{}, nil),
+			b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{}, nil),
+		}
+	}
+
+	for _, err := range moveAll() {
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, moves, test.ShouldEqual, 4)
+
+	test.That(t, b.EStop(context.Background()), test.ShouldBeNil)
+	test.That(t, b.EStopped(), test.ShouldBeTrue)
+	test.That(t, stops, test.ShouldEqual, 1)
+
+	t.Run("motion is rejected while latched", func(t *testing.T) {
+		for _, err := range moveAll() {
+			test.That(t, err, test.ShouldBeError, base.ErrEStopped)
+		}
+		test.That(t, moves, test.ShouldEqual, 4)
+		// stopping is always allowed
+		test.That(t, b.Stop(context.Background(), nil), test.ShouldBeNil)
+		test.That(t, stops, test.ShouldEqual, 2)
+	})
+
+	t.Run("motion is allowed after clearing", func(t *testing.T) {
+		test.That(t, b.ClearEStop(context.Background()), test.ShouldBeNil)
+		test.That(t, b.EStopped(), test.ShouldBeFalse)
+		for _, err := range moveAll() {
+			test.That(t, err, test.ShouldBeNil)
+		}
+		test.That(t, moves, test.ShouldEqual, 8)
+	})
+
+	t.Run("EStop ends a move in progress", func(t *testing.T) {
+		started := make(chan struct{})
+		injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		moveErr := make(chan error, 1)
+		go func() {
+			moveErr <- b.MoveStraight(context.Background(), 1000, 100, nil)
+		}()
+		<-started
+		test.That(t, b.EStop(context.Background()), test.ShouldBeNil)
+		test.That(t, <-moveErr, test.ShouldBeError, base.ErrEStopped)
+		test.That(t, b.ClearEStop(context.Background()), test.ShouldBeNil)
+	})
+
+	t.Run("EStop during a non-blocking move leaves the base stopped", func(t *testing.T) {
+		var mu sync.Mutex
+		var calls []string
+		record := func(call string) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, call)
+		}
+		injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			record("stop")
+			return nil
+		}
+
+		// the velocity reaches the driver only after EStop has stopped the base, and the driver does not
+		// check its context, like the wheeled base.
+		started := make(chan struct{})
+		eStopped := make(chan struct{})
+		injectBase.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+			close(started)
+			<-eStopped
+			record("set_velocity")
+			return nil
+		}
+		moveErr := make(chan error, 1)
+		go func() {
+			moveErr <- b.SetVelocity(context.Background(), r3.Vector{Y: 100}, r3.Vector{}, nil)
+		}()
+		<-started
+		test.That(t, b.EStop(context.Background()), test.ShouldBeNil)
+		close(eStopped)
+		test.That(t, <-moveErr, test.ShouldBeError, base.ErrEStopped)
+		test.That(t, calls, test.ShouldResemble, []string{"stop", "set_velocity", "stop"})
+		test.That(t, b.ClearEStop(context.Background()), test.ShouldBeNil)
+
+		// however EStop and SetVelocity race, the base is stopped last.
+		injectBase.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+			record("set_velocity")
+			return nil
+		}
+		for i := 0; i < 100; i++ {
+			calls = nil
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				//nolint:errcheck
+				b.SetVelocity(context.Background(), r3.Vector{Y: 100}, r3.Vector{}, nil)
+			}()
+			go func() {
+				defer wg.Done()
+				//nolint:errcheck
+				b.EStop(context.Background())
+			}()
+			wg.Wait()
+			test.That(t, calls[len(calls)-1], test.ShouldEqual, "stop")
+			test.That(t, b.ClearEStop(context.Background()), test.ShouldBeNil)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package fake
 
 import (
 	"context"
+	"sync"
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
@@ -21,8 +22,9 @@ func init() {
 }
 
 const (
-	defaultWidthMm               = 600
-	defaultMinimumTurningRadiusM = 0
+	defaultWidthMm                   = 600
+	defaultMinimumTurningRadiusM     = 0
+	defaultMaxLinearVelocityMmPerSec = 1000
 )
 
 // Base is a fake base that returns what it was provided in each method.
@@ -32,7 +34,13 @@ type Base struct {
 	CloseCount    int
 	WidthMeters   float64
 	TurningRadius float64
-	Geometry      []spatialmath.Geometry
+	// MaxLinearVelocityMmPerSec is reported in the base's properties, but not enforced.
+	MaxLinearVelocityMmPerSec float64
+	Geometry                  []spatialmath.Geometry
+
+	mu                     sync.Mutex
+	linearAccelerationMax  float64
+	angularAccelerationMax float64
 }
 
 // NewBase instantiates a new base of the fake model type.
@@ -50,6 +58,7 @@ func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, _
 	}
 	b.WidthMeters = defaultWidthMm * 0.001
 	b.TurningRadius = defaultMinimumTurningRadiusM
+	b.MaxLinearVelocityMmPerSec = defaultMaxLinearVelocityMmPerSec
 	return b, nil
 }
 
@@ -92,8 +101,9 @@ func (b *Base) Close(ctx context.Context) error {
 // Properties returns the base's properties.
 func (b *Base) Properties(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
 	return base.Properties{
-		TurningRadiusMeters: b.TurningRadius,
-		WidthMeters:         b.WidthMeters,
+		TurningRadiusMeters:           b.TurningRadius,
+		WidthMeters:                   b.WidthMeters,
+		MaxLinearVelocityMillisPerSec: b.MaxLinearVelocityMmPerSec,
 	}, nil
 }
 
@@ -101,3 +111,21 @@ func (b *Base) Properties(ctx context.Context, extra map[string]interface{}) (ba
 func (b *Base) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
 	return b.Geometry, nil
 }
+
+// SetAccelerationLimits records the limits, which GetAccelerationLimits returns.
+func (b *Base) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	if err := base.ValidateAccelerationLimits(linearMillisPerSec2, angularDegsPerSec2); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.linearAccelerationMax, b.angularAccelerationMax = linearMillisPerSec2, angularDegsPerSec2
+	return nil
+}
+
+// GetAccelerationLimits returns the limits last set.
+func (b *Base) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.linearAccelerationMax, b.angularAccelerationMax, nil
+}
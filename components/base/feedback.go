@@ -0,0 +1,91 @@
+package base
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// stallMinProgressMm is how far a base must travel between checks of its pose to count as making progress.
+const stallMinProgressMm = 1.
+
+// StraightFeedback describes how a MoveStraightFeedback went according to the base's odometry.
+type StraightFeedback struct {
+	// DistanceMm is the distance the base estimates it traveled, negative when driving backwards.
+	DistanceMm int
+	// Stalled is true if the base stopped making progress before finishing the move, as with stuck wheels.
+	Stalled bool
+}
+
+// MoveStraightFeedback drives the base straight like MoveStraight, blocking until the move is done, and
+// reports how far the base estimates it actually went. It only works with bases that implement Poser.
+// If the base's pose does not advance for stallTimeout while it is moving, the base is stopped and the
+// move is reported as stalled rather than failed, so callers can tell stuck wheels from other errors.
+func MoveStraightFeedback(
+	ctx context.Context,
+	b Base,
+	distanceMm int,
+	mmPerSec float64,
+	stallTimeout time.Duration,
+	extra map[string]interface{},
+) (StraightFeedback, error) {
+	poser, ok := b.(Poser)
+	if !ok {
+		return StraightFeedback{}, errors.Errorf("base %s does not report its pose, so it cannot measure how far it moved", b.Name())
+	}
+	if stallTimeout <= 0 {
+		return StraightFeedback{}, errors.New("stall timeout must be positive")
+	}
+	start, err := poser.Pose(ctx, nil)
+	if err != nil {
+		return StraightFeedback{}, err
+	}
+	traveled := func() (float64, bool) {
+		pose := currentPose(poser)
+		if pose == nil {
+			return 0, false
+		}
+		return pose.Point().Sub(start.Point()).Norm(), true
+	}
+
+	moveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	moveErr := make(chan error, 1)
+	go func() {
+		moveErr <- b.MoveStraight(moveCtx, distanceMm, mmPerSec, extra)
+	}()
+
+	var feedback StraightFeedback
+	ticker := time.NewTicker(stallTimeout / 5)
+	defer ticker.Stop()
+	var progress float64
+	lastProgress := time.Now()
+loop:
+	for {
+		select {
+		case err = <-moveErr:
+			break loop
+		case <-ticker.C:
+			if distance, ok := traveled(); ok && distance-progress >= stallMinProgressMm {
+				progress, lastProgress = distance, time.Now()
+			} else if time.Since(lastProgress) >= stallTimeout {
+				feedback.Stalled = true
+				cancel()
+				// the move's context was canceled, so stop the base with one that is not.
+				err = b.Stop(context.Background(), nil)
+				<-moveErr
+				break loop
+			}
+		}
+	}
+
+	if distance, ok := traveled(); ok {
+		if distanceMm < 0 {
+			distance = -distance
+		}
+		feedback.DistanceMm = int(math.Round(distance))
+	}
+	return feedback, err
+}
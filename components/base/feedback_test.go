@@ -0,0 +1,72 @@
+package base_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// odometryBase is a base that reports its pose, which may be read while the base is moving.
+type odometryBase struct {
+	*inject.Base
+	mu   sync.Mutex
+	pose spatialmath.Pose
+}
+
+func (ob *odometryBase) Pose(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	return ob.pose, nil
+}
+
+func (ob *odometryBase) setPose(pose spatialmath.Pose) {
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+	ob.pose = pose
+}
+
+func TestMoveStraightFeedback(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		b := &odometryBase{Base: inject.NewBase(testBaseName), pose: spatialmath.NewZeroPose()}
+		b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			b.setPose(spatialmath.NewPoseFromPoint(r3.Vector{X: -995}))
+			return nil
+		}
+		feedback, err := base.MoveStraightFeedback(context.Background(), b, -1000, 100, time.Second, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, feedback, test.ShouldResemble, base.StraightFeedback{DistanceMm: -995})
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		b := &odometryBase{Base: inject.NewBase(testBaseName), pose: spatialmath.NewZeroPose()}
+		b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			// the wheels get stuck 250mm in, and the move never finishes on its own.
+			b.setPose(spatialmath.NewPoseFromPoint(r3.Vector{Y: 250}))
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		var stops int
+		b.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			stops++
+			return nil
+		}
+		feedback, err := base.MoveStraightFeedback(context.Background(), b, 1000, 100, 50*time.Millisecond, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, feedback, test.ShouldResemble, base.StraightFeedback{DistanceMm: 250, Stalled: true})
+		test.That(t, stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("base without odometry", func(t *testing.T) {
+		_, err := base.MoveStraightFeedback(context.Background(), inject.NewBase(testBaseName), 1000, 100, time.Second, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot measure how far it moved")
+	})
+}
@@ -0,0 +1,78 @@
+package base
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	// holdHeadingSegmentMillis is how far DriveDistanceHoldingHeading drives between checks of the heading.
+	holdHeadingSegmentMillis = 100
+	// holdHeadingSpinDegsPerSec is how fast DriveDistanceHoldingHeading spins to correct the heading.
+	holdHeadingSpinDegsPerSec = 30.0
+)
+
+// DriveDistanceHoldingHeading drives the base distanceMillis straight at speed mm/s, backwards if
+// distanceMillis is negative, while holding the compass heading of dev at what it was to begin with.
+// Bases drift when driving straight, so the distance is driven in short segments, and after each one a
+// small spin turns the base back to the initial heading if it has drifted more than toleranceDeg away.
+// The base is stopped if driving fails or ctx is cancelled.
+func DriveDistanceHoldingHeading(
+	ctx context.Context,
+	b Base,
+	dev movementsensor.MovementSensor,
+	distanceMillis int,
+	speed float64,
+	toleranceDeg float64,
+) error {
+	if speed <= 0 {
+		return errors.Errorf("speed must be greater than 0, got %v", speed)
+	}
+	if toleranceDeg < 0 {
+		return errors.Errorf("tolerance must not be negative, got %v", toleranceDeg)
+	}
+	target, err := dev.CompassHeading(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	direction := 1
+	if distanceMillis < 0 {
+		direction = -1
+	}
+	drive := func() error {
+		for remaining := utils.AbsInt(distanceMillis); remaining > 0; {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			segment := utils.MinInt(remaining, holdHeadingSegmentMillis)
+			if err := b.MoveStraight(ctx, direction*segment, speed, nil); err != nil {
+				return err
+			}
+			remaining -= segment
+
+			heading, err := dev.CompassHeading(ctx, nil)
+			if err != nil {
+				return err
+			}
+			// compass headings grow clockwise and spins turn counterclockwise, so drifting right by some
+			// angle is corrected by spinning left by it.
+			drift := utils.SignedAngleDiffDeg(target, heading)
+			if math.Abs(drift) > toleranceDeg {
+				if err := b.Spin(ctx, drift, holdHeadingSpinDegsPerSec, nil); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := drive(); err != nil {
+		return stopOnError(b, err)
+	}
+	return nil
+}
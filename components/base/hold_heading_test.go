@@ -0,0 +1,120 @@
+package base_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+// driftingBase is a base that turns clockwise by driftDeg for every 100mm it drives, and a compass
+// that reports its heading.
+type driftingBase struct {
+	*inject.Base
+	compass *inject.MovementSensor
+
+	heading  float64
+	driftDeg float64
+	driven   int
+	spins    []float64
+	stops    int
+	// worstDeg is the furthest the base has ended up from startDeg after a move or spin.
+	startDeg, worstDeg float64
+}
+
+func newDriftingBase(startDeg, driftDeg float64) *driftingBase {
+	d := &driftingBase{
+		Base:     inject.NewBase(testBaseName),
+		compass:  &inject.MovementSensor{},
+		heading:  startDeg,
+		startDeg: startDeg,
+		driftDeg: driftDeg,
+	}
+	d.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		d.driven += distanceMm
+		d.turn(-d.driftDeg * math.Abs(float64(distanceMm)) / 100)
+		return nil
+	}
+	d.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		d.spins = append(d.spins, angleDeg)
+		d.turn(angleDeg)
+		return nil
+	}
+	d.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		d.stops++
+		return nil
+	}
+	d.compass.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return d.heading, nil
+	}
+	return d
+}
+
+// turn turns the base counterclockwise by angleDeg, which lowers its compass heading.
+func (d *driftingBase) turn(angleDeg float64) {
+	d.heading = utils.ModAngDeg(d.heading - angleDeg)
+	d.worstDeg = math.Max(d.worstDeg, utils.AngleDiffDeg(d.heading, d.startDeg))
+}
+
+func TestDriveDistanceHoldingHeading(t *testing.T) {
+	t.Run("drift is corrected", func(t *testing.T) {
+		d := newDriftingBase(90, 2)
+		err := base.DriveDistanceHoldingHeading(context.Background(), d, d.compass, 1000, 200, 5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, d.driven, test.ShouldEqual, 1000)
+		// the base drifts 6 degrees by the third segment, which is past the tolerance, and is spun back.
+		test.That(t, d.spins, test.ShouldResemble, []float64{6, 6, 6})
+		test.That(t, d.worstDeg, test.ShouldBeLessThanOrEqualTo, 6)
+		test.That(t, utils.AngleDiffDeg(d.heading, 90), test.ShouldBeLessThanOrEqualTo, 5)
+		test.That(t, d.stops, test.ShouldEqual, 0)
+	})
+
+	t.Run("drift across north while reversing", func(t *testing.T) {
+		d := newDriftingBase(1, -4)
+		err := base.DriveDistanceHoldingHeading(context.Background(), d, d.compass, -450, 200, 3)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, d.driven, test.ShouldEqual, -450)
+		// drifting counterclockwise past north is corrected with clockwise spins.
+		for _, spin := range d.spins {
+			test.That(t, spin, test.ShouldAlmostEqual, -4)
+		}
+		test.That(t, d.spins, test.ShouldHaveLength, 4)
+		test.That(t, utils.AngleDiffDeg(d.heading, 1), test.ShouldBeLessThanOrEqualTo, 3)
+	})
+
+	t.Run("within tolerance", func(t *testing.T) {
+		d := newDriftingBase(0, 0.1)
+		err := base.DriveDistanceHoldingHeading(context.Background(), d, d.compass, 1000, 200, 5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, d.spins, test.ShouldBeEmpty)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		d := newDriftingBase(0, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		moveStraight := d.MoveStraightFunc
+		d.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			if d.driven == 200 {
+				cancel()
+			}
+			return moveStraight(ctx, distanceMm, mmPerSec, extra)
+		}
+		err := base.DriveDistanceHoldingHeading(ctx, d, d.compass, 1000, 200, 5)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, d.driven, test.ShouldEqual, 300)
+		test.That(t, d.stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		d := newDriftingBase(0, 0)
+		test.That(t, base.DriveDistanceHoldingHeading(context.Background(), d, d.compass, 100, 0, 5), test.ShouldNotBeNil)
+		test.That(t, base.DriveDistanceHoldingHeading(context.Background(), d, d.compass, 100, 200, -1), test.ShouldNotBeNil)
+		test.That(t, d.driven, test.ShouldEqual, 0)
+	})
+}
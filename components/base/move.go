@@ -0,0 +1,158 @@
+package base
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// MoveOrder is the order in which the parts of a Move are done.
+type MoveOrder int
+
+// The orders the parts of a Move can be done in.
+const (
+	// SpinThenStraight spins before driving straight, which suits turning towards a goal and then
+	// heading for it. It is the zero value, so it is the order of a Move that does not give one.
+	SpinThenStraight MoveOrder = iota
+	// StraightThenSpin drives straight before spinning, which suits approaching a spot and then turning
+	// to face something from it.
+	StraightThenSpin
+)
+
+// Move describes a single leg of travel for a base: a spin of AngleDeg at DegsPerSec
+// and a straight drive of DistanceMm at MmPerSec, in the given Order. Either part may be zero to skip it.
+// See ParseMoves for its JSON form.
+type Move struct {
+	DistanceMm int       `json:"distance_mm,omitempty"`
+	MmPerSec   float64   `json:"mm_per_sec,omitempty"`
+	AngleDeg   float64   `json:"angle_deg,omitempty"`
+	DegsPerSec float64   `json:"degs_per_sec,omitempty"`
+	Order      MoveOrder `json:"order,omitempty"`
+}
+
+// Executed describes how much of a Move a base actually completed.
+type Executed struct {
+	AngleDeg   float64
+	DistanceMm int
+}
+
+// Poser is implemented by bases that can report their current pose in their own frame of reference,
+// such as bases that track odometry. DoMoveReport uses it to measure interrupted moves.
+type Poser interface {
+	Pose(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error)
+}
+
+// DoMove performs the given move on the given base, spinning and driving straight in the move's order.
+func DoMove(ctx context.Context, move Move, b Base) error {
+	_, err := DoMoveReport(ctx, move, b)
+	return err
+}
+
+// DoMoves performs the given moves on the given base one after another, stopping at the first that fails.
+func DoMoves(ctx context.Context, moves []Move, b Base) error {
+	for i, move := range moves {
+		if err := DoMove(ctx, move, b); err != nil {
+			return errors.Wrapf(err, "move %d", i)
+		}
+	}
+	return nil
+}
+
+// DoMoveReport performs the given move on the given base like DoMove, and also reports how much of
+// the move was executed. Parts of the move that finished are reported as fully executed. If a part
+// is interrupted and the base implements Poser, the progress made is measured from the base's pose
+// before and after it; otherwise that part is reported as not executed at all. Interrupted spins are
+// measured modulo a full turn.
+func DoMoveReport(ctx context.Context, move Move, b Base) (Executed, error) {
+	var executed Executed
+	poser, _ := b.(Poser)
+
+	spin := func() error {
+		if move.AngleDeg == 0 {
+			return nil
+		}
+		start := currentPose(poser)
+		if err := b.Spin(ctx, move.AngleDeg, move.DegsPerSec, nil); err != nil {
+			if end := currentPose(poser); start != nil && end != nil {
+				executed.AngleDeg = utils.SignedAngleDiffDeg(
+					start.Orientation().OrientationVectorDegrees().Theta,
+					end.Orientation().OrientationVectorDegrees().Theta,
+				)
+			}
+			return err
+		}
+		executed.AngleDeg = move.AngleDeg
+		return nil
+	}
+	straight := func() error {
+		if move.DistanceMm == 0 {
+			return nil
+		}
+		start := currentPose(poser)
+		if err := b.MoveStraight(ctx, move.DistanceMm, move.MmPerSec, nil); err != nil {
+			if end := currentPose(poser); start != nil && end != nil {
+				distance := end.Point().Sub(start.Point()).Norm()
+				if move.DistanceMm < 0 {
+					distance = -distance
+				}
+				executed.DistanceMm = int(math.Round(distance))
+			}
+			return err
+		}
+		executed.DistanceMm = move.DistanceMm
+		return nil
+	}
+
+	var parts []func() error
+	switch move.Order {
+	case SpinThenStraight:
+		parts = []func() error{spin, straight}
+	case StraightThenSpin:
+		parts = []func() error{straight, spin}
+	default:
+		return executed, errors.Errorf("unknown move order %d", move.Order)
+	}
+	for _, part := range parts {
+		if err := part(); err != nil {
+			return executed, err
+		}
+	}
+	return executed, nil
+}
+
+// SpinTo spins the base the shortest way round to face absoluteHeadingDeg in the frame of the pose
+// the base reports, so it only works with bases that implement Poser. Headings are measured
+// counterclockwise, like the theta of the base's orientation. The spin is at most half a turn, unlike
+// Spin, which turns the base by however much it is given.
+func SpinTo(ctx context.Context, b Base, absoluteHeadingDeg, degsPerSec float64) error {
+	poser, ok := b.(Poser)
+	if !ok {
+		return errors.Errorf("base %s does not report its pose, so it cannot spin to an absolute heading", b.Name())
+	}
+	pose, err := poser.Pose(ctx, nil)
+	if err != nil {
+		return err
+	}
+	angle := utils.SignedAngleDiffDeg(pose.Orientation().OrientationVectorDegrees().Theta, absoluteHeadingDeg)
+	if angle == 0 {
+		return nil
+	}
+	return b.Spin(ctx, angle, math.Abs(degsPerSec), nil)
+}
+
+// currentPose returns the pose of the base, or nil if it is unknown. A background context is used
+// since the move's context may be what interrupted it.
+func currentPose(poser Poser) spatialmath.Pose {
+	if poser == nil {
+		return nil
+	}
+	pose, err := poser.Pose(context.Background(), nil)
+	if err != nil {
+		return nil
+	}
+	return pose
+}
@@ -0,0 +1,93 @@
+package base
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// The JSON names of the move orders.
+const (
+	spinThenStraightJSON = "spin_then_straight"
+	straightThenSpinJSON = "straight_then_spin"
+)
+
+// MarshalText encodes the order as its JSON name, spin_then_straight or straight_then_spin.
+func (o MoveOrder) MarshalText() ([]byte, error) {
+	switch o {
+	case SpinThenStraight:
+		return []byte(spinThenStraightJSON), nil
+	case StraightThenSpin:
+		return []byte(straightThenSpinJSON), nil
+	default:
+		return nil, errors.Errorf("unknown move order %d", o)
+	}
+}
+
+// UnmarshalText decodes an order from its JSON name.
+func (o *MoveOrder) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case spinThenStraightJSON:
+		*o = SpinThenStraight
+	case straightThenSpinJSON:
+		*o = StraightThenSpin
+	default:
+		return errors.Errorf("unknown move order %q, must be %s or %s", text, spinThenStraightJSON, straightThenSpinJSON)
+	}
+	return nil
+}
+
+// Validate checks that the move can be done: speeds must not be negative, since the direction comes
+// from the sign of the distance and angle, and each part that moves the base needs a speed to move at.
+func (m Move) Validate() error {
+	if m.MmPerSec < 0 || m.DegsPerSec < 0 {
+		return errors.Errorf("speeds must not be negative, got %v mm/sec and %v degs/sec", m.MmPerSec, m.DegsPerSec)
+	}
+	if m.DistanceMm != 0 && m.MmPerSec == 0 {
+		return errors.Errorf("driving %dmm needs mm_per_sec", m.DistanceMm)
+	}
+	if m.AngleDeg != 0 && m.DegsPerSec == 0 {
+		return errors.Errorf("spinning %v degrees needs degs_per_sec", m.AngleDeg)
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a move from its JSON form and validates it.
+func (m *Move) UnmarshalJSON(data []byte) error {
+	type plainMove Move // has no UnmarshalJSON, so decoding into it does not recurse.
+	var decoded plainMove
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+	if err := Move(decoded).Validate(); err != nil {
+		return err
+	}
+	*m = Move(decoded)
+	return nil
+}
+
+// ParseMoves parses a JSON array of moves, such as a path kept in a config file, to be done with
+// DoMoves. Each move is an object with any of the fields distance_mm, mm_per_sec, angle_deg,
+// degs_per_sec and order, which is spin_then_straight (the default) or straight_then_spin. Fields
+// that are left out are zero, and unknown fields are rejected so that typos are not silently ignored:
+//
+//	[
+//	  {"angle_deg": 90, "degs_per_sec": 45},
+//	  {"distance_mm": 500, "mm_per_sec": 200, "angle_deg": -45, "degs_per_sec": 45, "order": "straight_then_spin"}
+//	]
+func ParseMoves(data []byte) ([]Move, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "moves must be a JSON array")
+	}
+	moves := make([]Move, len(raw))
+	for i, rawMove := range raw {
+		if err := json.Unmarshal(rawMove, &moves[i]); err != nil {
+			return nil, errors.Wrapf(err, "move %d", i)
+		}
+	}
+	return moves, nil
+}
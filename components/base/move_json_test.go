@@ -0,0 +1,72 @@
+package base_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestParseMoves(t *testing.T) {
+	moves := []base.Move{
+		{AngleDeg: 90, DegsPerSec: 45},
+		{DistanceMm: 500, MmPerSec: 200},
+		{DistanceMm: -250, MmPerSec: 100, AngleDeg: -45.5, DegsPerSec: 30, Order: base.StraightThenSpin},
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		data, err := json.Marshal(moves)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(data), test.ShouldEqual, `[{"angle_deg":90,"degs_per_sec":45},{"distance_mm":500,"mm_per_sec":200},`+
+			`{"distance_mm":-250,"mm_per_sec":100,"angle_deg":-45.5,"degs_per_sec":30,"order":"straight_then_spin"}]`)
+		parsed, err := base.ParseMoves(data)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, parsed, test.ShouldResemble, moves)
+	})
+
+	t.Run("explicit default order", func(t *testing.T) {
+		parsed, err := base.ParseMoves([]byte(`[{"angle_deg": 90, "degs_per_sec": 45, "order": "spin_then_straight"}]`))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, parsed, test.ShouldResemble, moves[:1])
+	})
+
+	t.Run("fed to DoMoves", func(t *testing.T) {
+		parsed, err := base.ParseMoves([]byte(`[{"angle_deg": 90, "degs_per_sec": 45}, {"distance_mm": 500, "mm_per_sec": 200}]`))
+		test.That(t, err, test.ShouldBeNil)
+		var calls []string
+		b := inject.NewBase(testBaseName)
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			calls = append(calls, "spin")
+			return nil
+		}
+		b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			calls = append(calls, "straight")
+			return nil
+		}
+		test.That(t, base.DoMoves(context.Background(), parsed, b), test.ShouldBeNil)
+		test.That(t, calls, test.ShouldResemble, []string{"spin", "straight"})
+	})
+
+	for _, tc := range []struct {
+		name, json, err string
+	}{
+		{"not an array", `{"distance_mm": 500}`, "moves must be a JSON array"},
+		{"negative speed", `[{"distance_mm": 500, "mm_per_sec": -200}]`, "move 0: speeds must not be negative"},
+		{"negative spin speed", `[{}, {"angle_deg": 90, "degs_per_sec": -45}]`, "move 1: speeds must not be negative"},
+		{"missing speed", `[{"distance_mm": 500}]`, "move 0: driving 500mm needs mm_per_sec"},
+		{"missing spin speed", `[{"angle_deg": 90}]`, "move 0: spinning 90 degrees needs degs_per_sec"},
+		{"unknown order", `[{"order": "sideways"}]`, `unknown move order "sideways"`},
+		{"unknown field", `[{"distance": 500, "mm_per_sec": 200}]`, `unknown field "distance"`},
+		{"wrong type", `[{"distance_mm": "far"}]`, "move 0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := base.ParseMoves([]byte(tc.json))
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		})
+	}
+}
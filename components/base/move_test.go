@@ -0,0 +1,189 @@
+package base_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// poseBase is a base that reports its pose, which tests update as the base moves.
+type poseBase struct {
+	*inject.Base
+	pose spatialmath.Pose
+}
+
+func (pb *poseBase) Pose(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+	return pb.pose, nil
+}
+
+func TestDoMoveReport(t *testing.T) {
+	move := base.Move{AngleDeg: 90, DegsPerSec: 30, DistanceMm: 1000, MmPerSec: 100}
+
+	t.Run("completed", func(t *testing.T) {
+		injectBase := inject.NewBase(testBaseName)
+		injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			return nil
+		}
+		injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			return nil
+		}
+		executed, err := base.DoMoveReport(context.Background(), move, injectBase)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, executed, test.ShouldResemble, base.Executed{AngleDeg: 90, DistanceMm: 1000})
+	})
+
+	t.Run("canceled during straight", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		b := &poseBase{Base: inject.NewBase(testBaseName), pose: spatialmath.NewZeroPose()}
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			b.pose = spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: angleDeg})
+			return nil
+		}
+		b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			// the base gets 400mm along its new heading before the move is canceled.
+			b.pose = spatialmath.NewPose(r3.Vector{Y: 400}, b.pose.Orientation())
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		executed, err := base.DoMoveReport(ctx, move, b)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, executed, test.ShouldResemble, base.Executed{AngleDeg: 90, DistanceMm: 400})
+	})
+
+	t.Run("canceled during spin", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		b := &poseBase{Base: inject.NewBase(testBaseName), pose: spatialmath.NewZeroPose()}
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			b.pose = spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -30})
+			cancel()
+			return ctx.Err()
+		}
+		executed, err := base.DoMoveReport(ctx, base.Move{AngleDeg: -90, DistanceMm: 1000}, b)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, executed.AngleDeg, test.ShouldAlmostEqual, -30)
+		test.That(t, executed.DistanceMm, test.ShouldEqual, 0)
+	})
+
+	t.Run("canceled without pose", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		injectBase := inject.NewBase(testBaseName)
+		injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			return nil
+		}
+		injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			cancel()
+			return ctx.Err()
+		}
+		executed, err := base.DoMoveReport(ctx, move, injectBase)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, executed, test.ShouldResemble, base.Executed{AngleDeg: 90})
+	})
+}
+
+func TestDoMoveOrder(t *testing.T) {
+	var calls []string
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		calls = append(calls, "Spin")
+		return nil
+	}
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		calls = append(calls, "MoveStraight")
+		return nil
+	}
+	move := base.Move{AngleDeg: 90, DegsPerSec: 30, DistanceMm: 1000, MmPerSec: 100}
+
+	for _, tc := range []struct {
+		name  string
+		order base.MoveOrder
+		calls []string
+	}{
+		{"spin then straight", base.SpinThenStraight, []string{"Spin", "MoveStraight"}},
+		{"straight then spin", base.StraightThenSpin, []string{"MoveStraight", "Spin"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = nil
+			move.Order = tc.order
+			test.That(t, base.DoMove(context.Background(), move, injectBase), test.ShouldBeNil)
+			test.That(t, calls, test.ShouldResemble, tc.calls)
+		})
+	}
+
+	t.Run("interrupted straight skips the spin", func(t *testing.T) {
+		calls = nil
+		errStuck := errors.New("stuck")
+		injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			calls = append(calls, "MoveStraight")
+			return errStuck
+		}
+		move.Order = base.StraightThenSpin
+		executed, err := base.DoMoveReport(context.Background(), move, injectBase)
+		test.That(t, err, test.ShouldBeError, errStuck)
+		test.That(t, executed, test.ShouldResemble, base.Executed{})
+		test.That(t, calls, test.ShouldResemble, []string{"MoveStraight"})
+	})
+
+	t.Run("unknown order", func(t *testing.T) {
+		calls = nil
+		move.Order = base.MoveOrder(7)
+		err := base.DoMove(context.Background(), move, injectBase)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, calls, test.ShouldBeEmpty)
+	})
+}
+
+func TestSpinTo(t *testing.T) {
+	newBase := func(headingDeg float64) (*poseBase, *[]float64) {
+		var spins []float64
+		b := &poseBase{
+			Base: inject.NewBase(testBaseName),
+			pose: spatialmath.NewPoseFromOrientation(&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: headingDeg}),
+		}
+		b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			test.That(t, degsPerSec, test.ShouldEqual, 45)
+			spins = append(spins, angleDeg)
+			return nil
+		}
+		return b, &spins
+	}
+
+	for _, tc := range []struct {
+		current, target, spin float64
+	}{
+		{350, 10, 20},
+		{10, 350, -20},
+		{0, 90, 90},
+		{90, 0, -90},
+		{-170, 170, -20},
+		{0, 720 + 30, 30},
+		{0, 180, -180},
+	} {
+		t.Run(fmt.Sprintf("%v to %v", tc.current, tc.target), func(t *testing.T) {
+			b, spins := newBase(tc.current)
+			test.That(t, base.SpinTo(context.Background(), b, tc.target, -45), test.ShouldBeNil)
+			test.That(t, *spins, test.ShouldHaveLength, 1)
+			test.That(t, (*spins)[0], test.ShouldAlmostEqual, tc.spin)
+		})
+	}
+
+	t.Run("already facing the heading", func(t *testing.T) {
+		b, spins := newBase(45)
+		test.That(t, base.SpinTo(context.Background(), b, 45+360, 45), test.ShouldBeNil)
+		test.That(t, *spins, test.ShouldBeEmpty)
+	})
+
+	t.Run("base without pose", func(t *testing.T) {
+		err := base.SpinTo(context.Background(), inject.NewBase(testBaseName), 90, 45)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not report its pose")
+	})
+}
@@ -0,0 +1,118 @@
+package base
+
+import (
+	"context"
+)
+
+const (
+	// accelerationKey is the extra key under which MoveOptions.AccelerationMmPerSecPerSec is passed to a base.
+	accelerationKey = "acceleration_mm_per_sec_per_sec"
+	// maxJerkKey is the extra key under which MoveOptions.MaxJerkMmPerSecCubed is passed to a base.
+	maxJerkKey = "max_jerk_mm_per_sec_cubed"
+	// decelDegsKey is the extra key under which StopOptions.DecelRateDegsPerSec2 is passed to a base.
+	decelDegsKey = "decel_rate_degs_per_sec2"
+	// decelMillisKey is the extra key under which StopOptions.DecelRateMillisPerSec2 is passed to a base.
+	decelMillisKey = "decel_rate_millis_per_sec2"
+)
+
+// MoveOptions describes how a base should ramp into and out of a move. Zero valued fields are
+// left up to the base, which is the same behavior as calling MoveStraight or Spin directly.
+// For spins, the same values are interpreted in degrees rather than millimeters.
+type MoveOptions struct {
+	AccelerationMmPerSecPerSec float64
+	MaxJerkMmPerSecCubed       float64
+}
+
+// ToExtra returns a copy of extra with the set options added to it. Since options are sent
+// as part of extra, they are forwarded to remote bases by the client without any further changes.
+func (opts MoveOptions) ToExtra(extra map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(extra)+2)
+	for k, v := range extra {
+		out[k] = v
+	}
+	if opts.AccelerationMmPerSecPerSec != 0 {
+		out[accelerationKey] = opts.AccelerationMmPerSecPerSec
+	}
+	if opts.MaxJerkMmPerSecCubed != 0 {
+		out[maxJerkKey] = opts.MaxJerkMmPerSecCubed
+	}
+	return out
+}
+
+// MoveOptionsFromExtra reads the MoveOptions a caller passed in extra. Base implementations that
+// support ramping should use this to find the requested acceleration and jerk limits.
+func MoveOptionsFromExtra(extra map[string]interface{}) MoveOptions {
+	var opts MoveOptions
+	if acc, ok := extra[accelerationKey].(float64); ok {
+		opts.AccelerationMmPerSecPerSec = acc
+	}
+	if jerk, ok := extra[maxJerkKey].(float64); ok {
+		opts.MaxJerkMmPerSecCubed = jerk
+	}
+	return opts
+}
+
+// MoveStraightWithOptions calls MoveStraight on the given base with the options added to extra.
+func MoveStraightWithOptions(
+	ctx context.Context,
+	b Base,
+	distanceMm int,
+	mmPerSec float64,
+	opts MoveOptions,
+	extra map[string]interface{},
+) error {
+	return b.MoveStraight(ctx, distanceMm, mmPerSec, opts.ToExtra(extra))
+}
+
+// SpinWithOptions calls Spin on the given base with the options added to extra.
+func SpinWithOptions(
+	ctx context.Context,
+	b Base,
+	angleDeg, degsPerSec float64,
+	opts MoveOptions,
+	extra map[string]interface{},
+) error {
+	return b.Spin(ctx, angleDeg, degsPerSec, opts.ToExtra(extra))
+}
+
+// StopOptions describes how quickly a base should ramp down to a stop. Zero valued fields mean an
+// immediate stop, which is the same behavior as calling Stop directly. Bases that cannot ramp down
+// ignore the options and stop immediately.
+type StopOptions struct {
+	DecelRateDegsPerSec2   float64
+	DecelRateMillisPerSec2 float64
+}
+
+// ToExtra returns a copy of extra with the set options added to it. Like MoveOptions, they are
+// forwarded to remote bases by the client without any further changes.
+func (opts StopOptions) ToExtra(extra map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(extra)+2)
+	for k, v := range extra {
+		out[k] = v
+	}
+	if opts.DecelRateDegsPerSec2 != 0 {
+		out[decelDegsKey] = opts.DecelRateDegsPerSec2
+	}
+	if opts.DecelRateMillisPerSec2 != 0 {
+		out[decelMillisKey] = opts.DecelRateMillisPerSec2
+	}
+	return out
+}
+
+// StopOptionsFromExtra reads the StopOptions a caller passed in extra. Base implementations that
+// support a controlled stop should use this in Stop to find the requested deceleration.
+func StopOptionsFromExtra(extra map[string]interface{}) StopOptions {
+	var opts StopOptions
+	if decel, ok := extra[decelDegsKey].(float64); ok {
+		opts.DecelRateDegsPerSec2 = decel
+	}
+	if decel, ok := extra[decelMillisKey].(float64); ok {
+		opts.DecelRateMillisPerSec2 = decel
+	}
+	return opts
+}
+
+// StopWithOptions calls Stop on the given base with the options added to extra.
+func StopWithOptions(ctx context.Context, b Base, opts StopOptions, extra map[string]interface{}) error {
+	return b.Stop(ctx, opts.ToExtra(extra))
+}
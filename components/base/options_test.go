@@ -0,0 +1,78 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestMoveOptions(t *testing.T) {
+	opts := base.MoveOptions{AccelerationMmPerSecPerSec: 100, MaxJerkMmPerSecCubed: 500}
+
+	var gotExtra map[string]interface{}
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		gotExtra = extra
+		return nil
+	}
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		gotExtra = extra
+		return nil
+	}
+
+	t.Run("MoveStraightWithOptions", func(t *testing.T) {
+		extra := map[string]interface{}{"foo": "bar"}
+		err := base.MoveStraightWithOptions(context.Background(), injectBase, 10, 20, opts, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gotExtra["foo"], test.ShouldEqual, "bar")
+		test.That(t, base.MoveOptionsFromExtra(gotExtra), test.ShouldResemble, opts)
+		test.That(t, extra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+	})
+
+	t.Run("SpinWithOptions", func(t *testing.T) {
+		err := base.SpinWithOptions(context.Background(), injectBase, 90, 45, opts, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, base.MoveOptionsFromExtra(gotExtra), test.ShouldResemble, opts)
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		err := base.MoveStraightWithOptions(context.Background(), injectBase, 10, 20, base.MoveOptions{}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gotExtra, test.ShouldResemble, map[string]interface{}{})
+		test.That(t, base.MoveOptionsFromExtra(gotExtra), test.ShouldResemble, base.MoveOptions{})
+	})
+}
+
+func TestStopOptions(t *testing.T) {
+	opts := base.StopOptions{DecelRateDegsPerSec2: 90, DecelRateMillisPerSec2: 250}
+
+	var gotExtra map[string]interface{}
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		gotExtra = extra
+		return nil
+	}
+
+	t.Run("StopWithOptions", func(t *testing.T) {
+		extra := map[string]interface{}{"foo": "bar"}
+		err := base.StopWithOptions(context.Background(), injectBase, opts, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gotExtra["foo"], test.ShouldEqual, "bar")
+		test.That(t, base.StopOptionsFromExtra(gotExtra), test.ShouldResemble, opts)
+		test.That(t, extra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+	})
+
+	t.Run("Stop is immediate", func(t *testing.T) {
+		err := injectBase.Stop(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, base.StopOptionsFromExtra(gotExtra), test.ShouldResemble, base.StopOptions{})
+
+		err = base.StopWithOptions(context.Background(), injectBase, base.StopOptions{}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, gotExtra, test.ShouldResemble, map[string]interface{}{})
+	})
+}
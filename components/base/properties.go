@@ -1,13 +1,28 @@
 // Package base contains an enum representing optional base features
 package base
 
-import pb "go.viam.com/api/component/base/v1"
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/edaniels/golog"
+	pb "go.viam.com/api/component/base/v1"
+)
 
 // Properties is a structure representing features
 // of a base.
 type Properties struct {
 	TurningRadiusMeters float64
 	WidthMeters         float64
+	// MaxLinearVelocityMillisPerSec is the fastest the base can drive straight, or zero if it is unknown.
+	// It is not part of the properties API, so it is zero in the Properties of a client. The helpers that
+	// use it get it from the server with a reserved DoCommand, see getExtraPropertiesCommand.
+	MaxLinearVelocityMillisPerSec float64
+	// IsHolonomic is true for bases that can move in any direction without turning first, such as omni
+	// and mecanum bases. It is not part of the properties API, so it is false in the Properties of a
+	// client, and gotten like MaxLinearVelocityMillisPerSec by the helpers that use it.
+	IsHolonomic bool
 }
 
 // ProtoFeaturesToProperties takes a GetPropertiesResponse and returns
@@ -34,3 +49,64 @@ func PropertiesToProtoResponse(
 		WidthMeters:         features.WidthMeters,
 	}, nil
 }
+
+// The properties API has no fields for some of the Properties, so the client gets them from the server
+// over DoCommand with a reserved command that the server handles before the base sees it, as with the
+// acceleration limits. The base's Properties are called with the extra under extraPropertiesExtraKey.
+const (
+	getExtraPropertiesCommand = "rdk:base:get_extra_properties"
+	extraPropertiesExtraKey   = "extra"
+	maxLinearVelocityKey      = "max_linear_velocity_millis_per_sec"
+	isHolonomicKey            = "is_holonomic"
+)
+
+func extraPropertiesToMap(props Properties) map[string]interface{} {
+	return map[string]interface{}{
+		maxLinearVelocityKey: props.MaxLinearVelocityMillisPerSec,
+		isHolonomicKey:       props.IsHolonomic,
+	}
+}
+
+// addExtraPropertiesFromMap sets the Properties in m on props. Servers from before the command existed
+// pass it on to the base's DoCommand, so anything missing from m is left unknown.
+func addExtraPropertiesFromMap(props *Properties, m map[string]interface{}) {
+	if v, ok := m[maxLinearVelocityKey].(float64); ok {
+		props.MaxLinearVelocityMillisPerSec = v
+	}
+	if v, ok := m[isHolonomicKey].(bool); ok {
+		props.IsHolonomic = v
+	}
+}
+
+// extraPropertiesGetter is implemented by bases, such as the client, whose Properties leave out the
+// ones the properties API has no fields for unless asked for them.
+type extraPropertiesGetter interface {
+	extraProperties(ctx context.Context, extra map[string]interface{}) (Properties, error)
+}
+
+// allProperties returns all of the Properties of b. For a client this takes an extra round trip, so
+// only the helpers that need the Properties missing from the properties API use it.
+func allProperties(ctx context.Context, b Base, extra map[string]interface{}) (Properties, error) {
+	if g, ok := b.(extraPropertiesGetter); ok {
+		return g.extraProperties(ctx, extra)
+	}
+	return b.Properties(ctx, extra)
+}
+
+// widthGetDeprecation makes WidthGet warn that it is deprecated only the first time it is called, so
+// that callers in a loop do not flood the logs.
+var widthGetDeprecation sync.Once
+
+// WidthGet returns the width of the base in millimeters, taken from its properties.
+//
+// Deprecated: Use the WidthMeters of the base's Properties instead.
+func WidthGet(ctx context.Context, b Base) (int, error) {
+	widthGetDeprecation.Do(func() {
+		golog.Global().Warn("base.WidthGet is deprecated and will be removed, use the WidthMeters of the base's Properties instead")
+	})
+	props, err := b.Properties(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(props.WidthMeters * 1000)), nil
+}
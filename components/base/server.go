@@ -6,6 +6,7 @@ import (
 
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/base/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/protoutils"
@@ -173,5 +174,38 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	cmd := req.GetCommand().AsMap()
+	switch cmd[reservedCommandKey] {
+	case setAccelerationLimitsCommand:
+		linear, angular, err := accelerationLimitsFromMap(cmd)
+		if err != nil {
+			return nil, err
+		}
+		if err := base.SetAccelerationLimits(ctx, linear, angular); err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: &structpb.Struct{}}, nil
+	case getAccelerationLimitsCommand:
+		linear, angular, err := base.GetAccelerationLimits(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := structpb.NewStruct(accelerationLimitsToMap(linear, angular))
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: res}, nil
+	case getExtraPropertiesCommand:
+		extra, _ := cmd[extraPropertiesExtraKey].(map[string]interface{})
+		props, err := base.Properties(ctx, extra)
+		if err != nil {
+			return nil, err
+		}
+		res, err := structpb.NewStruct(extraPropertiesToMap(props))
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: res}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, base, req)
 }
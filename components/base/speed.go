@@ -0,0 +1,44 @@
+package base
+
+import (
+	"context"
+	"math"
+
+	"github.com/edaniels/golog"
+)
+
+// ClampLinearSpeed limits mmPerSec to the max linear velocity in props, keeping its sign, and reports
+// whether it had to. Speeds are left alone if the base does not report a max.
+func ClampLinearSpeed(mmPerSec float64, props Properties) (float64, bool) {
+	max := props.MaxLinearVelocityMillisPerSec
+	if max <= 0 || math.Abs(mmPerSec) <= max {
+		return mmPerSec, false
+	}
+	return math.Copysign(max, mmPerSec), true
+}
+
+// MoveStraightClamped moves the base like MoveStraight, but first clamps mmPerSec to the max linear
+// velocity the base reports in its properties. For a remote base, that takes a DoCommand the server
+// must know, on top of GetProperties. If logger is not nil, a warning is logged whenever the speed is
+// clamped.
+func MoveStraightClamped(
+	ctx context.Context,
+	b Base,
+	distanceMm int,
+	mmPerSec float64,
+	logger golog.Logger,
+	extra map[string]interface{},
+) error {
+	props, err := allProperties(ctx, b, extra)
+	if err != nil {
+		return err
+	}
+	if clamped, ok := ClampLinearSpeed(mmPerSec, props); ok {
+		if logger != nil {
+			logger.Warnf("requested speed %.1f mm/s is faster than the base's max of %.1f mm/s; moving at %.1f mm/s instead",
+				mmPerSec, props.MaxLinearVelocityMillisPerSec, clamped)
+		}
+		mmPerSec = clamped
+	}
+	return b.MoveStraight(ctx, distanceMm, mmPerSec, extra)
+}
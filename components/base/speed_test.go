@@ -0,0 +1,59 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestClampLinearSpeed(t *testing.T) {
+	props := base.Properties{MaxLinearVelocityMillisPerSec: 300}
+	for _, tc := range []struct {
+		mmPerSec, expected float64
+		clamped            bool
+	}{
+		{100, 100, false},
+		{300, 300, false},
+		{500, 300, true},
+		{-500, -300, true},
+		{-200, -200, false},
+	} {
+		speed, clamped := base.ClampLinearSpeed(tc.mmPerSec, props)
+		test.That(t, speed, test.ShouldEqual, tc.expected)
+		test.That(t, clamped, test.ShouldEqual, tc.clamped)
+	}
+
+	speed, clamped := base.ClampLinearSpeed(5000, base.Properties{})
+	test.That(t, speed, test.ShouldEqual, 5000)
+	test.That(t, clamped, test.ShouldBeFalse)
+}
+
+func TestMoveStraightClamped(t *testing.T) {
+	var gotMmPerSec float64
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+		return base.Properties{MaxLinearVelocityMillisPerSec: 300}, nil
+	}
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		gotMmPerSec = mmPerSec
+		return nil
+	}
+
+	logger, obs := golog.NewObservedTestLogger(t)
+	test.That(t, base.MoveStraightClamped(context.Background(), injectBase, -100, -1000, logger, nil), test.ShouldBeNil)
+	test.That(t, gotMmPerSec, test.ShouldEqual, -300)
+	test.That(t, obs.FilterMessageSnippet("faster than the base's max").Len(), test.ShouldEqual, 1)
+
+	test.That(t, base.MoveStraightClamped(context.Background(), injectBase, 100, 200, logger, nil), test.ShouldBeNil)
+	test.That(t, gotMmPerSec, test.ShouldEqual, 200)
+	test.That(t, obs.FilterMessageSnippet("faster than the base's max").Len(), test.ShouldEqual, 1)
+
+	// raw MoveStraight is not clamped
+	test.That(t, injectBase.MoveStraight(context.Background(), 100, 1000, nil), test.ShouldBeNil)
+	test.That(t, gotMmPerSec, test.ShouldEqual, 1000)
+}
@@ -0,0 +1,90 @@
+package base
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	// spinMinCorrectionDeg is the smallest correcting spin SpinClosedLoop makes, since bases tend not to
+	// turn at all for smaller ones.
+	spinMinCorrectionDeg = 1.0
+	// spinMaxCorrections is how many correcting spins SpinClosedLoop makes before giving up.
+	spinMaxCorrections = 10
+)
+
+// SpinClosedLoop spins the base angleDeg at degsPerSec like Spin, then corrects the spin using the
+// compass heading of dev until the base is within toleranceDeg of where the spin should have left it.
+// Spins are inaccurate in the open, so angleDeg, which may be more than a full turn, is spun first and
+// only what is left of it is corrected. Each correction is at least spinMinCorrectionDeg so that it turns
+// the base, and if one does not bring the base closer to the target, such as when it overshoots, an
+// error is returned rather than spinning back and forth around it. The base is stopped if spinning fails
+// or ctx is cancelled.
+func SpinClosedLoop(
+	ctx context.Context,
+	b Base,
+	dev movementsensor.MovementSensor,
+	angleDeg float64,
+	degsPerSec float64,
+	toleranceDeg float64,
+) error {
+	if degsPerSec <= 0 {
+		return errors.Errorf("degsPerSec must be greater than 0, got %v", degsPerSec)
+	}
+	if toleranceDeg < 0 {
+		return errors.Errorf("tolerance must not be negative, got %v", toleranceDeg)
+	}
+	start, err := dev.CompassHeading(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// compass headings grow clockwise and spins turn counterclockwise.
+	target := utils.ModAngDeg(start - angleDeg)
+
+	spin := func() error {
+		if angleDeg != 0 {
+			if err := b.Spin(ctx, angleDeg, degsPerSec, nil); err != nil {
+				return err
+			}
+		}
+		lastDrift := math.Inf(1)
+		for corrections := 0; ; corrections++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			heading, err := dev.CompassHeading(ctx, nil)
+			if err != nil {
+				return err
+			}
+			// a base left clockwise of the target by some angle is corrected by spinning back by it.
+			drift := utils.SignedAngleDiffDeg(target, heading)
+			switch {
+			case math.Abs(drift) <= toleranceDeg:
+				return nil
+			case corrections == spinMaxCorrections:
+				return errors.Errorf("base is still %.1f degrees from its target heading after %d corrections", drift, corrections)
+			case math.Abs(drift) >= math.Abs(lastDrift):
+				return errors.Errorf("base is oscillating around its target heading, it was %.1f degrees off and is now %.1f",
+					lastDrift, drift)
+			}
+			lastDrift = drift
+
+			correction := drift
+			if math.Abs(correction) < spinMinCorrectionDeg {
+				correction = math.Copysign(spinMinCorrectionDeg, drift)
+			}
+			if err := b.Spin(ctx, correction, degsPerSec, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if err := spin(); err != nil {
+		return stopOnError(b, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/utils"
+)
+
+// newSlippingBase returns a base that only turns gain of the way on every spin.
+func newSlippingBase(startDeg, gain float64) *driftingBase {
+	d := newDriftingBase(startDeg, 0)
+	d.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		d.spins = append(d.spins, angleDeg)
+		d.turn(angleDeg * gain)
+		return nil
+	}
+	return d
+}
+
+func TestSpinClosedLoop(t *testing.T) {
+	t.Run("short spin is corrected", func(t *testing.T) {
+		d := newSlippingBase(10, 0.9)
+		err := base.SpinClosedLoop(context.Background(), d, d.compass, 90, 45, 0.5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, d.spins[0], test.ShouldEqual, 90)
+		test.That(t, d.spins[1], test.ShouldAlmostEqual, 9)
+		test.That(t, utils.AngleDiffDeg(d.heading, 280), test.ShouldBeLessThanOrEqualTo, 0.5)
+		test.That(t, d.stops, test.ShouldEqual, 0)
+	})
+
+	t.Run("multiple turns across north", func(t *testing.T) {
+		d := newSlippingBase(350, 0.95)
+		err := base.SpinClosedLoop(context.Background(), d, d.compass, -450, 45, 1)
+		test.That(t, err, test.ShouldBeNil)
+		// the full turn is spun first, and only the 22.5 degrees it fell short by is corrected.
+		test.That(t, d.spins[0], test.ShouldEqual, -450)
+		test.That(t, d.spins[1], test.ShouldAlmostEqual, -22.5)
+		test.That(t, utils.AngleDiffDeg(d.heading, 80), test.ShouldBeLessThanOrEqualTo, 1)
+	})
+
+	t.Run("small corrections are made at the minimum angle", func(t *testing.T) {
+		d := newSlippingBase(0, 1)
+		spin := d.SpinFunc
+		d.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			if len(d.spins) == 0 {
+				angleDeg -= 0.8
+			}
+			return spin(ctx, angleDeg, degsPerSec, extra)
+		}
+		err := base.SpinClosedLoop(context.Background(), d, d.compass, 90, 45, 0.5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, d.spins, test.ShouldHaveLength, 2)
+		test.That(t, d.spins[1], test.ShouldEqual, 1)
+		test.That(t, utils.AngleDiffDeg(d.heading, 270), test.ShouldBeLessThanOrEqualTo, 0.5)
+	})
+
+	t.Run("oscillation", func(t *testing.T) {
+		d := newSlippingBase(0, 2.5)
+		err := base.SpinClosedLoop(context.Background(), d, d.compass, 10, 45, 1)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "oscillating")
+		test.That(t, d.spins, test.ShouldHaveLength, 2)
+		test.That(t, d.stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		d := newSlippingBase(0, 0.5)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		spin := d.SpinFunc
+		d.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			cancel()
+			return spin(ctx, angleDeg, degsPerSec, extra)
+		}
+		err := base.SpinClosedLoop(ctx, d, d.compass, 90, 45, 1)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, d.spins, test.ShouldHaveLength, 1)
+		test.That(t, d.stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		d := newSlippingBase(0, 1)
+		test.That(t, base.SpinClosedLoop(context.Background(), d, d.compass, 90, 0, 1), test.ShouldNotBeNil)
+		test.That(t, base.SpinClosedLoop(context.Background(), d, d.compass, 90, 45, -1), test.ShouldNotBeNil)
+		test.That(t, d.spins, test.ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,33 @@
+package base
+
+import (
+	"context"
+
+	"github.com/golang/geo/r3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrLateralVelocityUnimplemented is returned when a base that is not holonomic is asked to move
+// sideways. It is an Unimplemented gRPC status, like ErrAccelerationLimitsUnimplemented.
+var ErrLateralVelocityUnimplemented = status.Error(codes.Unimplemented, "base is not holonomic and cannot move sideways")
+
+// SetVelocityVector sets the velocity of b as a single vector in mm/sec, with positive X moving to the
+// right and positive Y forwards, while it turns at angularDegsPerSec (positive turns to the left). Only
+// holonomic bases, whose Properties have IsHolonomic set, can move sideways; for any other base, a
+// linear with a sideways part fails with ErrLateralVelocityUnimplemented. Vertical motion is ignored.
+func SetVelocityVector(ctx context.Context, b Base, linear r3.Vector, angularDegsPerSec float64) error {
+	velocity := r3.Vector{X: linear.X, Y: linear.Y}
+	// the properties are only looked up for sideways moves, since for a remote base that takes two calls
+	// on top of SetVelocity, which is often called in a loop.
+	if velocity.X != 0 {
+		props, err := allProperties(ctx, b, nil)
+		if err != nil {
+			return err
+		}
+		if !props.IsHolonomic {
+			return ErrLateralVelocityUnimplemented
+		}
+	}
+	return b.SetVelocity(ctx, velocity, r3.Vector{Z: angularDegsPerSec}, nil)
+}
@@ -0,0 +1,59 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestSetVelocityVector(t *testing.T) {
+	newBase := func(holonomic bool) (*inject.Base, *[]r3.Vector) {
+		var calls []r3.Vector
+		injectBase := inject.NewBase(testBaseName)
+		injectBase.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+			return base.Properties{IsHolonomic: holonomic}, nil
+		}
+		injectBase.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+			calls = append(calls, linear, angular)
+			return nil
+		}
+		return injectBase, &calls
+	}
+
+	t.Run("holonomic base", func(t *testing.T) {
+		injectBase, calls := newBase(true)
+		err := base.SetVelocityVector(context.Background(), injectBase, r3.Vector{X: 100, Y: -50, Z: 10}, 30)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldResemble, []r3.Vector{{X: 100, Y: -50}, {Z: 30}})
+	})
+
+	t.Run("non-holonomic base", func(t *testing.T) {
+		injectBase, calls := newBase(false)
+		err := base.SetVelocityVector(context.Background(), injectBase, r3.Vector{X: 100, Y: -50}, 30)
+		test.That(t, err, test.ShouldBeError, base.ErrLateralVelocityUnimplemented)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+		test.That(t, *calls, test.ShouldBeEmpty)
+
+		err = base.SetVelocityVector(context.Background(), injectBase, r3.Vector{Y: 200}, -15)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldResemble, []r3.Vector{{Y: 200}, {Z: -15}})
+	})
+
+	t.Run("properties are only needed to move sideways", func(t *testing.T) {
+		injectBase, calls := newBase(false)
+		injectBase.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+			return base.Properties{}, errors.New("properties should not be needed")
+		}
+		err := base.SetVelocityVector(context.Background(), injectBase, r3.Vector{Y: 200}, -15)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldResemble, []r3.Vector{{Y: 200}, {Z: -15}})
+	})
+}
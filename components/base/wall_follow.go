@@ -0,0 +1,92 @@
+package base
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/sensor"
+)
+
+// Side is the side of a base that a wall is on.
+type Side int
+
+// The sides a base can follow a wall on.
+const (
+	SideLeft Side = iota
+	SideRight
+)
+
+const (
+	// wallFollowInterval is how often WallFollow reads the distance to the wall and corrects its heading.
+	wallFollowInterval = 100 * time.Millisecond
+	// wallFollowGain is how fast WallFollow turns, in degrees per second, per millimeter it is off the
+	// target distance.
+	wallFollowGain = 0.5
+	// wallFollowMaxDegsPerSec caps the correction so that a gap in the wall doesn't spin the base around.
+	wallFollowMaxDegsPerSec = 45.0
+)
+
+// WallFollow drives the base forward at speed mm/s while keeping a wall on the given side
+// targetDistanceMillis away. distSensor must be a distance sensor, such as an ultrasonic sensor, that
+// faces the wall and reports the distance to it in meters as its "distance" reading. The heading is
+// corrected in proportion to how far the base is from the target distance: it arcs towards the wall
+// when too far and away from it when too close. WallFollow runs until ctx is cancelled or the sensor
+// or base fails, and stops the base before returning.
+func WallFollow(
+	ctx context.Context,
+	b Base,
+	side Side,
+	targetDistanceMillis float64,
+	distSensor sensor.Sensor,
+	speed float64,
+) error {
+	if side != SideLeft && side != SideRight {
+		return errors.Errorf("unknown wall side %d", side)
+	}
+	step := func() error {
+		distanceMillis, err := readDistanceMillis(ctx, distSensor)
+		if err != nil {
+			return err
+		}
+		// turning counterclockwise, which is positive, brings the base towards a wall on its left.
+		degsPerSec := wallFollowGain * (distanceMillis - targetDistanceMillis)
+		if side == SideRight {
+			degsPerSec = -degsPerSec
+		}
+		degsPerSec = math.Max(-wallFollowMaxDegsPerSec, math.Min(wallFollowMaxDegsPerSec, degsPerSec))
+		return b.SetVelocity(ctx, r3.Vector{Y: speed}, r3.Vector{Z: degsPerSec}, nil)
+	}
+
+	ticker := time.NewTicker(wallFollowInterval)
+	defer ticker.Stop()
+	for {
+		err := step()
+		if err == nil {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+			case <-ticker.C:
+			}
+		}
+		if err != nil {
+			return stopOnError(b, err)
+		}
+	}
+}
+
+// readDistanceMillis returns the "distance" reading of a distance sensor, converted from meters.
+func readDistanceMillis(ctx context.Context, distSensor sensor.Sensor) (float64, error) {
+	readings, err := distSensor.Readings(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	distance, ok := readings["distance"].(float64)
+	if !ok {
+		return 0, errors.Errorf("sensor %s has no distance reading in meters, got %v", distSensor.Name(), readings)
+	}
+	return distance * 1000, nil
+}
@@ -0,0 +1,88 @@
+package base_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestWallFollow(t *testing.T) {
+	// the base drifts from 300mm to 400mm, 300mm and 200mm off the wall, then the sensor fails.
+	distancesMeters := []float64{0.3, 0.4, 0.3, 0.2}
+	errSensor := errors.New("sensor unplugged")
+	newSensor := func() *inject.Sensor {
+		var read int
+		s := inject.NewSensor("ultrasonic")
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			if read == len(distancesMeters) {
+				return nil, errSensor
+			}
+			read++
+			return map[string]interface{}{"distance": distancesMeters[read-1]}, nil
+		}
+		return s
+	}
+	newBase := func() (*inject.Base, *[]r3.Vector, *int) {
+		var turns []r3.Vector
+		var stops int
+		b := inject.NewBase(testBaseName)
+		b.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+			test.That(t, linear, test.ShouldResemble, r3.Vector{Y: 200})
+			turns = append(turns, angular)
+			return nil
+		}
+		b.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+			stops++
+			return nil
+		}
+		return b, &turns, &stops
+	}
+
+	t.Run("wall on the left", func(t *testing.T) {
+		b, turns, stops := newBase()
+		err := base.WallFollow(context.Background(), b, base.SideLeft, 300, newSensor(), 200)
+		test.That(t, err, test.ShouldBeError, errSensor)
+		// too far from the wall turns towards it, counterclockwise, and too close turns away.
+		test.That(t, *turns, test.ShouldResemble, []r3.Vector{{Z: 0}, {Z: 45}, {Z: 0}, {Z: -45}})
+		test.That(t, *stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("wall on the right", func(t *testing.T) {
+		b, turns, stops := newBase()
+		err := base.WallFollow(context.Background(), b, base.SideRight, 350, newSensor(), 200)
+		test.That(t, err, test.ShouldBeError, errSensor)
+		test.That(t, *turns, test.ShouldResemble, []r3.Vector{{Z: 25}, {Z: -25}, {Z: 25}, {Z: 45}})
+		test.That(t, *stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		b, _, stops := newBase()
+		s := inject.NewSensor("ultrasonic")
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			cancel()
+			return map[string]interface{}{"distance": 0.3}, nil
+		}
+		err := base.WallFollow(ctx, b, base.SideLeft, 300, s, 200)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, *stops, test.ShouldEqual, 1)
+	})
+
+	t.Run("not a distance sensor", func(t *testing.T) {
+		b, _, stops := newBase()
+		s := inject.NewSensor("thermometer")
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{"celsius": 21.0}, nil
+		}
+		err := base.WallFollow(context.Background(), b, base.SideLeft, 300, s, 200)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "has no distance reading")
+		test.That(t, *stops, test.ShouldEqual, 1)
+	})
+}
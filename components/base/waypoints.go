@@ -0,0 +1,96 @@
+package base
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/utils"
+)
+
+// FollowWaypoints drives the base through each of the given legs in order. For every leg, AngleDeg is
+// treated as the absolute compass heading to travel along rather than a relative spin. Before each straight
+// segment the compass is read and the base spins by whatever is needed to face that heading, correcting
+// for any drift picked up on previous legs. The base is stopped if an error occurs or the context is cancelled.
+func FollowWaypoints(ctx context.Context, b Base, compass movementsensor.MovementSensor, legs []Move) (err error) {
+	defer func() {
+		err = stopOnError(b, err)
+	}()
+
+	for _, leg := range legs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		heading, err := compass.CompassHeading(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := DoMove(ctx, Move{
+			DistanceMm: leg.DistanceMm,
+			MmPerSec:   leg.MmPerSec,
+			AngleDeg:   spinToHeading(heading, leg.AngleDeg),
+			DegsPerSec: leg.DegsPerSec,
+		}, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// turnToMaxSpins caps how many spins TurnTo makes, so that a base that keeps overshooting the target
+// heading does not oscillate around it forever.
+const turnToMaxSpins = 10
+
+// TurnTo spins the base until the compass reports a heading within toleranceDeg of targetHeadingDeg.
+// Each spin takes the shortest way round to the target, after which the compass is read again to
+// correct for any overshoot or slip. It gives up after turnToMaxSpins spins. The base is stopped when
+// TurnTo returns, including when the context is cancelled.
+func TurnTo(
+	ctx context.Context,
+	b Base,
+	compass movementsensor.MovementSensor,
+	targetHeadingDeg,
+	degsPerSec,
+	toleranceDeg float64,
+) (err error) {
+	defer func() {
+		if err == nil {
+			// the base is left stopped even when it did not have to turn.
+			err = b.Stop(ctx, nil)
+			return
+		}
+		err = stopOnError(b, err)
+	}()
+
+	var heading float64
+	for spins := 0; ; spins++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		heading, err = compass.CompassHeading(ctx, nil)
+		if err != nil {
+			return err
+		}
+		angle := spinToHeading(heading, targetHeadingDeg)
+		if math.Abs(angle) <= toleranceDeg {
+			return nil
+		}
+		if spins == turnToMaxSpins {
+			break
+		}
+		if err := b.Spin(ctx, angle, degsPerSec, nil); err != nil {
+			return err
+		}
+	}
+	return errors.Errorf("base did not turn to within %.1f degrees of heading %.1f after %d spins, it is at %.1f",
+		toleranceDeg, targetHeadingDeg, turnToMaxSpins, heading)
+}
+
+// spinToHeading returns the smallest spin, in degrees, that turns a base facing the current compass
+// heading to face the target one. Compass headings increase clockwise while a positive spin turns
+// the base to the left, so the result is in [-180, 180) with positive values meaning counterclockwise.
+func spinToHeading(current, target float64) float64 {
+	return utils.SignedAngleDiffDeg(target, current)
+}
@@ -0,0 +1,156 @@
+package base_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestFollowWaypoints(t *testing.T) {
+	var spins []float64
+	var straights []int
+	stopCount := 0
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		spins = append(spins, angleDeg)
+		return nil
+	}
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		straights = append(straights, distanceMm)
+		return nil
+	}
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stopCount++
+		return nil
+	}
+
+	// the compass reports the base drifting a little off of each requested heading
+	headings := []float64{0, 95, 170}
+	reads := 0
+	compass := inject.NewMovementSensor("compass")
+	compass.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		h := headings[reads]
+		reads++
+		return h, nil
+	}
+
+	legs := []base.Move{
+		{AngleDeg: 0, DegsPerSec: 30, DistanceMm: 100, MmPerSec: 50},
+		{AngleDeg: 90, DegsPerSec: 30, DistanceMm: 200, MmPerSec: 50},
+		{AngleDeg: 180, DegsPerSec: 30, DistanceMm: 300, MmPerSec: 50},
+	}
+
+	t.Run("corrective spins", func(t *testing.T) {
+		err := base.FollowWaypoints(context.Background(), injectBase, compass, legs)
+		test.That(t, err, test.ShouldBeNil)
+		// first leg is already on heading, the second drifted 5 degrees clockwise and the third is 10 short.
+		test.That(t, spins, test.ShouldResemble, []float64{5, -10})
+		test.That(t, straights, test.ShouldResemble, []int{100, 200, 300})
+		test.That(t, stopCount, test.ShouldEqual, 0)
+	})
+
+	t.Run("wraps around north", func(t *testing.T) {
+		spins = nil
+		straights = nil
+		reads = 0
+		headings = []float64{350}
+		err := base.FollowWaypoints(context.Background(), injectBase, compass, []base.Move{{AngleDeg: 10, DegsPerSec: 30}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spins, test.ShouldResemble, []float64{-20})
+		test.That(t, straights, test.ShouldBeEmpty)
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		errCompass := errors.New("no heading")
+		compass.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			return 0, errCompass
+		}
+		err := base.FollowWaypoints(context.Background(), injectBase, compass, legs)
+		test.That(t, err, test.ShouldBeError, errCompass)
+		test.That(t, stopCount, test.ShouldEqual, 1)
+	})
+
+	t.Run("stops on cancellation", func(t *testing.T) {
+		stopCount = 0
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := base.FollowWaypoints(ctx, injectBase, compass, legs)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, stopCount, test.ShouldEqual, 1)
+	})
+}
+
+func TestTurnTo(t *testing.T) {
+	var heading float64
+	var spins []float64
+	stopCount := 0
+	// the base only turns 80% of each requested spin, so it needs several corrections to converge.
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		spins = append(spins, angleDeg)
+		heading = math.Mod(heading-0.8*angleDeg+360, 360)
+		return nil
+	}
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		stopCount++
+		return nil
+	}
+	compass := inject.NewMovementSensor("compass")
+	compass.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return heading, nil
+	}
+
+	t.Run("converges", func(t *testing.T) {
+		heading, spins, stopCount = 350, nil, 0
+		err := base.TurnTo(context.Background(), injectBase, compass, 90, 30, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, math.Abs(heading-90), test.ShouldBeLessThanOrEqualTo, 1)
+		test.That(t, len(spins), test.ShouldBeGreaterThan, 1)
+		// clockwise through north is the short way, and each correction is smaller than the last
+		test.That(t, spins[0], test.ShouldAlmostEqual, -100)
+		for i := 1; i < len(spins); i++ {
+			test.That(t, math.Abs(spins[i]), test.ShouldBeLessThan, math.Abs(spins[i-1]))
+		}
+		test.That(t, stopCount, test.ShouldEqual, 1)
+	})
+
+	t.Run("already on heading", func(t *testing.T) {
+		heading, spins, stopCount = 89.5, nil, 0
+		err := base.TurnTo(context.Background(), injectBase, compass, 90, 30, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spins, test.ShouldBeEmpty)
+		test.That(t, stopCount, test.ShouldEqual, 1)
+	})
+
+	t.Run("gives up on a base that does not turn", func(t *testing.T) {
+		stuckBase := inject.NewBase(testBaseName)
+		stuckSpins := 0
+		stuckBase.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+			stuckSpins++
+			return nil
+		}
+		stuckBase.StopFunc = injectBase.StopFunc
+		heading, stopCount = 0, 0
+		err := base.TurnTo(context.Background(), stuckBase, compass, 90, 30, 1)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "did not turn to within 1.0 degrees of heading 90.0")
+		test.That(t, stuckSpins, test.ShouldEqual, 10)
+		test.That(t, stopCount, test.ShouldEqual, 1)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		heading, spins, stopCount = 0, nil, 0
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := base.TurnTo(ctx, injectBase, compass, 90, 30, 1)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, spins, test.ShouldBeEmpty)
+		test.That(t, stopCount, test.ShouldEqual, 1)
+	})
+}
@@ -450,6 +450,14 @@ func (sb *sensorBase) Geometries(ctx context.Context, extra map[string]interface
 	return sb.wBase.Geometries(ctx, extra)
 }
 
+func (sb *sensorBase) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	return sb.wBase.SetAccelerationLimits(ctx, linearMillisPerSec2, angularDegsPerSec2)
+}
+
+func (sb *sensorBase) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	return sb.wBase.GetAccelerationLimits(ctx)
+}
+
 func (sb *sensorBase) Close(ctx context.Context) error {
 	if err := sb.Stop(ctx, nil); err != nil {
 		return err
@@ -340,7 +340,8 @@ func TestSensorBase(t *testing.T) {
 
 	geometries, err := sb.Geometries(ctx, nil)
 	test.That(t, err, test.ShouldBeNil)
-	test.That(t, geometries, test.ShouldBeNil)
+	test.That(t, geometries, test.ShouldNotBeNil)
+	test.That(t, geometries, test.ShouldBeEmpty)
 
 	test.That(t, sb.SetPower(ctx, r3.Vector{X: 0, Y: 10, Z: 0}, r3.Vector{X: 0, Y: 0, Z: 0}, nil), test.ShouldBeNil)
 	test.That(t, sb.SetVelocity(ctx, r3.Vector{X: 0, Y: 100, Z: 0}, r3.Vector{X: 0, Y: 100, Z: 0}, nil), test.ShouldBeNil)
@@ -57,12 +57,15 @@ var Model = resource.DefaultModelFamily.WithModel("wheeled")
 
 // Config is how you configure a wheeled base.
 type Config struct {
-	WidthMM              int      `json:"width_mm"`
-	WheelCircumferenceMM int      `json:"wheel_circumference_mm"`
-	SpinSlipFactor       float64  `json:"spin_slip_factor,omitempty"`
-	Left                 []string `json:"left"`
-	Right                []string `json:"right"`
-	MovementSensor       []string `json:"movement_sensor,omitempty"`
+	WidthMM              int     `json:"width_mm"`
+	WheelCircumferenceMM int     `json:"wheel_circumference_mm"`
+	SpinSlipFactor       float64 `json:"spin_slip_factor,omitempty"`
+	// MaxLinearVelocityMmPerSec is the fastest the base can drive straight, which it reports in its
+	// properties. It is unknown if unset.
+	MaxLinearVelocityMmPerSec float64  `json:"max_linear_velocity_mm_per_sec,omitempty"`
+	Left                      []string `json:"left"`
+	Right                     []string `json:"right"`
+	MovementSensor            []string `json:"movement_sensor,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -77,6 +80,11 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 		return nil, utils.NewConfigValidationFieldRequiredError(path, "wheel_circumference_mm")
 	}
 
+	if cfg.MaxLinearVelocityMmPerSec < 0 {
+		return nil, utils.NewConfigValidationError(path,
+			fmt.Errorf("max_linear_velocity_mm_per_sec must not be negative, got %v", cfg.MaxLinearVelocityMmPerSec))
+	}
+
 	if len(cfg.Left) == 0 {
 		return nil, utils.NewConfigValidationFieldRequiredError(path, "left")
 	}
@@ -106,10 +114,11 @@ func init() {
 
 type wheeledBase struct {
 	resource.Named
-	widthMm              int
-	wheelCircumferenceMm int
-	spinSlipFactor       float64
-	geometries           []spatialmath.Geometry
+	widthMm                   int
+	wheelCircumferenceMm      int
+	spinSlipFactor            float64
+	maxLinearVelocityMmPerSec float64
+	geometries                []spatialmath.Geometry
 
 	left      []motor.Motor
 	right     []motor.Motor
@@ -130,6 +139,7 @@ func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependenci
 	geometries, err := kinematicbase.CollisionGeometry(conf.Frame)
 	if err != nil {
 		wb.logger.Warnf("base %v %s", wb.Name(), err.Error())
+		geometries = []spatialmath.Geometry{}
 	}
 	wb.geometries = geometries
 
@@ -143,6 +153,7 @@ func (wb *wheeledBase) Reconfigure(ctx context.Context, deps resource.Dependenci
 	}
 
 	wb.spinSlipFactor = newConf.SpinSlipFactor
+	wb.maxLinearVelocityMmPerSec = newConf.MaxLinearVelocityMmPerSec
 
 	updateMotors := func(curr []motor.Motor, fromConfig []string, whichMotor string) ([]motor.Motor, error) {
 		newMotors := make([]motor.Motor, 0)
@@ -458,11 +469,23 @@ func (wb *wheeledBase) Close(ctx context.Context) error {
 
 func (wb *wheeledBase) Properties(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
 	return base.Properties{
-		TurningRadiusMeters: 0.0,
-		WidthMeters:         float64(wb.widthMm) * 0.001, // convert to meters from mm
+		TurningRadiusMeters:           0.0,
+		WidthMeters:                   float64(wb.widthMm) * 0.001, // convert to meters from mm
+		MaxLinearVelocityMillisPerSec: wb.maxLinearVelocityMmPerSec,
 	}, nil
 }
 
+// Geometries returns the collision geometry of the base from its frame, or an empty slice if it has none.
 func (wb *wheeledBase) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
 	return wb.geometries, nil
 }
+
+// SetAccelerationLimits is unimplemented, since the base drives its motors at a set speed.
+func (wb *wheeledBase) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	return base.ErrAccelerationLimitsUnimplemented
+}
+
+// GetAccelerationLimits is unimplemented, since the base drives its motors at a set speed.
+func (wb *wheeledBase) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	return 0, 0, base.ErrAccelerationLimitsUnimplemented
+}
@@ -66,7 +66,8 @@ func TestWheelBaseMath(t *testing.T) {
 		test.That(t, props.WidthMeters, test.ShouldEqual, 100*0.001)
 
 		geometries, err := wb.Geometries(ctx, nil)
-		test.That(t, geometries, test.ShouldBeNil)
+		test.That(t, geometries, test.ShouldNotBeNil)
+		test.That(t, geometries, test.ShouldBeEmpty)
 		test.That(t, err, test.ShouldBeNil)
 
 		err = wb.SetVelocity(ctx, r3.Vector{X: 0, Y: 10, Z: 0}, r3.Vector{X: 0, Y: 0, Z: 10}, nil)
@@ -214,6 +215,15 @@ func TestWheelBaseMath(t *testing.T) {
 		rpms, rotations = wb.spinMath(30, 10)
 		test.That(t, rpms, test.ShouldAlmostEqual, 0.523, 0.001)
 		test.That(t, rotations, test.ShouldAlmostEqual, 0.0261, 0.001)
+
+		// angles are not normalized, so a full turn or two turn the wheels that much further.
+		rpms, rotations = wb.spinMath(360, 10)
+		test.That(t, rpms, test.ShouldAlmostEqual, 0.523, 0.001)
+		test.That(t, rotations, test.ShouldAlmostEqual, 0.314, 0.001)
+
+		rpms, rotations = wb.spinMath(-720, 10)
+		test.That(t, rpms, test.ShouldAlmostEqual, -0.523, 0.001)
+		test.That(t, rotations, test.ShouldAlmostEqual, 0.628, 0.001)
 	})
 	t.Run("spin block", func(t *testing.T) {
 		go func() {
@@ -388,6 +398,16 @@ func TestWheeledBaseReconfigure(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	motorDeps = fakeMotorDependencies(t, deps)
 	test.That(t, wb.Reconfigure(ctx, motorDeps, newTestConf), test.ShouldBeNil)
+	props, err := wb.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.MaxLinearVelocityMillisPerSec, test.ShouldEqual, 0)
+
+	// the max linear velocity is reported once it is configured
+	newTestConf.ConvertedAttributes.(*Config).MaxLinearVelocityMmPerSec = 500
+	test.That(t, wb.Reconfigure(ctx, motorDeps, newTestConf), test.ShouldBeNil)
+	props, err = wb.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props.MaxLinearVelocityMillisPerSec, test.ShouldEqual, 500)
 
 	// Add a new motor to Left only to confirm that Reconfigure is impossible because cfg validation fails
 	newerTestCfg := newTestCfg()
@@ -448,6 +468,11 @@ func TestValidate(t *testing.T) {
 	deps, err = cfg.Validate("path")
 	test.That(t, deps, test.ShouldResemble, []string{"fl-m", "bl-m", "fr-m", "br-m"})
 	test.That(t, err, test.ShouldBeNil)
+
+	cfg.MaxLinearVelocityMmPerSec = -1
+	deps, err = cfg.Validate("path")
+	test.That(t, deps, test.ShouldBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "max_linear_velocity_mm_per_sec must not be negative")
 }
 
 // waitForMotorsToStop polls all motors to see if they're on, used only for testing.
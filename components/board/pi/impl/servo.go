@@ -178,6 +178,15 @@ func (s *piPigpioServo) Position(ctx context.Context, extra map[string]interface
 	return uint32(pulseWidthToAngle(int(s.res), int(s.maxRotation))), nil
 }
 
+// Range returns the configured minimum and maximum angles of the servo, falling back to
+// its maximum rotation when no maximum is set.
+func (s *piPigpioServo) Range(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+	if s.max == 0 {
+		return s.min, s.maxRotation, nil
+	}
+	return s.min, s.max, nil
+}
+
 // angleToPulseWidth changes the input angle in degrees
 // into the corresponding pulsewidth value in microsecond
 func angleToPulseWidth(angle, maxRotation int) int {
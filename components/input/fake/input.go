@@ -3,10 +3,12 @@ package fake
 
 import (
 	"context"
+	"math"
 	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
 	"go.viam.com/utils"
@@ -17,6 +19,9 @@ import (
 
 var model = resource.DefaultModelFamily.WithModel("fake")
 
+// ErrDisconnected is returned by a fake controller that has been disconnected with SetConnected.
+var ErrDisconnected = errors.New("input controller is disconnected")
+
 func init() {
 	resource.RegisterComponent(
 		input.API,
@@ -31,8 +36,9 @@ func init() {
 
 // Config can list input structs (with their states), define event values and callback delays.
 type Config struct {
-	resource.TriviallyValidateConfig
-	controls []input.Control
+	// Controls are the controls the controller reports. Each must be a known input.Control. The controller
+	// reports AbsoluteX and ButtonStart if unset.
+	Controls []input.Control `json:"controls,omitempty"`
 
 	// EventValue will dictate the value of the events returned. Random between -1 to 1 if unset.
 	EventValue *float64 `json:"event_value,omitempty"`
@@ -40,6 +46,37 @@ type Config struct {
 	// CallbackDelaySec is the amount of time between callbacks getting triggered. Random between (1-2] sec if unset.
 	// 0 is not valid and will be overwritten by a random delay.
 	CallbackDelaySec float64 `json:"callback_delay_sec"`
+
+	// Deadband snaps injected absolute axis events to 0 if their magnitude is below it, like a real gamepad's
+	// driver ignores jitter around the center of a stick. 0 disables it.
+	Deadband float64 `json:"deadband,omitempty"`
+
+	// EventMaxAgeSec makes Events leave out injected events older than it, like a controller that has stopped
+	// reporting. Events are kept forever if unset.
+	EventMaxAgeSec float64 `json:"event_max_age_sec,omitempty"`
+
+	// HistorySize is how many of the most recent injected events to keep for each control, for EventHistory.
+	// History is not kept if unset.
+	HistorySize int `json:"history_size,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	for _, control := range conf.Controls {
+		if _, err := input.ParseControl(string(control)); err != nil {
+			return nil, utils.NewConfigValidationError(path, err)
+		}
+	}
+	if conf.Deadband < 0 || conf.Deadband >= 1 {
+		return nil, utils.NewConfigValidationError(path, errors.Errorf("deadband must be in [0, 1), got %v", conf.Deadband))
+	}
+	if conf.EventMaxAgeSec < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.Errorf("event_max_age_sec cannot be negative, got %v", conf.EventMaxAgeSec))
+	}
+	if conf.HistorySize < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.Errorf("history_size cannot be negative, got %v", conf.HistorySize))
+	}
+	return nil, nil
 }
 
 type callback struct {
@@ -57,6 +94,9 @@ func NewInputController(ctx context.Context, conf resource.Config) (input.Contro
 		closeCtx:   closeCtx,
 		cancelFunc: cancelFunc,
 		callbacks:  make([]callback, 0),
+		lastEvents: make(map[input.Control]input.Event),
+		history:    make(map[input.Control][]input.Event),
+		clock:      clock.New(),
 	}
 
 	if err := c.Reconfigure(ctx, nil, conf); err != nil {
@@ -85,6 +125,18 @@ type InputController struct {
 	eventValue    *float64
 	callbackDelay *time.Duration
 	callbacks     []callback
+	deadband      float64
+	eventMaxAge   time.Duration
+	clock         clock.Clock
+	// lastEvents holds the most recent event injected for each control by TriggerEvent.
+	lastEvents map[input.Control]input.Event
+	// prevEvents is what Events returned at the last call to EventsChanged.
+	prevEvents map[input.Control]input.Event
+	// history holds up to historySize of the most recent events injected for each control, oldest first.
+	historySize int
+	history     map[input.Control][]input.Event
+	// disconnected simulates the controller going away, see SetConnected.
+	disconnected bool
 }
 
 // Reconfigure updates the config of the controller.
@@ -97,8 +149,17 @@ func (c *InputController) Reconfigure(ctx context.Context, deps resource.Depende
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.controls = newConf.controls
+	c.controls = newConf.Controls
 	c.eventValue = newConf.EventValue
+	c.deadband = newConf.Deadband
+	c.historySize = newConf.HistorySize
+	for control, events := range c.history {
+		if len(events) > c.historySize {
+			c.history[control] = append([]input.Event(nil), events[len(events)-c.historySize:]...)
+		}
+	}
+	// convert to milliseconds to avoid any issues with float to int conversions
+	c.eventMaxAge = time.Duration(newConf.EventMaxAgeSec*1000) * time.Millisecond
 	if newConf.CallbackDelaySec != 0 {
 		// convert to milliseconds to avoid any issues with float to int conversions
 		delay := time.Duration(newConf.CallbackDelaySec*1000) * time.Millisecond
@@ -111,10 +172,51 @@ func (c *InputController) Reconfigure(ctx context.Context, deps resource.Depende
 func (c *InputController) Controls(ctx context.Context, extra map[string]interface{}) ([]input.Control, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.disconnected {
+		return nil, ErrDisconnected
+	}
+	return c.controlsLocked(), nil
+}
+
+func (c *InputController) controlsLocked() []input.Control {
 	if len(c.controls) == 0 {
-		return []input.Control{input.AbsoluteX, input.ButtonStart}, nil
+		return []input.Control{input.AbsoluteX, input.ButtonStart}
+	}
+	return c.controls
+}
+
+// SetConnected simulates the controller being unplugged or plugged back in, like a real gamepad. While it
+// is disconnected, Controls and Events return ErrDisconnected, and events injected by TriggerEvent or made
+// up for callbacks are dropped. Changing the connection sends a Disconnect or Connect event for each
+// control to the callbacks registered for it.
+func (c *InputController) SetConnected(ctx context.Context, connected bool) {
+	c.mu.Lock()
+	if c.disconnected == !connected {
+		c.mu.Unlock()
+		return
+	}
+	c.disconnected = !connected
+	evType := input.Disconnect
+	if connected {
+		evType = input.Connect
+	}
+	now := c.clock.Now()
+	type call struct {
+		ctrlFunc input.ControlFunction
+		event    input.Event
+	}
+	var calls []call
+	for _, control := range c.controlsLocked() {
+		event := input.Event{Time: now, Event: evType, Control: control}
+		for _, ctrlFunc := range c.callbacksForLocked(event) {
+			calls = append(calls, call{ctrlFunc, event})
+		}
+	}
+	c.mu.Unlock()
+
+	for _, call := range calls {
+		call.ctrlFunc(ctx, call.event)
 	}
-	return c.controls, nil
 }
 
 func (c *InputController) eventVal() float64 {
@@ -125,16 +227,42 @@ func (c *InputController) eventVal() float64 {
 	return rand.Float64()
 }
 
-// Events returns the a specified or random input.Event (the current state) for AbsoluteX.
+// Events returns the a specified or random input.Event (the current state) for AbsoluteX, along with the
+// last event injected by TriggerEvent for each control, which takes precedence unless it is older than the
+// configured max age.
 func (c *InputController) Events(ctx context.Context, extra map[string]interface{}) (map[input.Control]input.Event, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.disconnected {
+		return nil, ErrDisconnected
+	}
 	eventsOut := make(map[input.Control]input.Event)
 
-	eventsOut[input.AbsoluteX] = input.Event{Time: time.Now(), Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: c.eventVal()}
+	now := c.clock.Now()
+	eventsOut[input.AbsoluteX] = input.Event{Time: now, Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: c.eventVal()}
+	for control, event := range c.lastEvents {
+		if c.eventMaxAge > 0 && now.Sub(event.Time) > c.eventMaxAge {
+			continue
+		}
+		eventsOut[control] = event
+	}
 	return eventsOut, nil
 }
 
+// EventsChanged returns the events that changed since the last call to EventsChanged (see input.EventsChanged).
+// The first call returns every current event.
+func (c *InputController) EventsChanged(ctx context.Context, extra map[string]interface{}) (map[input.Control]input.Event, error) {
+	events, err := c.Events(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := input.EventsChanged(c.prevEvents, events)
+	c.prevEvents = events
+	return changed, nil
+}
+
 // RegisterControlCallback registers a callback function to be executed on the specified trigger Event. The fake implementation will
 // trigger the callback at a random or user-specified interval with a random or user-specified value.
 func (c *InputController) RegisterControlCallback(
@@ -177,6 +305,10 @@ func (c *InputController) startCallbackLoop() {
 			return
 		default:
 			c.mu.Lock()
+			if c.disconnected {
+				c.mu.Unlock()
+				continue
+			}
 			evValue := c.eventVal()
 			for _, callback := range c.callbacks {
 				for _, t := range callback.triggers {
@@ -189,9 +321,64 @@ func (c *InputController) startCallbackLoop() {
 	}
 }
 
-// TriggerEvent allows directly sending an Event (such as a button press) from external code.
+// TriggerEvent allows directly sending an Event (such as a button press) from external code. Absolute axis
+// events within the configured deadband are snapped to 0 before being recorded and passed to callbacks.
+// Events are dropped while the controller is disconnected.
 func (c *InputController) TriggerEvent(ctx context.Context, event input.Event, extra map[string]interface{}) error {
-	return errors.New("unsupported")
+	c.mu.Lock()
+	if c.disconnected {
+		c.mu.Unlock()
+		return nil
+	}
+	if event.Event == input.PositionChangeAbs && math.Abs(event.Value) < c.deadband {
+		event.Value = 0
+	}
+	c.lastEvents[event.Control] = event
+	if c.historySize > 0 {
+		events := c.history[event.Control]
+		if len(events) == c.historySize {
+			copy(events, events[1:])
+			events[len(events)-1] = event
+		} else {
+			events = append(events, event)
+		}
+		c.history[event.Control] = events
+	}
+	ctrlFuncs := c.callbacksForLocked(event)
+	c.mu.Unlock()
+
+	for _, ctrlFunc := range ctrlFuncs {
+		ctrlFunc(ctx, event)
+	}
+	return nil
+}
+
+// callbacksForLocked returns the callbacks registered for the event's control and type.
+func (c *InputController) callbacksForLocked(event input.Event) []input.ControlFunction {
+	var ctrlFuncs []input.ControlFunction
+	for _, callback := range c.callbacks {
+		if callback.control != event.Control {
+			continue
+		}
+		for _, t := range callback.triggers {
+			if t == event.Event || t == input.AllEvents {
+				ctrlFuncs = append(ctrlFuncs, callback.ctrlFunc)
+				break
+			}
+		}
+	}
+	return ctrlFuncs
+}
+
+// EventHistory returns the most recent events injected by TriggerEvent for the control, oldest first. It
+// returns up to the configured history size of events, and an error if history is not being kept.
+func (c *InputController) EventHistory(ctx context.Context, control input.Control) ([]input.Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.historySize == 0 {
+		return nil, errors.New("event history is not kept, set history_size in the config to keep it")
+	}
+	return append([]input.Event{}, c.history[control]...), nil
 }
 
 // Close attempts to cleanly close the input controller.
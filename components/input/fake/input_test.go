@@ -5,7 +5,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/pkg/errors"
+	"github.com/benbjohnson/clock"
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/input"
@@ -26,7 +26,7 @@ var (
 func setupDefinedInput(t *testing.T) *InputController {
 	t.Helper()
 	conf := Config{
-		controls:         controls,
+		Controls:         controls,
 		EventValue:       &value,
 		CallbackDelaySec: float64(delay/time.Millisecond) / 1000,
 	}
@@ -114,6 +114,32 @@ func TestEvents(t *testing.T) {
 	}
 }
 
+func TestEventsChanged(t *testing.T) {
+	i := setupDefinedInput(t)
+	defer func() {
+		test.That(t, i.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	changed, err := i.EventsChanged(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, changed, test.ShouldHaveLength, 1)
+	test.That(t, changed[input.AbsoluteX].Value, test.ShouldAlmostEqual, value)
+
+	// the event is re-reported with a new time, but the same state
+	changed, err = i.EventsChanged(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, changed, test.ShouldBeEmpty)
+
+	newValue := -0.3
+	i.mu.Lock()
+	i.eventValue = &newValue
+	i.mu.Unlock()
+	changed, err = i.EventsChanged(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, changed, test.ShouldHaveLength, 1)
+	test.That(t, changed[input.AbsoluteX].Value, test.ShouldAlmostEqual, newValue)
+}
+
 func TestRegisterControlCallback(t *testing.T) {
 	i := setupDefinedInput(t)
 	defer func() {
@@ -148,6 +174,209 @@ func TestTriggerEvent(t *testing.T) {
 	defer func() {
 		test.That(t, i.Close(context.Background()), test.ShouldBeNil)
 	}()
-	err := i.TriggerEvent(context.Background(), input.Event{}, nil)
-	test.That(t, err, test.ShouldBeError, errors.New("unsupported"))
+	var got []input.Event
+	ctrlFunc := func(ctx context.Context, event input.Event) {
+		got = append(got, event)
+	}
+	err := i.RegisterControlCallback(context.Background(), input.ButtonSouth, []input.EventType{input.ButtonPress}, ctrlFunc, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	press := input.Event{Time: time.Now(), Event: input.ButtonPress, Control: input.ButtonSouth, Value: 1}
+	release := input.Event{Time: time.Now(), Event: input.ButtonRelease, Control: input.ButtonSouth, Value: 0}
+	test.That(t, i.TriggerEvent(context.Background(), press, nil), test.ShouldBeNil)
+	test.That(t, i.TriggerEvent(context.Background(), release, nil), test.ShouldBeNil)
+	test.That(t, got, test.ShouldResemble, []input.Event{press})
+
+	events, err := i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[input.ButtonSouth], test.ShouldResemble, release)
+}
+
+func TestDeadband(t *testing.T) {
+	i := setupInputWithCfg(t, Config{Deadband: 0.1, CallbackDelaySec: 1000})
+	defer func() {
+		test.That(t, i.Close(context.Background()), test.ShouldBeNil)
+	}()
+	var got []float64
+	ctrlFunc := func(ctx context.Context, event input.Event) {
+		got = append(got, event.Value)
+	}
+	err := i.RegisterControlCallback(context.Background(), input.AbsoluteY, []input.EventType{input.AllEvents}, ctrlFunc, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		value    float64
+		expected float64
+	}{
+		{0.05, 0},
+		{-0.09, 0},
+		{0.5, 0.5},
+		{-0.1, -0.1},
+		{0, 0},
+	} {
+		event := input.Event{Time: time.Now(), Event: input.PositionChangeAbs, Control: input.AbsoluteY, Value: tc.value}
+		test.That(t, i.TriggerEvent(context.Background(), event, nil), test.ShouldBeNil)
+		events, err := i.Events(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, events[input.AbsoluteY].Value, test.ShouldEqual, tc.expected)
+	}
+	test.That(t, got, test.ShouldResemble, []float64{0, 0, 0.5, -0.1, 0})
+
+	// buttons are not analog and are never snapped
+	button := input.Event{Time: time.Now(), Event: input.ButtonPress, Control: input.ButtonSouth, Value: 0.05}
+	test.That(t, i.TriggerEvent(context.Background(), button, nil), test.ShouldBeNil)
+	events, err := i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[input.ButtonSouth].Value, test.ShouldEqual, 0.05)
+}
+
+func TestEventMaxAge(t *testing.T) {
+	i := setupInputWithCfg(t, Config{EventMaxAgeSec: 2, CallbackDelaySec: 1000})
+	defer func() {
+		test.That(t, i.Close(context.Background()), test.ShouldBeNil)
+	}()
+	mockClock := clock.NewMock()
+	i.mu.Lock()
+	i.clock = mockClock
+	i.mu.Unlock()
+
+	held := input.Event{Time: mockClock.Now(), Event: input.ButtonHold, Control: input.ButtonSouth, Value: 1}
+	test.That(t, i.TriggerEvent(context.Background(), held, nil), test.ShouldBeNil)
+
+	mockClock.Add(2 * time.Second)
+	events, err := i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[input.ButtonSouth], test.ShouldResemble, held)
+
+	// the controller stops reporting, so the button is no longer known to be held
+	mockClock.Add(time.Millisecond)
+	events, err = i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events, test.ShouldNotContainKey, input.ButtonSouth)
+	test.That(t, events, test.ShouldContainKey, input.AbsoluteX)
+
+	released := input.Event{Time: mockClock.Now(), Event: input.ButtonRelease, Control: input.ButtonSouth, Value: 0}
+	test.That(t, i.TriggerEvent(context.Background(), released, nil), test.ShouldBeNil)
+	events, err = i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[input.ButtonSouth], test.ShouldResemble, released)
+}
+
+func TestEventHistory(t *testing.T) {
+	i := setupInputWithCfg(t, Config{HistorySize: 3, CallbackDelaySec: 1000})
+	defer func() {
+		test.That(t, i.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	history, err := i.EventHistory(context.Background(), input.AbsoluteY)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, history, test.ShouldBeEmpty)
+
+	start := time.Now()
+	var yEvents []input.Event
+	for n := 0; n < 5; n++ {
+		event := input.Event{
+			Time: start.Add(time.Duration(n) * time.Millisecond), Event: input.PositionChangeAbs, Control: input.AbsoluteY, Value: float64(n) / 10,
+		}
+		yEvents = append(yEvents, event)
+		test.That(t, i.TriggerEvent(context.Background(), event, nil), test.ShouldBeNil)
+	}
+	press := input.Event{Time: start, Event: input.ButtonPress, Control: input.ButtonSouth, Value: 1}
+	test.That(t, i.TriggerEvent(context.Background(), press, nil), test.ShouldBeNil)
+
+	// only the last 3 events of each control are kept, oldest first
+	history, err = i.EventHistory(context.Background(), input.AbsoluteY)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, history, test.ShouldResemble, yEvents[2:])
+	history, err = i.EventHistory(context.Background(), input.ButtonSouth)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, history, test.ShouldResemble, []input.Event{press})
+
+	// Events still only has the latest event
+	events, err := i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events[input.AbsoluteY], test.ShouldResemble, yEvents[4])
+
+	// shrinking the history keeps the newest events
+	err = i.Reconfigure(context.Background(), nil, resource.Config{ConvertedAttributes: &Config{HistorySize: 2}})
+	test.That(t, err, test.ShouldBeNil)
+	history, err = i.EventHistory(context.Background(), input.AbsoluteY)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, history, test.ShouldResemble, yEvents[3:])
+
+	err = i.Reconfigure(context.Background(), nil, resource.Config{ConvertedAttributes: &Config{}})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = i.EventHistory(context.Background(), input.AbsoluteY)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "history_size")
+}
+
+func TestSetConnected(t *testing.T) {
+	i := setupInputWithCfg(t, Config{Controls: []input.Control{input.ButtonSouth, input.AbsoluteX}, CallbackDelaySec: 1000})
+	defer func() {
+		test.That(t, i.Close(context.Background()), test.ShouldBeNil)
+	}()
+	var got []input.Event
+	ctrlFunc := func(ctx context.Context, event input.Event) {
+		got = append(got, event)
+	}
+	triggers := []input.EventType{input.Connect, input.Disconnect, input.ButtonPress}
+	err := i.RegisterControlCallback(context.Background(), input.ButtonSouth, triggers, ctrlFunc, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	i.SetConnected(context.Background(), false)
+	test.That(t, got, test.ShouldHaveLength, 1)
+	test.That(t, got[0].Event, test.ShouldEqual, input.Disconnect)
+	test.That(t, got[0].Control, test.ShouldEqual, input.ButtonSouth)
+
+	_, err = i.Controls(context.Background(), nil)
+	test.That(t, err, test.ShouldBeError, ErrDisconnected)
+	_, err = i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeError, ErrDisconnected)
+	_, err = i.EventsChanged(context.Background(), nil)
+	test.That(t, err, test.ShouldBeError, ErrDisconnected)
+
+	// events injected while disconnected are dropped
+	press := input.Event{Time: time.Now(), Event: input.ButtonPress, Control: input.ButtonSouth, Value: 1}
+	test.That(t, i.TriggerEvent(context.Background(), press, nil), test.ShouldBeNil)
+	test.That(t, got, test.ShouldHaveLength, 1)
+
+	// disconnecting again changes nothing
+	i.SetConnected(context.Background(), false)
+	test.That(t, got, test.ShouldHaveLength, 1)
+
+	i.SetConnected(context.Background(), true)
+	test.That(t, got, test.ShouldHaveLength, 2)
+	test.That(t, got[1].Event, test.ShouldEqual, input.Connect)
+	test.That(t, got[1].Control, test.ShouldEqual, input.ButtonSouth)
+
+	controls, err := i.Controls(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, controls, test.ShouldResemble, []input.Control{input.ButtonSouth, input.AbsoluteX})
+	events, err := i.Events(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, events, test.ShouldNotContainKey, input.ButtonSouth)
+}
+
+func TestValidate(t *testing.T) {
+	for _, deadband := range []float64{0, 0.2} {
+		_, err := (&Config{Deadband: deadband}).Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+	}
+	for _, deadband := range []float64{-0.1, 1} {
+		_, err := (&Config{Deadband: deadband}).Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "deadband must be in [0, 1)")
+	}
+	_, err := (&Config{Controls: []input.Control{input.ButtonSouth, input.AbsoluteHat0X}}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = (&Config{Controls: []input.Control{input.ButtonSouth, "ButtonSouht"}}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `unknown input control "ButtonSouht", must be one of: AbsoluteX,`)
+	_, err = (&Config{EventMaxAgeSec: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "event_max_age_sec cannot be negative")
+	_, err = (&Config{HistorySize: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "history_size cannot be negative")
 }
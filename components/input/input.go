@@ -3,8 +3,10 @@ package input
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/inputcontroller/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -122,6 +124,34 @@ const (
 	AbsolutePedalClutch      Control = "AbsolutePedalClutch"
 )
 
+// allControls lists every known Control, in the order they are declared.
+var allControls = []Control{
+	AbsoluteX, AbsoluteY, AbsoluteZ, AbsoluteRX, AbsoluteRY, AbsoluteRZ, AbsoluteHat0X, AbsoluteHat0Y,
+	ButtonSouth, ButtonEast, ButtonWest, ButtonNorth, ButtonLT, ButtonRT, ButtonLT2, ButtonRT2,
+	ButtonLThumb, ButtonRThumb, ButtonSelect, ButtonStart, ButtonMenu, ButtonRecord, ButtonEStop,
+	AbsolutePedalAccelerator, AbsolutePedalBrake, AbsolutePedalClutch,
+}
+
+// AllControls returns every known Control, such as for a UI to list them.
+func AllControls() []Control {
+	return append([]Control(nil), allControls...)
+}
+
+// ParseControl returns the known Control named s. Names are case sensitive, so that a control in a
+// config is spelled the same way it is reported.
+func ParseControl(s string) (Control, error) {
+	for _, control := range allControls {
+		if string(control) == s {
+			return control, nil
+		}
+	}
+	names := make([]string, 0, len(allControls))
+	for _, control := range allControls {
+		names = append(names, string(control))
+	}
+	return "", errors.Errorf("unknown input control %q, must be one of: %s", s, strings.Join(names, ", "))
+}
+
 // Event is passed to the registered ControlFunction or returned by State().
 type Event struct {
 	Time    time.Time
@@ -130,6 +160,25 @@ type Event struct {
 	Value   float64 // 0 or 1 for buttons, -1.0 to +1.0 for axes
 }
 
+// EventsChanged returns the events in cur whose control has a different event type or value than in prev,
+// including controls that are not in prev at all. Times are not compared, so an event that is re-reported
+// with the same state does not count as a change. A control that is in prev but not in cur is returned
+// as a Disconnect event with the time of its last event.
+func EventsChanged(prev, cur map[Control]Event) map[Control]Event {
+	changed := make(map[Control]Event)
+	for control, event := range cur {
+		if last, ok := prev[control]; !ok || last.Event != event.Event || last.Value != event.Value {
+			changed[control] = event
+		}
+	}
+	for control, last := range prev {
+		if _, ok := cur[control]; !ok {
+			changed[control] = Event{Time: last.Time, Event: Disconnect, Control: control}
+		}
+	}
+	return changed
+}
+
 // Triggerable is used by the WebGamepad interface to inject events.
 type Triggerable interface {
 	// TriggerEvent allows directly sending an Event (such as a button press) from external code
@@ -50,3 +50,56 @@ func TestCreateStatus(t *testing.T) {
 		test.That(t, err, test.ShouldBeError, errFail)
 	})
 }
+
+func TestEventsChanged(t *testing.T) {
+	before := time.Now()
+	after := before.Add(time.Second)
+	prev := map[input.Control]input.Event{
+		input.AbsoluteX:   {Time: before, Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: 0.5},
+		input.AbsoluteY:   {Time: before, Event: input.PositionChangeAbs, Control: input.AbsoluteY, Value: -0.5},
+		input.ButtonSouth: {Time: before, Event: input.ButtonPress, Control: input.ButtonSouth, Value: 1},
+		input.ButtonEast:  {Time: before, Event: input.ButtonPress, Control: input.ButtonEast, Value: 1},
+	}
+	cur := map[input.Control]input.Event{
+		// unchanged apart from the time
+		input.AbsoluteX: {Time: after, Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: 0.5},
+		// value changed
+		input.AbsoluteY: {Time: after, Event: input.PositionChangeAbs, Control: input.AbsoluteY, Value: 0.25},
+		// event type changed
+		input.ButtonSouth: {Time: after, Event: input.ButtonRelease, Control: input.ButtonSouth, Value: 0},
+		// added
+		input.ButtonNorth: {Time: after, Event: input.ButtonPress, Control: input.ButtonNorth, Value: 1},
+		// ButtonEast removed
+	}
+
+	test.That(t, input.EventsChanged(prev, cur), test.ShouldResemble, map[input.Control]input.Event{
+		input.AbsoluteY:   cur[input.AbsoluteY],
+		input.ButtonSouth: cur[input.ButtonSouth],
+		input.ButtonNorth: cur[input.ButtonNorth],
+		input.ButtonEast:  {Time: before, Event: input.Disconnect, Control: input.ButtonEast},
+	})
+
+	test.That(t, input.EventsChanged(nil, cur), test.ShouldResemble, cur)
+	test.That(t, input.EventsChanged(cur, cur), test.ShouldBeEmpty)
+	test.That(t, input.EventsChanged(nil, nil), test.ShouldBeEmpty)
+}
+
+func TestParseControl(t *testing.T) {
+	for _, control := range input.AllControls() {
+		parsed, err := input.ParseControl(string(control))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, parsed, test.ShouldEqual, control)
+	}
+	test.That(t, input.AllControls(), test.ShouldContain, input.AbsolutePedalClutch)
+
+	for _, name := range []string{"", "buttonsouth", "ButtonSouht"} {
+		_, err := input.ParseControl(name)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be one of: AbsoluteX, AbsoluteY,")
+	}
+
+	// callers can't change the known controls through what AllControls returns.
+	controls := input.AllControls()
+	controls[0] = "Phantom"
+	test.That(t, input.AllControls()[0], test.ShouldEqual, input.AbsoluteX)
+}
@@ -0,0 +1,5 @@
+// export_test.go adds functionality to the movementsensor package that we only want to use and expose during testing.
+package movementsensor
+
+// AverageHeadingOverWithClock is AverageHeadingOver with the clock it measures the duration by.
+var AverageHeadingOverWithClock = averageHeadingOver
@@ -0,0 +1,246 @@
+package movementsensor
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/utils"
+)
+
+// headingSampleInterval is how often the heading helpers sample the compass heading.
+const headingSampleInterval = 20 * time.Millisecond
+
+// StabilizedHeading samples the compass heading of the given movement sensor until the last window
+// readings are all within tolerance degrees of their circular mean, and then returns that mean. It
+// returns an error if the heading has not settled before the timeout passes or the context is done.
+func StabilizedHeading(
+	ctx context.Context,
+	dev MovementSensor,
+	tolerance float64,
+	window int,
+	timeout time.Duration,
+) (float64, error) {
+	if window < 1 {
+		return 0, errors.Errorf("window must be at least 1, got %d", window)
+	}
+	if tolerance < 0 {
+		return 0, errors.Errorf("tolerance must not be negative, got %v", tolerance)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	readings := make([]float64, 0, window)
+	for {
+		heading, err := dev.CompassHeading(timeoutCtx, nil)
+		if err != nil {
+			return 0, err
+		}
+		if len(readings) == window {
+			readings = readings[1:]
+		}
+		readings = append(readings, heading)
+
+		if len(readings) == window {
+			if mean, ok := headingWithinTolerance(readings, tolerance); ok {
+				return mean, nil
+			}
+		}
+
+		if !goutils.SelectContextOrWait(timeoutCtx, headingSampleInterval) {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return 0, errors.Errorf("compass heading did not stabilize within %s", timeout)
+		}
+	}
+}
+
+// headingWithinTolerance returns the circular mean of the given headings and whether all of them
+// are within tolerance degrees of it.
+func headingWithinTolerance(headings []float64, tolerance float64) (float64, bool) {
+	mean := utils.MeanAngleDeg(headings...)
+	if math.IsNaN(mean) {
+		return 0, false
+	}
+	for _, heading := range headings {
+		if utils.AngleDiffDeg(heading, mean) > tolerance {
+			return 0, false
+		}
+	}
+	return mean, true
+}
+
+// HeadingWhileStationary samples the compass heading of the given movement sensor samples times and
+// returns the median reading. If any two consecutive readings differ by more than maxDrift degrees, the
+// robot is most likely turning, so a median would be meaningless and an error asking the caller to stop
+// the robot first is returned instead.
+func HeadingWhileStationary(ctx context.Context, dev MovementSensor, maxDrift float64, samples int) (float64, error) {
+	if samples < 1 {
+		return 0, errors.Errorf("samples must be at least 1, got %d", samples)
+	}
+	if maxDrift < 0 {
+		return 0, errors.Errorf("maxDrift must not be negative, got %v", maxDrift)
+	}
+
+	var prev float64
+	headings := make([]float64, 0, samples)
+	for i := 0; i < samples; i++ {
+		if i > 0 && !goutils.SelectContextOrWait(ctx, headingSampleInterval) {
+			return 0, ctx.Err()
+		}
+		heading, err := dev.CompassHeading(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		if i > 0 {
+			if drift := math.Abs(utils.SignedAngleDiffDeg(prev, heading)); drift > maxDrift {
+				return 0, errors.Errorf(
+					"compass heading drifted %.1f degrees between readings, more than the allowed %v; "+
+						"the robot appears to be turning, stop it before reading its heading",
+					drift, maxDrift)
+			}
+		}
+		prev = heading
+		headings = append(headings, heading)
+	}
+	return medianHeadingDeg(headings), nil
+}
+
+// MedianHeadingResult is the outcome of MedianHeading.
+type MedianHeadingResult struct {
+	// Heading is the median of the readings that were taken.
+	Heading float64
+	// Samples is how many readings the median is of.
+	Samples int
+	// TimedOut is true if the context's deadline passed before every sample was taken, in which case
+	// Heading is the median of fewer readings than were asked for.
+	TimedOut bool
+}
+
+// MedianHeading samples the compass heading of the given movement sensor samples times and returns the
+// median reading. Readings are taken one after another, so sampling takes at least samples times the
+// compass's latency; a deadline on ctx bounds the total time. If the deadline passes partway through
+// and at least minSamples readings were taken, the median of those is returned with TimedOut set.
+// Otherwise the deadline is an error, as is the context being cancelled.
+func MedianHeading(ctx context.Context, dev MovementSensor, samples, minSamples int) (MedianHeadingResult, error) {
+	if minSamples < 1 || minSamples > samples {
+		return MedianHeadingResult{}, errors.Errorf("minSamples must be between 1 and samples (%d), got %d", samples, minSamples)
+	}
+
+	headings := make([]float64, 0, samples)
+	var timedOut bool
+	for i := 0; i < samples; i++ {
+		if i > 0 && !goutils.SelectContextOrWait(ctx, headingSampleInterval) {
+			timedOut = true
+			break
+		}
+		heading, err := dev.CompassHeading(ctx, nil)
+		if err != nil {
+			if ctx.Err() == nil {
+				return MedianHeadingResult{}, err
+			}
+			timedOut = true
+			break
+		}
+		headings = append(headings, heading)
+	}
+	if timedOut {
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return MedianHeadingResult{}, ctx.Err()
+		}
+		if len(headings) < minSamples {
+			return MedianHeadingResult{}, errors.Wrapf(ctx.Err(),
+				"only %d of %d compass readings were taken before the deadline, at least %d are needed",
+				len(headings), samples, minSamples)
+		}
+	}
+	return MedianHeadingResult{Heading: medianHeadingDeg(headings), Samples: len(headings), TimedOut: timedOut}, nil
+}
+
+// AverageHeadingOver reads the compass heading of the given movement sensor as fast as it answers for
+// duration and returns the circular mean of the readings, so that averaging takes a known amount of wall
+// clock time however fast the compass is. A read that starts before duration has passed is included
+// even if it finishes after. It returns an error if fewer than minSamples readings were taken, if they
+// point in no overall direction, or if the context is done first.
+func AverageHeadingOver(ctx context.Context, dev MovementSensor, duration time.Duration, minSamples int) (float64, error) {
+	return averageHeadingOver(ctx, clock.New(), dev, duration, minSamples)
+}
+
+func averageHeadingOver(
+	ctx context.Context,
+	clk clock.Clock,
+	dev MovementSensor,
+	duration time.Duration,
+	minSamples int,
+) (float64, error) {
+	if duration <= 0 {
+		return 0, errors.Errorf("duration must be positive, got %s", duration)
+	}
+	if minSamples < 1 {
+		return 0, errors.Errorf("minSamples must be at least 1, got %d", minSamples)
+	}
+
+	var headings []float64
+	for start := clk.Now(); clk.Since(start) < duration; {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		heading, err := dev.CompassHeading(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		headings = append(headings, heading)
+	}
+	if len(headings) < minSamples {
+		return 0, errors.Errorf("only %d compass readings were taken in %s, at least %d are needed", len(headings), duration, minSamples)
+	}
+	mean := utils.MeanAngleDeg(headings...)
+	if math.IsNaN(mean) {
+		return 0, errors.Errorf("the %d compass readings taken in %s have no mean heading", len(headings), duration)
+	}
+	return mean, nil
+}
+
+// medianHeadingDeg returns the median of the given headings, which must not be empty.
+func medianHeadingDeg(headings []float64) float64 {
+	// offsets are relative to the first reading so that readings on either side of north sort correctly.
+	first := headings[0]
+	offsets := make([]float64, 0, len(headings))
+	for _, heading := range headings {
+		offsets = append(offsets, utils.SignedAngleDiffDeg(first, heading))
+	}
+	sort.Float64s(offsets)
+	median := offsets[len(offsets)/2]
+	if len(offsets)%2 == 0 {
+		median = (median + offsets[len(offsets)/2-1]) / 2
+	}
+	return math.Mod(first+median+360, 360)
+}
+
+// HeadingWithRetry reads the compass heading of the given movement sensor, retrying failed reads up
+// to attempts times in total and waiting backoff between them. Transient bus errors are common on
+// I2C compasses, so a single failed read is not worth failing a caller over. If every read fails, the
+// last error is returned.
+func HeadingWithRetry(ctx context.Context, dev MovementSensor, attempts int, backoff time.Duration) (float64, error) {
+	if attempts < 1 {
+		return 0, errors.Errorf("attempts must be at least 1, got %d", attempts)
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 && !goutils.SelectContextOrWait(ctx, backoff) {
+			return 0, ctx.Err()
+		}
+		var heading float64
+		heading, err = dev.CompassHeading(ctx, nil)
+		if err == nil {
+			return heading, nil
+		}
+	}
+	return 0, errors.Wrapf(err, "failed to read compass heading after %d attempts", attempts)
+}
@@ -0,0 +1,292 @@
+package movementsensor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestStabilizedHeading(t *testing.T) {
+	newCompass := func(headings ...float64) (*inject.MovementSensor, *int) {
+		calls := 0
+		ms := &inject.MovementSensor{}
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			heading := headings[len(headings)-1]
+			if calls < len(headings) {
+				heading = headings[calls]
+			}
+			calls++
+			return heading, nil
+		}
+		return ms, &calls
+	}
+
+	t.Run("returns once stable", func(t *testing.T) {
+		// noisy readings, then readings that settle around north.
+		ms, calls := newCompass(90, 200, 10, 300, 359, 1, 358, 2, 0, 180)
+		heading, err := movementsensor.StabilizedHeading(context.Background(), ms, 3, 4, time.Second)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading > 359 || heading < 1, test.ShouldBeTrue)
+		test.That(t, *calls, test.ShouldEqual, 8)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		ms, _ := newCompass(0, 90, 180, 270)
+		_, err := movementsensor.StabilizedHeading(context.Background(), ms, 3, 4, 100*time.Millisecond)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "did not stabilize")
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ms, _ := newCompass(0, 90)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := movementsensor.StabilizedHeading(ctx, ms, 3, 4, time.Second)
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+	})
+
+	t.Run("compass error", func(t *testing.T) {
+		errCompass := errors.New("no heading")
+		ms := &inject.MovementSensor{}
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			return 0, errCompass
+		}
+		_, err := movementsensor.StabilizedHeading(context.Background(), ms, 3, 4, time.Second)
+		test.That(t, err, test.ShouldBeError, errCompass)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		ms, _ := newCompass(0)
+		_, err := movementsensor.StabilizedHeading(context.Background(), ms, 3, 0, time.Second)
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = movementsensor.StabilizedHeading(context.Background(), ms, -1, 4, time.Second)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestHeadingWhileStationary(t *testing.T) {
+	newCompass := func(headings ...float64) *inject.MovementSensor {
+		calls := 0
+		ms := &inject.MovementSensor{}
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			heading := headings[calls%len(headings)]
+			calls++
+			return heading, nil
+		}
+		return ms
+	}
+
+	t.Run("stable", func(t *testing.T) {
+		heading, err := movementsensor.HeadingWhileStationary(context.Background(), newCompass(90, 91, 89.5, 90.5, 120), 2, 4)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 90.25)
+	})
+
+	t.Run("stable around north", func(t *testing.T) {
+		heading, err := movementsensor.HeadingWhileStationary(context.Background(), newCompass(359, 1, 358, 0.5, 359.5), 3, 5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 359.5)
+	})
+
+	t.Run("turning", func(t *testing.T) {
+		// a robot turning at a steady rate
+		ms := newCompass(10, 14, 18, 22, 26, 30)
+		_, err := movementsensor.HeadingWhileStationary(context.Background(), ms, 2, 6)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "drifted 4.0 degrees")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "stop it before reading its heading")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := movementsensor.HeadingWhileStationary(context.Background(), newCompass(0), 2, 0)
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = movementsensor.HeadingWhileStationary(context.Background(), newCompass(0), -1, 3)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := movementsensor.HeadingWhileStationary(ctx, newCompass(0), 2, 3)
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+	})
+}
+
+func TestHeadingWithRetry(t *testing.T) {
+	newFlakyCompass := func(failures int) (*inject.MovementSensor, *int) {
+		calls := 0
+		ms := &inject.MovementSensor{}
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			calls++
+			if calls <= failures {
+				return 0, errors.Errorf("i2c read failed on attempt %d", calls)
+			}
+			return 42, nil
+		}
+		return ms, &calls
+	}
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		ms, calls := newFlakyCompass(2)
+		heading, err := movementsensor.HeadingWithRetry(context.Background(), ms, 3, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldEqual, 42)
+		test.That(t, *calls, test.ShouldEqual, 3)
+	})
+
+	t.Run("returns the last error", func(t *testing.T) {
+		ms, calls := newFlakyCompass(5)
+		_, err := movementsensor.HeadingWithRetry(context.Background(), ms, 3, time.Millisecond)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "i2c read failed on attempt 3")
+		test.That(t, *calls, test.ShouldEqual, 3)
+	})
+
+	t.Run("context canceled during backoff", func(t *testing.T) {
+		ms, calls := newFlakyCompass(5)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := movementsensor.HeadingWithRetry(ctx, ms, 3, time.Minute)
+		test.That(t, errors.Is(err, context.DeadlineExceeded), test.ShouldBeTrue)
+		test.That(t, *calls, test.ShouldEqual, 1)
+	})
+
+	t.Run("invalid attempts", func(t *testing.T) {
+		ms, calls := newFlakyCompass(0)
+		_, err := movementsensor.HeadingWithRetry(context.Background(), ms, 0, time.Millisecond)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, *calls, test.ShouldEqual, 0)
+	})
+}
+
+func TestMedianHeading(t *testing.T) {
+	// newSlowCompass returns the given headings promptly, and then is slow to answer at all, so further
+	// reads only end when the context does.
+	newSlowCompass := func(headings ...float64) *inject.MovementSensor {
+		calls := 0
+		ms := &inject.MovementSensor{}
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			calls++
+			if calls <= len(headings) {
+				return headings[calls-1], nil
+			}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return ms
+	}
+
+	t.Run("all samples", func(t *testing.T) {
+		result, err := movementsensor.MedianHeading(context.Background(), newSlowCompass(350, 10, 2), 3, 3)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result, test.ShouldResemble, movementsensor.MedianHeadingResult{Heading: 2, Samples: 3})
+	})
+
+	t.Run("deadline returns a partial median", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		result, err := movementsensor.MedianHeading(ctx, newSlowCompass(90, 92, 91), 10, 3)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result, test.ShouldResemble, movementsensor.MedianHeadingResult{Heading: 91, Samples: 3, TimedOut: true})
+	})
+
+	t.Run("deadline before the minimum", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := movementsensor.MedianHeading(ctx, newSlowCompass(90, 92), 10, 3)
+		test.That(t, errors.Is(err, context.DeadlineExceeded), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "only 2 of 10 compass readings were taken before the deadline")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := movementsensor.MedianHeading(ctx, newSlowCompass(), 10, 1)
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+	})
+
+	t.Run("invalid minimum", func(t *testing.T) {
+		_, err := movementsensor.MedianHeading(context.Background(), newSlowCompass(), 3, 4)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "minSamples must be between 1 and samples")
+	})
+}
+
+func TestAverageHeadingOver(t *testing.T) {
+	// newFixedRateCompass returns a compass that takes readDuration of mockClock's time per reading and
+	// reads the given headings in turn.
+	newFixedRateCompass := func(mockClock *clock.Mock, readDuration time.Duration, headings ...float64) (*inject.MovementSensor, *int) {
+		calls := 0
+		ms := &inject.MovementSensor{}
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			mockClock.Add(readDuration)
+			heading := headings[calls%len(headings)]
+			calls++
+			return heading, nil
+		}
+		return ms, &calls
+	}
+
+	t.Run("samples for the duration", func(t *testing.T) {
+		mockClock := clock.NewMock()
+		ms, calls := newFixedRateCompass(mockClock, 10*time.Millisecond, 350, 10)
+		heading, err := movementsensor.AverageHeadingOverWithClock(context.Background(), mockClock, ms, 100*time.Millisecond, 5)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldEqual, 10)
+		test.That(t, heading, test.ShouldAlmostEqual, 0, 1e-9)
+	})
+
+	t.Run("a read that starts before the duration is over counts", func(t *testing.T) {
+		mockClock := clock.NewMock()
+		ms, calls := newFixedRateCompass(mockClock, 30*time.Millisecond, 80, 100)
+		heading, err := movementsensor.AverageHeadingOverWithClock(context.Background(), mockClock, ms, 100*time.Millisecond, 4)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, *calls, test.ShouldEqual, 4)
+		test.That(t, heading, test.ShouldAlmostEqual, 90, 1e-9)
+	})
+
+	t.Run("too few samples", func(t *testing.T) {
+		mockClock := clock.NewMock()
+		ms, _ := newFixedRateCompass(mockClock, 50*time.Millisecond, 90)
+		_, err := movementsensor.AverageHeadingOverWithClock(context.Background(), mockClock, ms, 100*time.Millisecond, 3)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "only 2 compass readings were taken in 100ms, at least 3 are needed")
+	})
+
+	t.Run("opposite readings have no mean", func(t *testing.T) {
+		mockClock := clock.NewMock()
+		ms, _ := newFixedRateCompass(mockClock, 10*time.Millisecond, 0, 180)
+		_, err := movementsensor.AverageHeadingOverWithClock(context.Background(), mockClock, ms, 100*time.Millisecond, 1)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "have no mean heading")
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mockClock := clock.NewMock()
+		ms, calls := newFixedRateCompass(mockClock, 10*time.Millisecond, 90)
+		prev := ms.CompassHeadingFunc
+		ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+			cancel()
+			return prev(ctx, extra)
+		}
+		_, err := movementsensor.AverageHeadingOverWithClock(ctx, mockClock, ms, 100*time.Millisecond, 1)
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+		test.That(t, *calls, test.ShouldEqual, 1)
+	})
+
+	t.Run("real clock", func(t *testing.T) {
+		ms, calls := newFixedRateCompass(clock.NewMock(), 0, 45)
+		heading, err := movementsensor.AverageHeadingOver(context.Background(), ms, 10*time.Millisecond, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 45, 1e-9)
+		test.That(t, *calls, test.ShouldBeGreaterThan, 0)
+	})
+}
@@ -0,0 +1,51 @@
+package movementsensor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/utils"
+)
+
+// smoothedCompass is a MovementSensor whose compass heading is an exponential moving average of
+// the headings of the movement sensor it wraps. All other methods are passed through.
+type smoothedCompass struct {
+	MovementSensor
+	alpha float64
+
+	mu          sync.Mutex
+	heading     float64
+	initialized bool
+}
+
+// NewSmoothedCompass returns a MovementSensor that smooths successive CompassHeading readings of dev
+// with an exponential moving average. alpha is the weight given to each new reading and must be in
+// (0, 1]; an alpha of 1 disables smoothing. Averaging is done circularly so that readings either side
+// of north do not average to south. The first reading initializes the filter.
+func NewSmoothedCompass(dev MovementSensor, alpha float64) (MovementSensor, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, errors.Errorf("alpha must be in (0, 1], got %v", alpha)
+	}
+	return &smoothedCompass{MovementSensor: dev, alpha: alpha}, nil
+}
+
+func (sc *smoothedCompass) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	reading, err := sc.MovementSensor.CompassHeading(ctx, extra)
+	if err != nil {
+		return 0, err
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if !sc.initialized {
+		sc.heading = utils.ModAngDeg(reading)
+		sc.initialized = true
+		return sc.heading, nil
+	}
+	// move along the shorter way around the circle towards the new reading.
+	diff := utils.SignedAngleDiffDeg(sc.heading, reading)
+	sc.heading = utils.ModAngDeg(sc.heading + sc.alpha*diff)
+	return sc.heading, nil
+}
@@ -0,0 +1,97 @@
+package movementsensor_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+func TestSmoothedCompass(t *testing.T) {
+	var reading float64
+	ms := &inject.MovementSensor{}
+	ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return reading, nil
+	}
+
+	_, err := movementsensor.NewSmoothedCompass(ms, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = movementsensor.NewSmoothedCompass(ms, 1.5)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("step change is tracked with lag", func(t *testing.T) {
+		smoothed, err := movementsensor.NewSmoothedCompass(ms, 0.5)
+		test.That(t, err, test.ShouldBeNil)
+
+		reading = 10
+		heading, err := smoothed.CompassHeading(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 10)
+
+		reading = 50
+		for _, expected := range []float64{30, 40, 45, 47.5} {
+			heading, err = smoothed.CompassHeading(context.Background(), nil)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, heading, test.ShouldAlmostEqual, expected)
+		}
+	})
+
+	t.Run("smooths across north", func(t *testing.T) {
+		smoothed, err := movementsensor.NewSmoothedCompass(ms, 0.25)
+		test.That(t, err, test.ShouldBeNil)
+
+		reading = 350
+		_, err = smoothed.CompassHeading(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		reading = 30
+		heading, err := smoothed.CompassHeading(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 0)
+	})
+
+	t.Run("alpha of 1 does not smooth", func(t *testing.T) {
+		smoothed, err := movementsensor.NewSmoothedCompass(ms, 1)
+		test.That(t, err, test.ShouldBeNil)
+
+		reading = 90
+		_, err = smoothed.CompassHeading(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		reading = 270
+		heading, err := smoothed.CompassHeading(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 270)
+	})
+}
+
+func TestNoisyFixedCompass(t *testing.T) {
+	// a true heading near north, so the noise wraps around 0
+	const trueHeading = 2.
+	samples := func(ms movementsensor.MovementSensor, n int) []float64 {
+		headings := make([]float64, 0, n)
+		for i := 0; i < n; i++ {
+			heading, err := ms.CompassHeading(context.Background(), nil)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, heading, test.ShouldBeBetweenOrEqual, 0, 360)
+			headings = append(headings, heading)
+		}
+		return headings
+	}
+
+	noisy := samples(inject.NewNoisyFixedCompass("compass", trueHeading, 5, 42), 2000)
+	test.That(t, utils.AngleDiffDeg(utils.MeanAngleDeg(noisy...), trueHeading), test.ShouldBeLessThan, 0.5)
+	test.That(t, samples(inject.NewNoisyFixedCompass("compass", trueHeading, 5, 42), 2000), test.ShouldResemble, noisy)
+	test.That(t, samples(inject.NewNoisyFixedCompass("compass", trueHeading, 5, 7), 2000), test.ShouldNotResemble, noisy)
+
+	// smoothing the noisy compass keeps it close to the true heading
+	smoothed, err := movementsensor.NewSmoothedCompass(inject.NewNoisyFixedCompass("compass", trueHeading, 5, 42), 0.1)
+	test.That(t, err, test.ShouldBeNil)
+	for _, heading := range samples(smoothed, 2000)[100:] {
+		test.That(t, utils.AngleDiffDeg(heading, trueHeading), test.ShouldBeLessThan, 5)
+	}
+}
@@ -0,0 +1,91 @@
+package movementsensor
+
+import (
+	"context"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/utils"
+)
+
+// TiltCompensatedHeading returns the compass heading in degrees, in [0, 360), of a robot whose
+// magnetometer measures the magnetic field raw and whose accelerometer measures accel, both along the
+// same axes: X to the robot's right, Y forward and Z up. A heading from the horizontal part of raw alone
+// is wrong on a slope, because the field also points steeply down into the ground and tilting the robot
+// leaks that into X and Y. accel, which at rest points straight up away from gravity, gives the robot's
+// roll and pitch, and the heading is taken from raw once those are undone.
+//
+// The result is NaN if accel is zero or points along Y, where the robot's heading is undefined, or if
+// raw has no horizontal part.
+func TiltCompensatedHeading(raw, accel [3]float64) float64 {
+	mag := r3.Vector{X: raw[0], Y: raw[1], Z: raw[2]}
+	up := r3.Vector{X: accel[0], Y: accel[1], Z: accel[2]}
+	if up.Norm() == 0 {
+		return math.NaN()
+	}
+	up = up.Normalize()
+
+	// the robot's forward and right directions as they would be on level ground, in the robot's axes.
+	forward := r3.Vector{Y: 1}
+	forward = forward.Sub(up.Mul(forward.Dot(up)))
+	if forward.Norm() < 1e-9 {
+		return math.NaN()
+	}
+	forward = forward.Normalize()
+	right := forward.Cross(up)
+
+	north, east := mag.Dot(forward), mag.Dot(right)
+	if north == 0 && east == 0 {
+		return math.NaN()
+	}
+	// north lies to the left of forward when the robot faces east of it.
+	return utils.ModAngDeg(utils.RadToDeg(math.Atan2(-east, north)))
+}
+
+// RawCompass is a magnetometer that measures the magnetic field along its axes, in any units.
+type RawCompass interface {
+	MagneticField(ctx context.Context, extra map[string]interface{}) (r3.Vector, error)
+}
+
+// accelerationReading is the reading TiltCompensatedCompass takes the accelerometer's measurement from,
+// as movement sensors report it.
+const accelerationReading = "linear_acceleration"
+
+// TiltCompensatedCompass is a compass made of a RawCompass and an accelerometer mounted on the same
+// axes, which reports headings that stay correct on a slope. See TiltCompensatedHeading for the axes.
+type TiltCompensatedCompass struct {
+	compass RawCompass
+	accel   sensor.Sensor
+}
+
+// NewTiltCompensatedCompass returns a compass that corrects the headings of compass using accel, whose
+// "linear_acceleration" reading must be an r3.Vector that includes gravity, as a movement sensor with
+// linear acceleration reports it.
+func NewTiltCompensatedCompass(compass RawCompass, accel sensor.Sensor) *TiltCompensatedCompass {
+	return &TiltCompensatedCompass{compass: compass, accel: accel}
+}
+
+// CompassHeading returns the tilt compensated heading in degrees, in [0, 360).
+func (c *TiltCompensatedCompass) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	field, err := c.compass.MagneticField(ctx, extra)
+	if err != nil {
+		return 0, err
+	}
+	readings, err := c.accel.Readings(ctx, extra)
+	if err != nil {
+		return 0, err
+	}
+	accel, ok := readings[accelerationReading].(r3.Vector)
+	if !ok {
+		return 0, errors.Errorf("expected the accelerometer's %q reading to be an r3.Vector, got %T",
+			accelerationReading, readings[accelerationReading])
+	}
+	heading := TiltCompensatedHeading([3]float64{field.X, field.Y, field.Z}, [3]float64{accel.X, accel.Y, accel.Z})
+	if math.IsNaN(heading) {
+		return 0, errors.Errorf("cannot find a heading from magnetic field %v and acceleration %v", field, accel)
+	}
+	return heading, nil
+}
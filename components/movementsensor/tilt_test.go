@@ -0,0 +1,99 @@
+package movementsensor_test
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+// rotate rotates v about the unit vector axis by angleDeg, counterclockwise looking down axis.
+func rotate(v, axis r3.Vector, angleDeg float64) r3.Vector {
+	sin, cos := math.Sincos(utils.DegToRad(angleDeg))
+	return v.Mul(cos).Add(axis.Cross(v).Mul(sin)).Add(axis.Mul(axis.Dot(v) * (1 - cos)))
+}
+
+// tiltedReadings returns what a magnetometer and accelerometer measure along the robot's axes when it
+// faces headingDeg, pitched nose up by pitchDeg and rolled right side down by rollDeg. The field is
+// made up but like Earth's in the northern hemisphere, pointing north and steeply down.
+func tiltedReadings(headingDeg, pitchDeg, rollDeg float64) ([3]float64, [3]float64) {
+	field := r3.Vector{Y: 20, Z: -45}
+	gravity := r3.Vector{Z: 9.81}
+
+	// the robot's axes in east, north, up coordinates, starting level and facing north.
+	right, forward, up := r3.Vector{X: 1}, r3.Vector{Y: 1}, r3.Vector{Z: 1}
+	right, forward = rotate(right, up, -headingDeg), rotate(forward, up, -headingDeg)
+	forward, up = rotate(forward, right, pitchDeg), rotate(up, right, pitchDeg)
+	right, up = rotate(right, forward, rollDeg), rotate(up, forward, rollDeg)
+
+	inRobotAxes := func(v r3.Vector) [3]float64 {
+		return [3]float64{v.Dot(right), v.Dot(forward), v.Dot(up)}
+	}
+	return inRobotAxes(field), inRobotAxes(gravity)
+}
+
+func TestTiltCompensatedHeading(t *testing.T) {
+	for _, heading := range []float64{0, 45, 90, 135, 200, 315} {
+		for _, tilt := range [][2]float64{{0, 0}, {20, 0}, {0, -25}, {15, 30}, {-30, -10}} {
+			pitch, roll := tilt[0], tilt[1]
+			t.Run(fmt.Sprintf("heading %v pitch %v roll %v", heading, pitch, roll), func(t *testing.T) {
+				raw, accel := tiltedReadings(heading, pitch, roll)
+				compensated := movementsensor.TiltCompensatedHeading(raw, accel)
+				diff := math.Mod(compensated-heading+540, 360) - 180
+				test.That(t, diff, test.ShouldAlmostEqual, 0, 1e-6)
+			})
+		}
+	}
+
+	t.Run("uncompensated heading is wrong on a slope", func(t *testing.T) {
+		raw, _ := tiltedReadings(45, 20, 0)
+		naive := utils.ModAngDeg(utils.RadToDeg(math.Atan2(-raw[0], raw[1])))
+		test.That(t, math.Abs(naive-45), test.ShouldBeGreaterThan, 10)
+	})
+
+	t.Run("undefined heading", func(t *testing.T) {
+		raw, _ := tiltedReadings(0, 0, 0)
+		test.That(t, math.IsNaN(movementsensor.TiltCompensatedHeading(raw, [3]float64{})), test.ShouldBeTrue)
+		// pointing straight up
+		test.That(t, math.IsNaN(movementsensor.TiltCompensatedHeading(raw, [3]float64{0, 1, 0})), test.ShouldBeTrue)
+	})
+}
+
+type injectRawCompass struct {
+	field r3.Vector
+}
+
+func (c *injectRawCompass) MagneticField(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	return c.field, nil
+}
+
+func TestTiltCompensatedCompass(t *testing.T) {
+	raw, accel := tiltedReadings(120, 10, -20)
+	compass := &injectRawCompass{field: r3.Vector{X: raw[0], Y: raw[1], Z: raw[2]}}
+	accelReading := interface{}(r3.Vector{X: accel[0], Y: accel[1], Z: accel[2]})
+	accelerometer := &inject.Sensor{}
+	accelerometer.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"linear_acceleration": accelReading}, nil
+	}
+
+	tiltCompass := movementsensor.NewTiltCompensatedCompass(compass, accelerometer)
+	heading, err := tiltCompass.CompassHeading(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldAlmostEqual, 120, 1e-6)
+
+	accelReading = "not a vector"
+	_, err = tiltCompass.CompassHeading(context.Background(), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "linear_acceleration")
+
+	accelReading = r3.Vector{}
+	_, err = tiltCompass.CompassHeading(context.Background(), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
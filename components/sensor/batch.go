@@ -0,0 +1,101 @@
+package sensor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	pb "go.viam.com/api/component/sensor/v1"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/protoutils"
+)
+
+// The sensor API has no dedicated RPC for reading many sensors at once, so the client and server
+// exchange batches over DoCommand using a reserved command that the server handles itself.
+const (
+	batchCommandKey      = "command"
+	readingsBatchCommand = "rdk:sensor:readings_batch"
+	batchNamesKey        = "names"
+	batchExtraKey        = "extra"
+	batchReadingsKey     = "readings"
+	batchErrorsKey       = "errors"
+)
+
+// BatchReadings is the result of reading a single sensor as part of ReadingsBatch. Exactly one of
+// Readings and Err is set.
+type BatchReadings struct {
+	Readings map[string]interface{}
+	Err      error
+}
+
+// ReadingsBatch gets the readings of all of the named sensors served over conn in a single round trip.
+// A sensor that cannot be found or fails to read has its error reported in its own entry rather than
+// failing the whole batch.
+func ReadingsBatch(
+	ctx context.Context,
+	conn rpc.ClientConn,
+	names []string,
+	extra map[string]interface{},
+) (map[string]BatchReadings, error) {
+	namesList := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		namesList = append(namesList, name)
+	}
+	cmd, err := structpb.NewStruct(map[string]interface{}{
+		batchCommandKey: readingsBatchCommand,
+		batchNamesKey:   namesList,
+		batchExtraKey:   extra,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := pb.NewSensorServiceClient(conn).DoCommand(ctx, &commonpb.DoCommandRequest{Command: cmd})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]BatchReadings, len(names))
+	for name, readings := range resp.GetResult().GetFields()[batchReadingsKey].GetStructValue().GetFields() {
+		goReadings, err := protoutils.ReadingProtoToGo(readings.GetStructValue().GetFields())
+		if err != nil {
+			return nil, err
+		}
+		results[name] = BatchReadings{Readings: goReadings}
+	}
+	for name, errMsg := range resp.GetResult().GetFields()[batchErrorsKey].GetStructValue().GetFields() {
+		results[name] = BatchReadings{Err: errors.New(errMsg.GetStringValue())}
+	}
+	return results, nil
+}
+
+// readingsBatch handles the reserved readings batch command by reading each named sensor in the
+// collection, just like GetReadings does for a single sensor.
+func (s *serviceServer) readingsBatch(ctx context.Context, cmd map[string]interface{}) (*commonpb.DoCommandResponse, error) {
+	names, ok := cmd[batchNamesKey].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("readings batch command missing %q", batchNamesKey)
+	}
+	extra, _ := cmd[batchExtraKey].(map[string]interface{})
+
+	readingsByName := map[string]*structpb.Value{}
+	errorsByName := map[string]*structpb.Value{}
+	for _, n := range names {
+		name, ok := n.(string)
+		if !ok {
+			return nil, errors.Errorf("expected sensor name to be a string but got %T", n)
+		}
+		readings, err := s.readings(ctx, name, extra)
+		if err != nil {
+			errorsByName[name] = structpb.NewStringValue(err.Error())
+			continue
+		}
+		readingsByName[name] = structpb.NewStructValue(&structpb.Struct{Fields: readings})
+	}
+
+	return &commonpb.DoCommandResponse{Result: &structpb.Struct{Fields: map[string]*structpb.Value{
+		batchReadingsKey: structpb.NewStructValue(&structpb.Struct{Fields: readingsByName}),
+		batchErrorsKey:   structpb.NewStructValue(&structpb.Struct{Fields: errorsByName}),
+	}}}, nil
+}
@@ -0,0 +1,83 @@
+package sensor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// readingsCache memoizes the last successful readings of each sensor for a short time. Concurrent
+// requests for a sensor that is already being read wait for that read instead of starting another.
+type readingsCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cachedReadings
+}
+
+// cachedReadings is the result of a single read of a sensor. Its fields are set before done is closed.
+type cachedReadings struct {
+	done     chan struct{}
+	readAt   time.Time
+	readings map[string]*structpb.Value
+	err      error
+	// abandoned is set if the read failed because the request that started it ended.
+	abandoned bool
+}
+
+func newReadingsCache(ttl time.Duration) *readingsCache {
+	return &readingsCache{ttl: ttl, entries: make(map[string]*cachedReadings)}
+}
+
+// get returns the readings of the named sensor, calling read with ctx if there are no readings younger
+// than the cache's TTL and no read is in flight. Failed reads are shared with the requests that waited
+// on them, but are not cached. A read that failed because ctx, which belongs to the request that started
+// it, was cancelled or reached its deadline says nothing about the sensor, so the requests waiting on it
+// read again instead.
+func (c *readingsCache) get(
+	ctx context.Context,
+	name string,
+	read func() (map[string]*structpb.Value, error),
+) (map[string]*structpb.Value, error) {
+	for {
+		c.mu.Lock()
+		if entry, ok := c.entries[name]; ok {
+			select {
+			case <-entry.done:
+				if time.Since(entry.readAt) < c.ttl {
+					c.mu.Unlock()
+					return entry.readings, nil
+				}
+			default:
+				c.mu.Unlock()
+				select {
+				case <-entry.done:
+					if entry.abandoned {
+						continue
+					}
+					return entry.readings, entry.err
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+		}
+		entry := &cachedReadings{done: make(chan struct{})}
+		c.entries[name] = entry
+		c.mu.Unlock()
+
+		entry.readings, entry.err = read()
+		entry.readAt = time.Now()
+		if entry.err != nil {
+			entry.abandoned = ctx.Err() != nil
+			c.mu.Lock()
+			if c.entries[name] == entry {
+				delete(c.entries, name)
+			}
+			c.mu.Unlock()
+		}
+		close(entry.done)
+		return entry.readings, entry.err
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/sensor/v1"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
@@ -21,6 +24,7 @@ type client struct {
 	name   string
 	client pb.SensorServiceClient
 	logger golog.Logger
+	opts   clientOptions
 }
 
 // NewClientFromConn constructs a new Client from connection passed in.
@@ -31,13 +35,28 @@ func NewClientFromConn(
 	name resource.Name,
 	logger golog.Logger,
 ) (Sensor, error) {
-	c := pb.NewSensorServiceClient(conn)
-	return &client{
+	return NewClientFromConnWithOptions(ctx, conn, remoteName, name, logger)
+}
+
+// NewClientFromConnWithOptions constructs a new Client from connection passed in, configured by opts.
+func NewClientFromConnWithOptions(
+	ctx context.Context,
+	conn rpc.ClientConn,
+	remoteName string,
+	name resource.Name,
+	logger golog.Logger,
+	opts ...ClientOption,
+) (Sensor, error) {
+	c := &client{
 		Named:  name.PrependRemote(remoteName).AsNamed(),
 		name:   name.ShortName(),
-		client: c,
+		client: pb.NewSensorServiceClient(conn),
 		logger: logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt.apply(&c.opts)
+	}
+	return c, nil
 }
 
 func (c *client) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
@@ -45,11 +64,20 @@ func (c *client) Readings(ctx context.Context, extra map[string]interface{}) (ma
 	if err != nil {
 		return nil, err
 	}
-	resp, err := c.client.GetReadings(ctx, &pb.GetReadingsRequest{
+	callCtx := ctx
+	if _, ok := ctx.Deadline(); !ok && c.opts.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, c.opts.timeout)
+		defer cancel()
+	}
+	resp, err := c.client.GetReadings(callCtx, &pb.GetReadingsRequest{
 		Name:  c.name,
 		Extra: ext,
 	})
 	if err != nil {
+		if callCtx != ctx && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return nil, status.Errorf(codes.DeadlineExceeded, "sensor %q did not return readings within %v", c.name, c.opts.timeout)
+		}
 		return nil, err
 	}
 
@@ -0,0 +1,23 @@
+package sensor
+
+import "time"
+
+// clientOptions configures a sensor client.
+type clientOptions struct {
+	// timeout bounds each Readings call whose context has no deadline, see WithTimeout.
+	timeout time.Duration
+}
+
+// ClientOption configures a sensor client made by NewClientFromConnWithOptions.
+type ClientOption interface {
+	apply(*clientOptions)
+}
+
+// WithTimeout returns a ClientOption that makes each Readings call fail with a DeadlineExceeded error
+// if the sensor has not returned its readings within d, so that a sensor on a flaky bus cannot hang
+// its caller. A call whose context already has a deadline keeps that deadline instead.
+func WithTimeout(d time.Duration) ClientOption {
+	return newFuncOption(func(o *clientOptions) {
+		o.timeout = d
+	})
+}
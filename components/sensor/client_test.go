@@ -4,10 +4,13 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/components/sensor"
 	viamgrpc "go.viam.com/rdk/grpc"
@@ -20,6 +23,7 @@ import (
 var (
 	testSensorName    = "sensor1"
 	failSensorName    = "sensor2"
+	slowSensorName    = "sensor3"
 	missingSensorName = "sensor4"
 )
 
@@ -44,9 +48,20 @@ func TestClient(t *testing.T) {
 		return nil, errReadingsFailed
 	}
 
+	// slowSensor only returns once its context is done, like a sensor on a hung bus.
+	slowSensor := &inject.Sensor{}
+	slowSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
 	sensorSvc, err := resource.NewAPIResourceCollection(
 		sensors.API,
-		map[resource.Name]sensor.Sensor{sensor.Named(testSensorName): injectSensor, sensor.Named(failSensorName): injectSensor2},
+		map[resource.Name]sensor.Sensor{
+			sensor.Named(testSensorName): injectSensor,
+			sensor.Named(failSensorName): injectSensor2,
+			sensor.Named(slowSensorName): slowSensor,
+		},
 	)
 	test.That(t, err, test.ShouldBeNil)
 	resourceAPI, ok, err := resource.LookupAPIRegistration[sensor.Sensor](sensor.API)
@@ -109,4 +124,60 @@ func TestClient(t *testing.T) {
 		test.That(t, client2.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
+
+	t.Run("readings timeout", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		timeout := 100 * time.Millisecond
+		slowClient, err := sensor.NewClientFromConnWithOptions(
+			context.Background(), conn, "", sensor.Named(slowSensorName), logger, sensor.WithTimeout(timeout))
+		test.That(t, err, test.ShouldBeNil)
+
+		start := time.Now()
+		_, err = slowClient.Readings(context.Background(), nil)
+		test.That(t, time.Since(start), test.ShouldBeBetween, timeout, 5*timeout)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `sensor "sensor3" did not return readings within 100ms`)
+
+		// the caller's own deadline is kept, however long it is
+		ctx, cancel := context.WithTimeout(context.Background(), 3*timeout)
+		defer cancel()
+		start = time.Now()
+		_, err = slowClient.Readings(ctx, nil)
+		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 3*timeout)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+		test.That(t, err.Error(), test.ShouldNotContainSubstring, "did not return readings")
+
+		fastClient, err := sensor.NewClientFromConnWithOptions(
+			context.Background(), conn, "", sensor.Named(testSensorName), logger, sensor.WithTimeout(timeout))
+		test.That(t, err, test.ShouldBeNil)
+		readings, err := fastClient.Readings(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readings, test.ShouldResemble, rs)
+	})
+
+	t.Run("readings batch", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		results, err := sensor.ReadingsBatch(
+			context.Background(),
+			conn,
+			[]string{testSensorName, failSensorName, missingSensorName},
+			map[string]interface{}{"foo": "batch"},
+		)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, results, test.ShouldHaveLength, 3)
+		test.That(t, results[testSensorName].Err, test.ShouldBeNil)
+		test.That(t, results[testSensorName].Readings, test.ShouldResemble, rs)
+		test.That(t, extraCap, test.ShouldResemble, map[string]interface{}{"foo": "batch"})
+		test.That(t, results[failSensorName].Readings, test.ShouldBeNil)
+		test.That(t, results[failSensorName].Err.Error(), test.ShouldContainSubstring, errReadingsFailed.Error())
+		test.That(t, results[missingSensorName].Err.Error(), test.ShouldContainSubstring, "not found")
+
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
 }
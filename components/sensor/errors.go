@@ -0,0 +1,51 @@
+package sensor
+
+import (
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/protoutils"
+)
+
+var (
+	// ErrSensorNotFound matches, with errors.Is, the error returned when there is no sensor with the
+	// requested name.
+	ErrSensorNotFound = errors.New("sensor not found")
+	// ErrNotASensor matches, with errors.Is, the error returned when the resource with the requested
+	// name is not a sensor.
+	ErrNotASensor = errors.New("not a sensor")
+	// ErrUnsupportedReadingType matches, with errors.Is, the error returned when a sensor returns a
+	// reading that cannot be sent over gRPC. Use errors.As with a *protoutils.UnsupportedReadingTypeError
+	// to find out which reading it was and its Go type.
+	ErrUnsupportedReadingType = protoutils.ErrUnsupportedReadingType
+)
+
+// lookupError classifies an error from looking up a sensor as one of the sentinel errors while
+// keeping its original message, which already names the resource.
+type lookupError struct {
+	err  error
+	kind error
+}
+
+func (e *lookupError) Error() string {
+	return e.err.Error()
+}
+
+func (e *lookupError) Unwrap() error {
+	return e.err
+}
+
+func (e *lookupError) Is(target error) bool {
+	return target == e.kind
+}
+
+// newNotFoundError returns err, which reported that the named sensor does not exist, so that it
+// matches ErrSensorNotFound.
+func newNotFoundError(err error) error {
+	return &lookupError{err: err, kind: ErrSensorNotFound}
+}
+
+// newNotASensorError returns err, which reported that a resource is not a sensor, so that it matches
+// ErrNotASensor.
+func newNotASensorError(err error) error {
+	return &lookupError{err: err, kind: ErrNotASensor}
+}
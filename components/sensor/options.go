@@ -0,0 +1,17 @@
+package sensor
+
+// funcOption wraps a function that modifies options of type T into an implementation of the option
+// interface for T, such as StreamOption for streamOptions.
+type funcOption[T any] struct {
+	f func(*T)
+}
+
+func (fdo *funcOption[T]) apply(do *T) {
+	fdo.f(do)
+}
+
+func newFuncOption[T any](f func(*T)) *funcOption[T] {
+	return &funcOption[T]{
+		f: f,
+	}
+}
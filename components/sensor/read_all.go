@@ -0,0 +1,58 @@
+package sensor
+
+import (
+	"context"
+)
+
+// readAllWorkers is the most sensors ReadAll reads at once.
+const readAllWorkers = 16
+
+// ReadAll reads all of the given sensors concurrently, at most readAllWorkers at a time, and returns
+// the readings of those that succeeded and the errors of those that failed, both keyed by the names in
+// sensors. A slow sensor only holds up its own worker. If the context is done before every sensor has
+// been read, ReadAll returns right away with the context's error for each sensor still outstanding.
+func ReadAll(ctx context.Context, sensors map[string]Sensor) (map[string]map[string]interface{}, map[string]error) {
+	type result struct {
+		name     string
+		readings map[string]interface{}
+		err      error
+	}
+	// buffered so that reads that finish after ReadAll has returned do not block forever.
+	results := make(chan result, len(sensors))
+	workers := make(chan struct{}, readAllWorkers)
+	pending := make(map[string]struct{}, len(sensors))
+	for name, s := range sensors {
+		pending[name] = struct{}{}
+		go func(name string, s Sensor) {
+			select {
+			case workers <- struct{}{}:
+			case <-ctx.Done():
+				results <- result{name: name, err: ctx.Err()}
+				return
+			}
+			defer func() { <-workers }()
+			readings, err := s.Readings(ctx, nil)
+			results <- result{name: name, readings: readings, err: err}
+		}(name, s)
+	}
+
+	readingsByName := make(map[string]map[string]interface{}, len(sensors))
+	errs := make(map[string]error)
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.name)
+			if r.err != nil {
+				errs[r.name] = r.err
+				continue
+			}
+			readingsByName[r.name] = r.readings
+		case <-ctx.Done():
+			for name := range pending {
+				errs[name] = ctx.Err()
+			}
+			return readingsByName, errs
+		}
+	}
+	return readingsByName, errs
+}
@@ -0,0 +1,101 @@
+package sensor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestReadAll(t *testing.T) {
+	newSensor := func(readings func(ctx context.Context) (map[string]interface{}, error)) *inject.Sensor {
+		s := &inject.Sensor{}
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			return readings(ctx)
+		}
+		return s
+	}
+	errFailed := errors.New("bus error")
+
+	t.Run("independent results", func(t *testing.T) {
+		// the slow sensor only returns once both fast sensors have, so the reads must be concurrent.
+		var fastDone sync.WaitGroup
+		fastDone.Add(2)
+		fast := func(value float64) *inject.Sensor {
+			return newSensor(func(ctx context.Context) (map[string]interface{}, error) {
+				defer fastDone.Done()
+				return map[string]interface{}{"value": value}, nil
+			})
+		}
+		sensors := map[string]sensor.Sensor{
+			"fast1": fast(1),
+			"fast2": fast(2),
+			"slow": newSensor(func(ctx context.Context) (map[string]interface{}, error) {
+				fastDone.Wait()
+				return map[string]interface{}{"value": 3.0}, nil
+			}),
+			"failing": newSensor(func(ctx context.Context) (map[string]interface{}, error) {
+				return nil, errFailed
+			}),
+		}
+
+		readings, errs := sensor.ReadAll(context.Background(), sensors)
+		test.That(t, readings, test.ShouldResemble, map[string]map[string]interface{}{
+			"fast1": {"value": 1.0},
+			"fast2": {"value": 2.0},
+			"slow":  {"value": 3.0},
+		})
+		test.That(t, errs, test.ShouldResemble, map[string]error{"failing": errFailed})
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		sensors := map[string]sensor.Sensor{
+			"fast": newSensor(func(ctx context.Context) (map[string]interface{}, error) {
+				return map[string]interface{}{"value": 1.0}, nil
+			}),
+			"stuck": newSensor(func(ctx context.Context) (map[string]interface{}, error) {
+				<-ctx.Done()
+				return nil, errors.New("gave up")
+			}),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		readings, errs := sensor.ReadAll(ctx, sensors)
+		test.That(t, readings, test.ShouldResemble, map[string]map[string]interface{}{"fast": {"value": 1.0}})
+		test.That(t, errs, test.ShouldHaveLength, 1)
+		test.That(t, errors.Is(errs["stuck"], context.DeadlineExceeded), test.ShouldBeTrue)
+	})
+
+	t.Run("bounded workers", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		sensors := map[string]sensor.Sensor{}
+		for i := 0; i < 40; i++ {
+			sensors[fmt.Sprintf("sensor%d", i)] = newSensor(func(ctx context.Context) (map[string]interface{}, error) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					max := maxInFlight.Load()
+					if n <= max || maxInFlight.CompareAndSwap(max, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				return map[string]interface{}{}, nil
+			})
+		}
+
+		readings, errs := sensor.ReadAll(context.Background(), sensors)
+		test.That(t, readings, test.ShouldHaveLength, 40)
+		test.That(t, errs, test.ShouldBeEmpty)
+		test.That(t, maxInFlight.Load(), test.ShouldBeBetweenOrEqual, 2, 16)
+	})
+}
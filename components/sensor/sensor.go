@@ -44,14 +44,35 @@ type Sensor interface {
 }
 
 // FromDependencies is a helper for getting the named sensor from a collection of
-// dependencies.
+// dependencies. The error matches ErrSensorNotFound or ErrNotASensor if the sensor is missing or of
+// the wrong type.
 func FromDependencies(deps resource.Dependencies, name string) (Sensor, error) {
-	return resource.FromDependencies[Sensor](deps, Named(name))
+	res, err := deps.Lookup(Named(name))
+	if err != nil {
+		return nil, newNotFoundError(resource.DependencyNotFoundError(Named(name)))
+	}
+	s, ok := res.(Sensor)
+	if !ok {
+		return nil, newNotASensorError(resource.DependencyTypeError[Sensor](Named(name), res))
+	}
+	return s, nil
 }
 
-// FromRobot is a helper for getting the named Sensor from the given Robot.
+// FromRobot is a helper for getting the named Sensor from the given Robot. The error matches
+// ErrSensorNotFound or ErrNotASensor if the sensor is missing or of the wrong type.
 func FromRobot(r robot.Robot, name string) (Sensor, error) {
-	return robot.ResourceFromRobot[Sensor](r, Named(name))
+	res, err := r.ResourceByName(Named(name))
+	if err != nil {
+		if resource.IsNotFoundError(err) {
+			return nil, newNotFoundError(err)
+		}
+		return nil, err
+	}
+	s, ok := res.(Sensor)
+	if !ok {
+		return nil, newNotASensorError(resource.TypeError[Sensor](res))
+	}
+	return s, nil
 }
 
 // NamesFromRobot is a helper for getting all sensor names from the given Robot.
@@ -3,9 +3,17 @@ package sensor
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
 
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/sensor/v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
@@ -15,6 +23,10 @@ import (
 type serviceServer struct {
 	pb.UnimplementedSensorServiceServer
 	coll resource.APIResourceCollection[Sensor]
+	// cache is nil unless readings are cached.
+	cache *readingsCache
+	// statsHandler is nil unless stats are recorded.
+	statsHandler StatsHandler
 }
 
 // NewRPCServiceServer constructs an sensor gRPC service serviceServer.
@@ -22,16 +34,69 @@ func NewRPCServiceServer(coll resource.APIResourceCollection[Sensor]) interface{
 	return &serviceServer{coll: coll}
 }
 
+// NewRPCServiceServerWithReadingsCache constructs a sensor gRPC service serviceServer that serves the
+// readings of a sensor from a cache for up to ttl after they were last read successfully, so that many
+// clients polling the same sensor only read it once per ttl. Readings requested with extra parameters
+// are never cached since they may differ from the sensor's normal readings.
+func NewRPCServiceServerWithReadingsCache(coll resource.APIResourceCollection[Sensor], ttl time.Duration) interface{} {
+	return NewRPCServiceServerWithOptions(coll, WithReadingsCache(ttl))
+}
+
+// NewRPCServiceServerWithOptions constructs a sensor gRPC service serviceServer configured by opts.
+func NewRPCServiceServerWithOptions(coll resource.APIResourceCollection[Sensor], opts ...ServerOption) interface{} {
+	var o serverOptions
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	s := &serviceServer{coll: coll, statsHandler: o.statsHandler}
+	if o.cacheReadings {
+		s.cache = newReadingsCache(o.cacheTTL)
+	}
+	return s
+}
+
 // GetReadings returns the most recent readings from the given Sensor.
 func (s *serviceServer) GetReadings(
 	ctx context.Context,
 	req *pb.GetReadingsRequest,
 ) (*pb.GetReadingsResponse, error) {
-	sensorDevice, err := s.coll.Resource(req.Name)
+	var start time.Time
+	if s.statsHandler != nil {
+		start = time.Now()
+	}
+	m, err := s.readings(ctx, req.Name, req.Extra.AsMap())
+	if s.statsHandler != nil {
+		s.statsHandler(ctx, ReadingsStats{Name: req.Name, Duration: time.Since(start), Err: err})
+	}
 	if err != nil {
 		return nil, err
 	}
-	readings, err := sensorDevice.Readings(ctx, req.Extra.AsMap())
+	return &pb.GetReadingsResponse{Readings: m}, nil
+}
+
+func (s *serviceServer) readings(
+	ctx context.Context,
+	name string,
+	extra map[string]interface{},
+) (map[string]*structpb.Value, error) {
+	if s.cache == nil || len(extra) > 0 {
+		return s.readSensor(ctx, name, extra)
+	}
+	return s.cache.get(ctx, name, func() (map[string]*structpb.Value, error) {
+		return s.readSensor(ctx, name, extra)
+	})
+}
+
+func (s *serviceServer) readSensor(
+	ctx context.Context,
+	name string,
+	extra map[string]interface{},
+) (map[string]*structpb.Value, error) {
+	sensorDevice, err := s.coll.Resource(name)
+	if err != nil {
+		return nil, newNotFoundError(err)
+	}
+	readings, err := readingsUntilDone(ctx, name, sensorDevice, extra)
 	if err != nil {
 		return nil, err
 	}
@@ -39,16 +104,98 @@ func (s *serviceServer) GetReadings(
 	if err != nil {
 		return nil, err
 	}
-	return &pb.GetReadingsResponse{Readings: m}, nil
+	if err := checkReadingsFinite(m); err != nil {
+		return nil, errors.Wrapf(err, "invalid readings from sensor %q", name)
+	}
+	return m, nil
+}
+
+// readingsUntilDone gets the readings of the sensor, but returns a DeadlineExceeded or Canceled status
+// as soon as ctx is done, even if the sensor ignores ctx and keeps blocking. In that case the call to the
+// sensor is left to finish in the background, and a warning is logged when it does.
+func readingsUntilDone(
+	ctx context.Context,
+	name string,
+	sensorDevice Sensor,
+	extra map[string]interface{},
+) (map[string]interface{}, error) {
+	type result struct {
+		readings map[string]interface{}
+		err      error
+	}
+	start := time.Now()
+	done := make(chan result, 1)
+	go func() {
+		readings, err := sensorDevice.Readings(ctx, extra)
+		done <- result{readings, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.readings, r.err
+	case <-ctx.Done():
+		go func() {
+			r := <-done
+			golog.Global().Warnw("sensor returned readings after the request for them was abandoned",
+				"name", name, "duration", time.Since(start), "error", r.err)
+		}()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// checkReadingsFinite returns an error naming the first reading that contains a NaN or infinite
+// number. Such numbers cannot be represented in JSON, so clients that read them that way would
+// otherwise fail with an error that does not say which reading was at fault.
+func checkReadingsFinite(readings map[string]*structpb.Value) error {
+	return checkFieldsFinite("", readings)
+}
+
+func checkFieldsFinite(prefix string, fields map[string]*structpb.Value) error {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if err := checkValueFinite(path, fields[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkValueFinite(path string, v *structpb.Value) error {
+	switch x := v.GetKind().(type) {
+	case *structpb.Value_NumberValue:
+		if math.IsNaN(x.NumberValue) || math.IsInf(x.NumberValue, 0) {
+			return errors.Errorf("reading %q is %v, which is not a finite number", path, x.NumberValue)
+		}
+	case *structpb.Value_ListValue:
+		for i, elem := range x.ListValue.GetValues() {
+			if err := checkValueFinite(fmt.Sprintf("%s[%d]", path, i), elem); err != nil {
+				return err
+			}
+		}
+	case *structpb.Value_StructValue:
+		return checkFieldsFinite(path, x.StructValue.GetFields())
+	}
+	return nil
 }
 
 // DoCommand receives arbitrary commands.
 func (s *serviceServer) DoCommand(ctx context.Context,
 	req *commonpb.DoCommandRequest,
 ) (*commonpb.DoCommandResponse, error) {
+	if cmd := req.GetCommand().AsMap(); cmd[batchCommandKey] == readingsBatchCommand {
+		return s.readingsBatch(ctx, cmd)
+	}
 	sensorDevice, err := s.coll.Resource(req.Name)
 	if err != nil {
-		return nil, err
+		return nil, newNotFoundError(err)
 	}
 	return protoutils.DoFromResourceServer(ctx, sensorDevice, req)
 }
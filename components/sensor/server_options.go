@@ -0,0 +1,52 @@
+package sensor
+
+import (
+	"context"
+	"time"
+)
+
+// ReadingsStats describes a GetReadings request handled by a sensor server.
+type ReadingsStats struct {
+	// Name is the name of the sensor the readings were requested from.
+	Name string
+	// Duration is how long the request took, including looking up the sensor and converting its readings.
+	Duration time.Duration
+	// Err is the error the request failed with, or nil if it succeeded.
+	Err error
+}
+
+// StatsHandler is called with the stats of each GetReadings request once it is done. It is called on
+// the goroutine handling the request, so it should return quickly.
+type StatsHandler func(ctx context.Context, stats ReadingsStats)
+
+// serverOptions configures a sensor server.
+type serverOptions struct {
+	// statsHandler is nil unless stats are recorded, see WithStatsHandler.
+	statsHandler StatsHandler
+	// cacheReadings makes readings cached for cacheTTL, see WithReadingsCache.
+	cacheReadings bool
+	cacheTTL      time.Duration
+}
+
+// ServerOption configures a sensor server made by NewRPCServiceServerWithOptions.
+type ServerOption interface {
+	apply(*serverOptions)
+}
+
+// WithStatsHandler returns a ServerOption that calls h with the duration and outcome of every
+// GetReadings request, so that their latency and errors can be recorded with any metrics library.
+// Without it, requests are not timed at all.
+func WithStatsHandler(h StatsHandler) ServerOption {
+	return newFuncOption(func(o *serverOptions) {
+		o.statsHandler = h
+	})
+}
+
+// WithReadingsCache returns a ServerOption that caches readings for ttl, as
+// NewRPCServiceServerWithReadingsCache does.
+func WithReadingsCache(ttl time.Duration) ServerOption {
+	return newFuncOption(func(o *serverOptions) {
+		o.cacheReadings = true
+		o.cacheTTL = ttl
+	})
+}
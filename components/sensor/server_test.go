@@ -3,14 +3,25 @@ package sensor_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/sensor/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/components/sensor"
+	rprotoutils "go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/testutils/inject"
 )
@@ -70,4 +81,358 @@ func TestServer(t *testing.T) {
 		test.That(t, err, test.ShouldNotBeNil)
 		test.That(t, err.Error(), test.ShouldContainSubstring, "not found")
 	})
+
+	t.Run("readings batch", func(t *testing.T) {
+		cmd, err := protoutils.StructToStructPb(map[string]interface{}{
+			"command": "rdk:sensor:readings_batch",
+			"names":   []interface{}{testSensorName, failSensorName, missingSensorName},
+			"extra":   map[string]interface{}{"foo": "baz"},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		resp, err := sensorServer.DoCommand(context.Background(), &commonpb.DoCommandRequest{Command: cmd})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, extraCap, test.ShouldResemble, map[string]interface{}{"foo": "baz"})
+
+		result := resp.Result.AsMap()
+		test.That(t, result["readings"], test.ShouldResemble, map[string]interface{}{testSensorName: rs})
+		errs, ok := result["errors"].(map[string]interface{})
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, errs, test.ShouldHaveLength, 2)
+		test.That(t, errs[failSensorName], test.ShouldContainSubstring, errReadingsFailed.Error())
+		test.That(t, errs[missingSensorName], test.ShouldContainSubstring, "not found")
+
+		cmd, err = protoutils.StructToStructPb(map[string]interface{}{"command": "rdk:sensor:readings_batch"})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = sensorServer.DoCommand(context.Background(), &commonpb.DoCommandRequest{Command: cmd})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestServerNonFiniteReadings(t *testing.T) {
+	sensorServer, injectSensor, _, err := newServer()
+	test.That(t, err, test.ShouldBeNil)
+
+	var rs map[string]interface{}
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return rs, nil
+	}
+
+	rs = map[string]interface{}{"a": 1.1, "b": math.NaN()}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `sensor "sensor1"`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `reading "b" is NaN`)
+
+	rs = map[string]interface{}{"vec": r3.Vector{X: 1, Y: math.Inf(1)}, "list": []interface{}{1.0, math.Inf(-1)}}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `reading "list[1]" is -Inf`)
+
+	rs = map[string]interface{}{"vec": r3.Vector{X: 1, Y: math.Inf(1)}}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `reading "vec.y" is +Inf`)
+
+	rs = map[string]interface{}{"a": 1.1, "vec": r3.Vector{X: 1}}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestServerNestedReadings(t *testing.T) {
+	sensorServer, injectSensor, _, err := newServer()
+	test.That(t, err, test.ShouldBeNil)
+
+	var rs map[string]interface{}
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return rs, nil
+	}
+
+	rs = map[string]interface{}{
+		"imu": map[string]interface{}{
+			"acceleration": r3.Vector{X: 1, Y: 2, Z: 3},
+			"samples":      []float64{0.5, 1.5},
+		},
+		"tracks": []map[string]interface{}{{"id": 7, "speeds": []int{1, 2}}},
+	}
+	resp, err := sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldBeNil)
+	imu := resp.Readings["imu"].GetStructValue().GetFields()
+	test.That(t, imu["acceleration"].GetStructValue().GetFields()["y"].GetNumberValue(), test.ShouldEqual, 2)
+	test.That(t, imu["samples"].GetListValue().GetValues()[1].GetNumberValue(), test.ShouldEqual, 1.5)
+	track := resp.Readings["tracks"].GetListValue().GetValues()[0].GetStructValue().GetFields()
+	test.That(t, track["id"].GetNumberValue(), test.ShouldEqual, 7)
+	test.That(t, track["speeds"].GetListValue().GetValues()[1].GetNumberValue(), test.ShouldEqual, 2)
+
+	rs = map[string]interface{}{"imu": map[string]interface{}{"raw": []interface{}{struct{ X int }{1}}}}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `reading "imu.raw[0]" has unsupported type struct { X int }`)
+
+	// a channel cannot be converted, and the error says exactly where it was and what it was
+	rs = map[string]interface{}{"events": []interface{}{1.0, make(chan int)}}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, errors.Is(err, sensor.ErrUnsupportedReadingType), test.ShouldBeTrue)
+	var unsupported *rprotoutils.UnsupportedReadingTypeError
+	test.That(t, errors.As(err, &unsupported), test.ShouldBeTrue)
+	test.That(t, unsupported.Path, test.ShouldEqual, "events[1]")
+	test.That(t, unsupported.Type, test.ShouldEqual, reflect.TypeOf(make(chan int)))
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Internal)
+	test.That(t, status.Convert(err).Message(), test.ShouldEqual, `reading "events[1]" has unsupported type chan int`)
+
+	// nested non-finite numbers are still reported by their path
+	rs = map[string]interface{}{"imu": map[string]interface{}{"samples": []float64{0.5, math.NaN()}}}
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `reading "imu.samples[1]" is NaN`)
+}
+
+func TestServerReadingsCache(t *testing.T) {
+	injectSensor := &inject.Sensor{}
+	sensorSvc, err := resource.NewAPIResourceCollection(sensor.API, map[resource.Name]sensor.Sensor{
+		sensor.Named(testSensorName): injectSensor,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	sensorServer := sensor.NewRPCServiceServerWithReadingsCache(sensorSvc, time.Minute).(pb.SensorServiceServer)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		calls.Add(1)
+		<-release
+		return map[string]interface{}{"a": 1.1}, nil
+	}
+
+	const numRequests = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, numRequests)
+	for i := 0; i < numRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+			if err == nil && resp.Readings["a"].GetNumberValue() != 1.1 {
+				err = fmt.Errorf("unexpected readings %v", resp.Readings)
+			}
+			errs <- err
+		}()
+	}
+	// let the requests pile up on the first read before it finishes
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		test.That(t, err, test.ShouldBeNil)
+	}
+	test.That(t, calls.Load(), test.ShouldEqual, 1)
+
+	// within the TTL the cached readings are served
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, calls.Load(), test.ShouldEqual, 1)
+
+	// requests with extra parameters always read the sensor
+	extra, err := protoutils.StructToStructPb(map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName, Extra: extra})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, calls.Load(), test.ShouldEqual, 2)
+
+	t.Run("expiry and errors", func(t *testing.T) {
+		sensorServer := sensor.NewRPCServiceServerWithReadingsCache(sensorSvc, 20*time.Millisecond).(pb.SensorServiceServer)
+		var calls int
+		injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, errReadingsFailed
+			}
+			return map[string]interface{}{"a": 1.1}, nil
+		}
+
+		_, err := sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+		test.That(t, err, test.ShouldBeError, errReadingsFailed)
+		// the error was not cached
+		_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 2)
+
+		_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 2)
+
+		time.Sleep(30 * time.Millisecond)
+		_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, calls, test.ShouldEqual, 3)
+	})
+}
+
+func TestServerReadingsCacheCancelledRead(t *testing.T) {
+	injectSensor := &inject.Sensor{}
+	sensorSvc, err := resource.NewAPIResourceCollection(sensor.API, map[resource.Name]sensor.Sensor{
+		sensor.Named(testSensorName): injectSensor,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	sensorServer := sensor.NewRPCServiceServerWithReadingsCache(sensorSvc, time.Minute).(pb.SensorServiceServer)
+
+	// the first read blocks until released, ignoring its context, and later ones return right away.
+	var calls atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		if calls.Add(1) == 1 {
+			<-release
+		}
+		return map[string]interface{}{"a": 1.1}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := sensorServer.GetReadings(ctx, &pb.GetReadingsRequest{Name: testSensorName})
+		leaderErr <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	type result struct {
+		resp *pb.GetReadingsResponse
+		err  error
+	}
+	waiter := make(chan result, 1)
+	go func() {
+		resp, err := sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+		waiter <- result{resp, err}
+	}()
+	// let the second request start waiting on the first read before the first request is cancelled.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	test.That(t, status.Code(<-leaderErr), test.ShouldEqual, codes.Canceled)
+	// the waiting request reads the sensor itself instead of failing with the first request.
+	r := <-waiter
+	test.That(t, r.err, test.ShouldBeNil)
+	test.That(t, r.resp.Readings["a"].GetNumberValue(), test.ShouldEqual, 1.1)
+	test.That(t, calls.Load(), test.ShouldEqual, 2)
+}
+
+func TestServerReadingsDeadline(t *testing.T) {
+	sensorServer, injectSensor, _, err := newServer()
+	test.That(t, err, test.ShouldBeNil)
+
+	// the sensor ignores its context and blocks until released
+	release := make(chan struct{})
+	finished := make(chan struct{})
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		defer close(finished)
+		<-release
+		return map[string]interface{}{"a": 1.1}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = sensorServer.GetReadings(ctx, &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, time.Since(start), test.ShouldBeLessThan, time.Second)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+
+	// the abandoned read is still allowed to finish
+	close(release)
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned read never finished")
+	}
+
+	// and the sensor can be read normally afterwards
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"a": 2.2}, nil
+	}
+	resp, err := sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Readings["a"].GetNumberValue(), test.ShouldEqual, 2.2)
+}
+
+func TestSentinelErrors(t *testing.T) {
+	sensorServer, _, _, err := newServer()
+	test.That(t, err, test.ShouldBeNil)
+	notASensor := inject.NewGeneric(testSensorName)
+
+	t.Run("server", func(t *testing.T) {
+		_, err := sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: missingSensorName})
+		test.That(t, errors.Is(err, sensor.ErrSensorNotFound), test.ShouldBeTrue)
+		test.That(t, errors.Is(err, sensor.ErrNotASensor), test.ShouldBeFalse)
+		test.That(t, resource.IsNotFoundError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldEqual, resource.NewNotFoundError(sensor.Named(missingSensorName)).Error())
+	})
+
+	t.Run("robot", func(t *testing.T) {
+		r := &inject.Robot{}
+		r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+			if name.Name == testSensorName {
+				return notASensor, nil
+			}
+			return nil, resource.NewNotFoundError(name)
+		}
+		_, err := sensor.FromRobot(r, missingSensorName)
+		test.That(t, errors.Is(err, sensor.ErrSensorNotFound), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "not found")
+
+		_, err = sensor.FromRobot(r, testSensorName)
+		test.That(t, errors.Is(err, sensor.ErrNotASensor), test.ShouldBeTrue)
+		test.That(t, errors.Is(err, sensor.ErrSensorNotFound), test.ShouldBeFalse)
+		test.That(t, err.Error(), test.ShouldEqual, resource.TypeError[sensor.Sensor](notASensor).Error())
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		deps := resource.Dependencies{sensor.Named(testSensorName): notASensor}
+		_, err := sensor.FromDependencies(deps, missingSensorName)
+		test.That(t, errors.Is(err, sensor.ErrSensorNotFound), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "missing from dependencies")
+
+		_, err = sensor.FromDependencies(deps, testSensorName)
+		test.That(t, errors.Is(err, sensor.ErrNotASensor), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "should be an implementation of")
+	})
+}
+
+func TestServerStatsHandler(t *testing.T) {
+	injectSensor := &inject.Sensor{}
+	injectSensor2 := &inject.Sensor{}
+	sensorSvc, err := resource.NewAPIResourceCollection(sensor.API, map[resource.Name]sensor.Sensor{
+		sensor.Named(testSensorName): injectSensor,
+		sensor.Named(failSensorName): injectSensor2,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return map[string]interface{}{"a": 1.1}, nil
+	}
+	injectSensor2.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errReadingsFailed
+	}
+
+	var recorded []sensor.ReadingsStats
+	sensorServer := sensor.NewRPCServiceServerWithOptions(sensorSvc, sensor.WithStatsHandler(
+		func(ctx context.Context, stats sensor.ReadingsStats) {
+			recorded = append(recorded, stats)
+		},
+	)).(pb.SensorServiceServer)
+
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: testSensorName})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = sensorServer.GetReadings(context.Background(), &pb.GetReadingsRequest{Name: failSensorName})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, recorded, test.ShouldHaveLength, 2)
+	test.That(t, recorded[0].Name, test.ShouldEqual, testSensorName)
+	test.That(t, recorded[0].Err, test.ShouldBeNil)
+	test.That(t, recorded[0].Duration, test.ShouldBeGreaterThanOrEqualTo, 10*time.Millisecond)
+	test.That(t, recorded[1].Name, test.ShouldEqual, failSensorName)
+	test.That(t, recorded[1].Err, test.ShouldEqual, err)
 }
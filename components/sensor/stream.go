@@ -0,0 +1,134 @@
+package sensor
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+)
+
+// ReadingsFrame is a set of readings sent by StreamReadings, or the error that reading them failed with.
+type ReadingsFrame struct {
+	Readings map[string]interface{}
+	Err      error
+}
+
+// streamOptions configures StreamReadings.
+type streamOptions struct {
+	// changeThreshold is how much a numeric reading must change for a frame to be sent, or negative to
+	// send every frame.
+	changeThreshold float64
+}
+
+// StreamOption configures StreamReadings.
+type StreamOption interface {
+	apply(*streamOptions)
+}
+
+// ChangeThreshold returns a StreamOption that only sends a frame when its readings differ from the last
+// frame sent: a numeric reading, including one nested in a map or list, must have changed by more than
+// epsilon, and any other reading must have changed at all. Readings appearing or disappearing is always
+// a change. The filtering is done by StreamReadings in the caller's process, not by the sensor's server:
+// the sensor API has no streaming RPC to suppress frames on, so a remote sensor is still read over the
+// network every interval, and only the frames passed on to the caller are cut.
+func ChangeThreshold(epsilon float64) StreamOption {
+	return newFuncOption(func(o *streamOptions) {
+		o.changeThreshold = math.Abs(epsilon)
+	})
+}
+
+// StreamReadings reads the sensor every interval and sends its readings on the returned channel, e.g. to
+// keep a dashboard up to date. The sensor API has no streaming RPC, so for a remote sensor each frame is
+// a GetReadings call over the sensor client's connection, and ChangeThreshold saves sending unchanged
+// frames on to the caller rather than the calls themselves. Frames are not queued, so a reader slower
+// than interval gets fewer of them. The channel is closed when ctx is done or after a frame with an error.
+func StreamReadings(ctx context.Context, s Sensor, interval time.Duration, opts ...StreamOption) (<-chan ReadingsFrame, error) {
+	if interval <= 0 {
+		return nil, errors.New("stream interval must be greater than 0")
+	}
+	o := streamOptions{changeThreshold: -1}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	frames := make(chan ReadingsFrame)
+	goutils.PanicCapturingGo(func() {
+		defer close(frames)
+		var last map[string]interface{}
+		for {
+			readings, err := s.Readings(ctx, nil)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil || last == nil || o.changeThreshold < 0 || readingsChanged(last, readings, o.changeThreshold) {
+				select {
+				case frames <- ReadingsFrame{Readings: readings, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+				last = readings
+			}
+			if err != nil || !goutils.SelectContextOrWait(ctx, interval) {
+				return
+			}
+		}
+	})
+	return frames, nil
+}
+
+// readingsChanged returns whether any reading differs between prev and next by more than epsilon.
+func readingsChanged(prev, next map[string]interface{}, epsilon float64) bool {
+	if len(prev) != len(next) {
+		return true
+	}
+	for k, prevValue := range prev {
+		nextValue, ok := next[k]
+		if !ok || valueChanged(prevValue, nextValue, epsilon) {
+			return true
+		}
+	}
+	return false
+}
+
+func valueChanged(prev, next interface{}, epsilon float64) bool {
+	if prevNum, ok := toFloat(prev); ok {
+		nextNum, ok := toFloat(next)
+		return !ok || math.Abs(nextNum-prevNum) > epsilon
+	}
+	switch prevValue := prev.(type) {
+	case map[string]interface{}:
+		nextValue, ok := next.(map[string]interface{})
+		return !ok || readingsChanged(prevValue, nextValue, epsilon)
+	case []interface{}:
+		nextValue, ok := next.([]interface{})
+		if !ok || len(prevValue) != len(nextValue) {
+			return true
+		}
+		for i := range prevValue {
+			if valueChanged(prevValue[i], nextValue[i], epsilon) {
+				return true
+			}
+		}
+		return false
+	default:
+		return !reflect.DeepEqual(prev, next)
+	}
+}
+
+// toFloat returns v as a float64 if it is a number of any type.
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,109 @@
+package sensor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestStreamReadings(t *testing.T) {
+	errDone := errors.New("no more readings")
+	// newSensor returns a sensor whose readings are values in turn, after which it fails with errDone.
+	newSensor := func(values ...map[string]interface{}) *inject.Sensor {
+		s := &inject.Sensor{}
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			if len(values) == 0 {
+				return nil, errDone
+			}
+			next := values[0]
+			values = values[1:]
+			return next, nil
+		}
+		return s
+	}
+	collect := func(frames <-chan sensor.ReadingsFrame) ([]map[string]interface{}, error) {
+		var readings []map[string]interface{}
+		for frame := range frames {
+			if frame.Err != nil {
+				return readings, frame.Err
+			}
+			readings = append(readings, frame.Readings)
+		}
+		return readings, nil
+	}
+
+	_, err := sensor.StreamReadings(context.Background(), newSensor(), 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("every reading without a threshold", func(t *testing.T) {
+		s := newSensor(map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 1.0})
+		frames, err := sensor.StreamReadings(context.Background(), s, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		readings, err := collect(frames)
+		test.That(t, err, test.ShouldBeError, errDone)
+		test.That(t, readings, test.ShouldHaveLength, 3)
+	})
+
+	t.Run("constant sensor", func(t *testing.T) {
+		var values []map[string]interface{}
+		for i := 0; i < 10; i++ {
+			values = append(values, map[string]interface{}{"a": 1.0, "b": "ok", "c": map[string]interface{}{"x": 2}})
+		}
+		frames, err := sensor.StreamReadings(context.Background(), newSensor(values...), time.Millisecond, sensor.ChangeThreshold(0.1))
+		test.That(t, err, test.ShouldBeNil)
+		readings, err := collect(frames)
+		test.That(t, err, test.ShouldBeError, errDone)
+		// only the first reading, since nothing changes after it.
+		test.That(t, readings, test.ShouldResemble, values[:1])
+	})
+
+	t.Run("stepping sensor", func(t *testing.T) {
+		// each step holds for three readings with noise under the threshold, which must not send frames.
+		var values []map[string]interface{}
+		for step := 0; step < 4; step++ {
+			for i := 0; i < 3; i++ {
+				values = append(values, map[string]interface{}{"a": float64(step) + 0.01*float64(i), "list": []interface{}{step}})
+			}
+		}
+		frames, err := sensor.StreamReadings(context.Background(), newSensor(values...), time.Millisecond, sensor.ChangeThreshold(0.1))
+		test.That(t, err, test.ShouldBeNil)
+		readings, err := collect(frames)
+		test.That(t, err, test.ShouldBeError, errDone)
+		test.That(t, readings, test.ShouldResemble, []map[string]interface{}{values[0], values[3], values[6], values[9]})
+	})
+
+	t.Run("changed keys and non-numeric readings", func(t *testing.T) {
+		values := []map[string]interface{}{
+			{"a": 1.0},
+			{"a": 1.0, "b": true},
+			{"a": 1.0, "b": true},
+			{"a": 1.0, "b": false},
+			{"b": false},
+		}
+		frames, err := sensor.StreamReadings(context.Background(), newSensor(values...), time.Millisecond, sensor.ChangeThreshold(0.1))
+		test.That(t, err, test.ShouldBeNil)
+		readings, err := collect(frames)
+		test.That(t, err, test.ShouldBeError, errDone)
+		test.That(t, readings, test.ShouldResemble, []map[string]interface{}{values[0], values[1], values[3], values[4]})
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		s := &inject.Sensor{}
+		s.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+			return map[string]interface{}{"a": 1.0}, nil
+		}
+		frames, err := sensor.StreamReadings(ctx, s, time.Millisecond, sensor.ChangeThreshold(0.1))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, (<-frames).Readings, test.ShouldResemble, map[string]interface{}{"a": 1.0})
+		cancel()
+		_, ok := <-frames
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+}
@@ -3,8 +3,10 @@ package servo
 
 import (
 	"context"
+	"sync"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/servo/v1"
 	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
@@ -21,6 +23,12 @@ type client struct {
 	name   string
 	client pb.ServoServiceClient
 	logger golog.Logger
+	opts   clientOptions
+
+	mu sync.Mutex
+	// cancelMove cancels the Move in flight when moves are latest-wins, and moveID identifies it.
+	cancelMove context.CancelFunc
+	moveID     uint64
 }
 
 // NewClientFromConn constructs a new Client from connection passed in.
@@ -31,13 +39,33 @@ func NewClientFromConn(
 	name resource.Name,
 	logger golog.Logger,
 ) (Servo, error) {
-	c := pb.NewServoServiceClient(conn)
-	return &client{
+	return NewClientFromConnWithOptions(ctx, conn, remoteName, name, logger)
+}
+
+// NewClientFromConnWithOptions constructs a new Client from connection passed in, configured by opts.
+// name must not be empty, since the server could not tell which servo its requests are for. The client's
+// Name returns it, with remoteName prepended.
+func NewClientFromConnWithOptions(
+	ctx context.Context,
+	conn rpc.ClientConn,
+	remoteName string,
+	name resource.Name,
+	logger golog.Logger,
+	opts ...ClientOption,
+) (Servo, error) {
+	if name.Name == "" {
+		return nil, errors.New("servo client needs the name of the servo to control, got an empty name")
+	}
+	c := &client{
 		Named:  name.PrependRemote(remoteName).AsNamed(),
 		name:   name.ShortName(),
-		client: c,
+		client: pb.NewServoServiceClient(conn),
 		logger: logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt.apply(&c.opts)
+	}
+	return c, nil
 }
 
 func (c *client) Move(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error {
@@ -45,6 +73,11 @@ func (c *client) Move(ctx context.Context, angleDeg uint32, extra map[string]int
 	if err != nil {
 		return err
 	}
+	if c.opts.latestWins {
+		var done func()
+		ctx, done = c.replaceMove(ctx)
+		defer done()
+	}
 	req := &pb.MoveRequest{AngleDeg: angleDeg, Name: c.name, Extra: ext}
 	if _, err := c.client.Move(ctx, req); err != nil {
 		return err
@@ -52,6 +85,28 @@ func (c *client) Move(ctx context.Context, angleDeg uint32, extra map[string]int
 	return nil
 }
 
+// replaceMove cancels the Move in flight, if any, and returns the context for a new one along with a
+// function to call when the new one is done.
+func (c *client) replaceMove(ctx context.Context) (context.Context, func()) {
+	moveCtx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancelMove != nil {
+		c.cancelMove()
+	}
+	c.moveID++
+	id := c.moveID
+	c.cancelMove = cancel
+	return moveCtx, func() {
+		c.mu.Lock()
+		if c.moveID == id {
+			c.cancelMove = nil
+		}
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
 func (c *client) Position(ctx context.Context, extra map[string]interface{}) (uint32, error) {
 	ext, err := protoutils.StructToStructPb(extra)
 	if err != nil {
@@ -65,6 +120,20 @@ func (c *client) Position(ctx context.Context, extra map[string]interface{}) (ui
 	return resp.PositionDeg, nil
 }
 
+func (c *client) Range(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+	cmd := map[string]interface{}{rangeCommandKey: rangeCommand}
+	for k, v := range extra {
+		if k != rangeCommandKey {
+			cmd[k] = v
+		}
+	}
+	resp, err := rprotoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
+	if err != nil {
+		return 0, 0, err
+	}
+	return rangeFromResponse(resp)
+}
+
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
 	ext, err := protoutils.StructToStructPb(extra)
 	if err != nil {
@@ -0,0 +1,38 @@
+package servo
+
+// clientOptions configures a servo client.
+type clientOptions struct {
+	// latestWins makes a Move cancel the one in flight, see WithLatestWins.
+	latestWins bool
+}
+
+// ClientOption configures a servo client made by NewClientFromConnWithOptions.
+type ClientOption interface {
+	apply(*clientOptions)
+}
+
+// funcOption wraps a function that modifies clientOptions into an
+// implementation of the ClientOption interface.
+type funcOption struct {
+	f func(*clientOptions)
+}
+
+func (fdo *funcOption) apply(do *clientOptions) {
+	fdo.f(do)
+}
+
+func newFuncOption(f func(*clientOptions)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithLatestWins returns a ClientOption that makes each Move cancel the context of the Move still in
+// flight from the same client, if there is one, so that the server can abort it and only the latest
+// position is moved to. The cancelled Move returns a Canceled error. Without it, every Move runs to
+// completion, even when they overlap.
+func WithLatestWins() ClientOption {
+	return newFuncOption(func(o *clientOptions) {
+		o.latestWins = true
+	})
+}
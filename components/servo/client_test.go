@@ -2,12 +2,17 @@ package servo_test
 
 import (
 	"context"
+	"math"
 	"net"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/components/servo"
 	viamgrpc "go.viam.com/rdk/grpc"
@@ -46,6 +51,16 @@ func TestClient(t *testing.T) {
 		actualExtra = extra
 		return nil
 	}
+	workingServo.RangeFunc = func(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+		actualExtra = extra
+		return 15, 165, nil
+	}
+	// the working servo reports that it is moving for the first few polls after each move
+	var movingPolls atomic.Int32
+	var movedTo atomic.Uint32
+	workingServo.IsMovingFunc = func(ctx context.Context) (bool, error) {
+		return movingPolls.Add(-1) >= 0, nil
+	}
 
 	failingServo.MoveFunc = func(ctx context.Context, angle uint32, extra map[string]interface{}) error {
 		return errMoveFailed
@@ -56,6 +71,9 @@ func TestClient(t *testing.T) {
 	failingServo.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
 		return errStopFailed
 	}
+	failingServo.RangeFunc = func(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+		return 0, 0, errRangeUnknown
+	}
 
 	resourceMap := map[resource.Name]servo.Servo{
 		servo.Named(testServoName): workingServo,
@@ -96,15 +114,75 @@ func TestClient(t *testing.T) {
 		err = workingServoClient.Move(context.Background(), 20, map[string]interface{}{"foo": "Move"})
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, actualExtra, test.ShouldResemble, map[string]interface{}{"foo": "Move"})
+		degsPerSec, ok := servo.SpeedFromExtra(actualExtra)
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, degsPerSec, test.ShouldEqual, 0)
+
+		err = servo.MoveWithSpeed(context.Background(), workingServoClient, 90, 15, map[string]interface{}{"foo": "MoveWithSpeed"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, actualExtra["foo"], test.ShouldEqual, "MoveWithSpeed")
+		degsPerSec, ok = servo.SpeedFromExtra(actualExtra)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, degsPerSec, test.ShouldEqual, 15)
+
+		err = servo.MoveWithSpeed(context.Background(), workingServoClient, 90, -15, nil)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		prevMove := workingServo.MoveFunc
+		var pollsPerMove atomic.Int32
+		pollsPerMove.Store(3)
+		workingServo.MoveFunc = func(ctx context.Context, angle uint32, extra map[string]interface{}) error {
+			movedTo.Store(angle)
+			movingPolls.Store(pollsPerMove.Load())
+			return nil
+		}
+		err = servo.MoveAndWait(context.Background(), workingServoClient, 45, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, movedTo.Load(), test.ShouldEqual, 45)
+		test.That(t, movingPolls.Load(), test.ShouldEqual, -1)
+
+		// a servo that never settles is waited on until the context is done
+		pollsPerMove.Store(math.MaxInt32)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		err = servo.MoveAndWait(ctx, workingServoClient, 60, time.Millisecond)
+		cancel()
+		// depending on timing, the deadline passes while waiting or during a call to IsMoving
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, context.DeadlineExceeded.Error())
+		test.That(t, movedTo.Load(), test.ShouldEqual, 60)
+		movingPolls.Store(0)
+		workingServo.MoveFunc = prevMove
 
 		currentDeg, err := workingServoClient.Position(context.Background(), map[string]interface{}{"foo": "Position"})
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, currentDeg, test.ShouldEqual, 20)
 		test.That(t, actualExtra, test.ShouldResemble, map[string]interface{}{"foo": "Position"})
 
+		streamCtx, cancelStream := context.WithCancel(context.Background())
+		samples, err := servo.StreamPosition(streamCtx, workingServoClient, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		for i := 0; i < 3; i++ {
+			sample, ok := <-samples
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, sample.Err, test.ShouldBeNil)
+			test.That(t, sample.PositionDeg, test.ShouldEqual, 20)
+		}
+		cancelStream()
+		for range samples {
+			// a sample may already be in flight when the stream is canceled, but the channel is closed after it
+		}
+		_, err = servo.StreamPosition(context.Background(), workingServoClient, 0)
+		test.That(t, err, test.ShouldNotBeNil)
+
 		test.That(t, workingServoClient.Stop(context.Background(), map[string]interface{}{"foo": "Stop"}), test.ShouldBeNil)
 		test.That(t, actualExtra, test.ShouldResemble, map[string]interface{}{"foo": "Stop"})
 
+		minDeg, maxDeg, err := workingServoClient.Range(context.Background(), map[string]interface{}{"foo": "Range"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, minDeg, test.ShouldEqual, 15)
+		test.That(t, maxDeg, test.ShouldEqual, 165)
+		test.That(t, actualExtra, test.ShouldResemble, map[string]interface{}{"foo": "Range"})
+
 		test.That(t, workingServoClient.Close(context.Background()), test.ShouldBeNil)
 
 		test.That(t, conn.Close(), test.ShouldBeNil)
@@ -124,14 +202,66 @@ func TestClient(t *testing.T) {
 		test.That(t, err, test.ShouldNotBeNil)
 		test.That(t, err.Error(), test.ShouldContainSubstring, errPositionUnreadable.Error())
 
+		samples, err := servo.StreamPosition(context.Background(), failingServoClient, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		sample := <-samples
+		test.That(t, sample.Err, test.ShouldNotBeNil)
+		test.That(t, sample.Err.Error(), test.ShouldContainSubstring, errPositionUnreadable.Error())
+		_, ok := <-samples
+		test.That(t, ok, test.ShouldBeFalse)
+
 		err = failingServoClient.Stop(context.Background(), nil)
 		test.That(t, err, test.ShouldNotBeNil)
 		test.That(t, err.Error(), test.ShouldContainSubstring, errStopFailed.Error())
 
+		_, _, err = failingServoClient.Range(context.Background(), nil)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, errRangeUnknown.Error())
+
 		test.That(t, failingServoClient.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 
+	t.Run("closing does not close the borrowed connection", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		servoClient, err := servo.NewClientFromConn(context.Background(), conn, "", servo.Named(testServoName), logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		test.That(t, servoClient.Close(context.Background()), test.ShouldBeNil)
+		test.That(t, servoClient.Close(context.Background()), test.ShouldBeNil)
+
+		// the connection belongs to whoever dialed it, so other clients can keep using it.
+		otherClient, err := servo.NewClientFromConn(context.Background(), conn, "", servo.Named(testServoName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = otherClient.Position(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	})
+
+	t.Run("client names", func(t *testing.T) {
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+
+		servoClient, err := servo.NewClientFromConn(context.Background(), conn, "", servo.Named(testServoName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, servoClient.Name(), test.ShouldResemble, servo.Named(testServoName))
+		test.That(t, servoClient.Name().ShortName(), test.ShouldEqual, testServoName)
+
+		remoteClient, err := servo.NewClientFromConn(context.Background(), conn, "remote", servo.Named(testServoName), logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, remoteClient.Name().ShortName(), test.ShouldEqual, "remote:"+testServoName)
+
+		_, err = servo.NewClientFromConn(context.Background(), conn, "", servo.Named(""), logger)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "empty name")
+		_, err = servo.NewClientFromConnWithOptions(context.Background(), conn, "", servo.Named(""), logger, servo.WithLatestWins())
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
 	t.Run("dialed client tests for working servo", func(t *testing.T) {
 		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
 		test.That(t, err, test.ShouldBeNil)
@@ -147,4 +277,56 @@ func TestClient(t *testing.T) {
 
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
+	t.Run("latest-wins moves", func(t *testing.T) {
+		// the servo takes until its context is done or it is released to finish each move
+		type move struct {
+			angle   uint32
+			ctx     context.Context
+			release chan struct{}
+		}
+		moves := make(chan move, 2)
+		prevMove := workingServo.MoveFunc
+		defer func() { workingServo.MoveFunc = prevMove }()
+		workingServo.MoveFunc = func(ctx context.Context, angle uint32, extra map[string]interface{}) error {
+			m := move{angle: angle, ctx: ctx, release: make(chan struct{})}
+			moves <- m
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-m.release:
+				return nil
+			}
+		}
+
+		conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		latestWinsClient, err := servo.NewClientFromConnWithOptions(
+			context.Background(), conn, "", servo.Named(testServoName), logger, servo.WithLatestWins())
+		test.That(t, err, test.ShouldBeNil)
+
+		firstErr := make(chan error, 1)
+		go func() {
+			firstErr <- latestWinsClient.Move(context.Background(), 30, nil)
+		}()
+		first := <-moves
+		test.That(t, first.angle, test.ShouldEqual, 30)
+
+		secondErr := make(chan error, 1)
+		go func() {
+			secondErr <- latestWinsClient.Move(context.Background(), 60, nil)
+		}()
+		second := <-moves
+		test.That(t, second.angle, test.ShouldEqual, 60)
+
+		err = <-firstErr
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Canceled)
+		<-first.ctx.Done()
+		test.That(t, second.ctx.Err(), test.ShouldBeNil)
+
+		close(second.release)
+		test.That(t, <-secondErr, test.ShouldBeNil)
+	})
 }
@@ -42,6 +42,11 @@ func (s *Servo) Position(ctx context.Context, extra map[string]interface{}) (uin
 	return s.angle, nil
 }
 
+// Range returns the full range of a servo.
+func (s *Servo) Range(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+	return servo.DefaultMinDeg, servo.DefaultMaxDeg, nil
+}
+
 // Stop doesn't do anything for a fake servo.
 func (s *Servo) Stop(ctx context.Context, extra map[string]interface{}) error {
 	return nil
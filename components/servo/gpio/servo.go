@@ -338,6 +338,11 @@ func (s *servoGPIO) Position(ctx context.Context, extra map[string]interface{})
 	return uint32(mapDutyCylePctToDeg(s.minUs, s.maxUs, s.minDeg, s.maxDeg, pct, s.frequency)), nil
 }
 
+// Range returns the configured minimum and maximum angles of the servo.
+func (s *servoGPIO) Range(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+	return uint32(s.minDeg), uint32(s.maxDeg), nil
+}
+
 // Stop stops the servo. It is assumed the servo stops immediately.
 func (s *servoGPIO) Stop(ctx context.Context, extra map[string]interface{}) error {
 	ctx, done := s.opMgr.New(ctx)
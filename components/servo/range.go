@@ -0,0 +1,30 @@
+package servo
+
+import (
+	"github.com/pkg/errors"
+)
+
+// The servo API has no dedicated RPC for Range, so the client and server exchange it
+// over DoCommand using a reserved command that the server handles before the servo sees it.
+const (
+	rangeCommandKey = "command"
+	rangeCommand    = "rdk:servo:range"
+	rangeMinKey     = "min_angle_deg"
+	rangeMaxKey     = "max_angle_deg"
+)
+
+func rangeToResponse(minDeg, maxDeg uint32) map[string]interface{} {
+	return map[string]interface{}{rangeMinKey: minDeg, rangeMaxKey: maxDeg}
+}
+
+func rangeFromResponse(resp map[string]interface{}) (uint32, uint32, error) {
+	minDeg, ok := resp[rangeMinKey].(float64)
+	if !ok {
+		return 0, 0, errors.Errorf("servo range response missing %q", rangeMinKey)
+	}
+	maxDeg, ok := resp[rangeMaxKey].(float64)
+	if !ok {
+		return 0, 0, errors.Errorf("servo range response missing %q", rangeMaxKey)
+	}
+	return uint32(minDeg), uint32(maxDeg), nil
+}
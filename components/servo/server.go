@@ -6,6 +6,7 @@ import (
 
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/servo/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/protoutils"
@@ -77,5 +78,17 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if cmd := req.GetCommand().AsMap(); cmd[rangeCommandKey] == rangeCommand {
+		delete(cmd, rangeCommandKey)
+		minDeg, maxDeg, err := servo.Range(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		res, err := structpb.NewStruct(rangeToResponse(minDeg, maxDeg))
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: res}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, servo, req)
 }
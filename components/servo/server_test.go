@@ -18,6 +18,7 @@ var (
 	errMoveFailed         = errors.New("move failed")
 	errPositionUnreadable = errors.New("current angle not readable")
 	errStopFailed         = errors.New("stop failed")
+	errRangeUnknown       = errors.New("range unknown")
 )
 
 func newServer() (pb.ServoServiceServer, *inject.Servo, *inject.Servo, error) {
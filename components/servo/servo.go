@@ -2,12 +2,16 @@ package servo
 
 import (
 	"context"
+	"time"
 
+	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/servo/v1"
+	goutils "go.viam.com/utils"
 
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/utils"
 )
 
 func init() {
@@ -41,8 +45,19 @@ type Servo interface {
 
 	// Position returns the current set angle (degrees) of the servo.
 	Position(ctx context.Context, extra map[string]interface{}) (uint32, error)
+
+	// Range returns the minimum and maximum angles (degrees) the servo can be moved to.
+	// Servos without a configured range report the full 0-180 degrees.
+	Range(ctx context.Context, extra map[string]interface{}) (minDeg, maxDeg uint32, err error)
 }
 
+const (
+	// DefaultMinDeg is the minimum angle reported by servos that do not have a configured range.
+	DefaultMinDeg = 0
+	// DefaultMaxDeg is the maximum angle reported by servos that do not have a configured range.
+	DefaultMaxDeg = 180
+)
+
 // Named is a helper for getting the named Servo's typed resource name.
 func Named(name string) resource.Name {
 	return resource.NewName(API, name)
@@ -70,3 +85,88 @@ func CreateStatus(ctx context.Context, s Servo) (*pb.Status, error) {
 	}
 	return &pb.Status{PositionDeg: position, IsMoving: isMoving}, nil
 }
+
+// Sweep steps the servo from one angle to another by step degrees, moving to each position in turn and
+// waiting dwell before the next. Descending ranges (from > to) sweep downwards. The final position is always
+// to, even when the range is not a multiple of step. Cancelling the context stops the sweep between steps.
+func Sweep(ctx context.Context, s Servo, from, to, step uint8, dwell time.Duration) error {
+	if step == 0 {
+		return errors.New("sweep step must be greater than 0")
+	}
+	pos := int(from)
+	for {
+		if err := s.Move(ctx, uint32(pos), nil); err != nil {
+			return err
+		}
+		if pos == int(to) {
+			return nil
+		}
+		if !goutils.SelectContextOrWait(ctx, dwell) {
+			return ctx.Err()
+		}
+		if from <= to {
+			pos = utils.MinInt(pos+int(step), int(to))
+		} else {
+			pos = utils.MaxInt(pos-int(step), int(to))
+		}
+	}
+}
+
+// MoveAndWait moves the servo to the given angle and then polls IsMoving every pollInterval until the
+// servo reports that it has stopped, so that the move is finished when it returns even if the servo
+// returns from Move before reaching the angle. Cancelling the context stops the waiting, not the move.
+func MoveAndWait(ctx context.Context, s Servo, angleDeg uint8, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		return errors.New("poll interval must be greater than 0")
+	}
+	if err := s.Move(ctx, uint32(angleDeg), nil); err != nil {
+		return err
+	}
+	for {
+		isMoving, err := s.IsMoving(ctx)
+		if err != nil {
+			return err
+		}
+		if !isMoving {
+			return nil
+		}
+		if !goutils.SelectContextOrWait(ctx, pollInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// PositionSample is a position read by StreamPosition, or the error that reading it failed with.
+type PositionSample struct {
+	PositionDeg uint32
+	Err         error
+}
+
+// StreamPosition reads the servo's position every interval and sends it on the returned channel, e.g. to
+// watch a servo live while tuning it. The servo API has no streaming RPC, so for a remote servo each sample
+// is a GetPosition call over the servo client's connection. Samples are not queued, so a reader slower than
+// interval gets fewer of them. The channel is closed when ctx is done or after a sample with an error.
+func StreamPosition(ctx context.Context, s Servo, interval time.Duration) (<-chan PositionSample, error) {
+	if interval <= 0 {
+		return nil, errors.New("stream interval must be greater than 0")
+	}
+	samples := make(chan PositionSample)
+	goutils.PanicCapturingGo(func() {
+		defer close(samples)
+		for {
+			positionDeg, err := s.Position(ctx, nil)
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case samples <- PositionSample{PositionDeg: positionDeg, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil || !goutils.SelectContextOrWait(ctx, interval) {
+				return
+			}
+		}
+	})
+	return samples, nil
+}
@@ -3,6 +3,7 @@ package servo_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	pb "go.viam.com/api/component/servo/v1"
@@ -57,3 +58,60 @@ func TestCreateStatus(t *testing.T) {
 		test.That(t, err, test.ShouldBeError, errFail)
 	})
 }
+
+func TestSweep(t *testing.T) {
+	var angles []uint32
+	injectServo := inject.NewServo("servo")
+	injectServo.MoveFunc = func(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error {
+		angles = append(angles, angleDeg)
+		return nil
+	}
+
+	t.Run("ascending", func(t *testing.T) {
+		angles = nil
+		err := servo.Sweep(context.Background(), injectServo, 10, 50, 10, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, angles, test.ShouldResemble, []uint32{10, 20, 30, 40, 50})
+	})
+
+	t.Run("descending with uneven step", func(t *testing.T) {
+		angles = nil
+		err := servo.Sweep(context.Background(), injectServo, 180, 100, 30, time.Millisecond)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, angles, test.ShouldResemble, []uint32{180, 150, 120, 100})
+	})
+
+	t.Run("single position", func(t *testing.T) {
+		angles = nil
+		err := servo.Sweep(context.Background(), injectServo, 90, 90, 5, time.Hour)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, angles, test.ShouldResemble, []uint32{90})
+	})
+
+	t.Run("zero step", func(t *testing.T) {
+		err := servo.Sweep(context.Background(), injectServo, 0, 180, 0, time.Millisecond)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		angles = nil
+		ctx, cancel := context.WithCancel(context.Background())
+		injectServo.MoveFunc = func(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error {
+			angles = append(angles, angleDeg)
+			cancel()
+			return nil
+		}
+		err := servo.Sweep(ctx, injectServo, 0, 180, 10, time.Hour)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+		test.That(t, angles, test.ShouldResemble, []uint32{0})
+	})
+
+	t.Run("move error", func(t *testing.T) {
+		errMove := errors.New("can't move")
+		injectServo.MoveFunc = func(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error {
+			return errMove
+		}
+		err := servo.Sweep(context.Background(), injectServo, 0, 180, 10, time.Millisecond)
+		test.That(t, err, test.ShouldBeError, errMove)
+	})
+}
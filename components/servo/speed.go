@@ -0,0 +1,35 @@
+package servo
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// speedKey is the extra key under which MoveWithSpeed passes the requested speed to a servo. The servo
+// API has no speed parameter, so it is sent as part of extra and forwarded to remote servos by the client.
+const speedKey = "speed_degs_per_sec"
+
+// MoveWithSpeed moves the servo to the given angle like Move, asking it to move at degsPerSec rather
+// than as fast as it can, e.g. for a smooth camera pan. Servos that do not support a speed ignore it and
+// move the same way as Move. A degsPerSec of 0 leaves the speed up to the servo.
+func MoveWithSpeed(ctx context.Context, s Servo, angleDeg uint32, degsPerSec float64, extra map[string]interface{}) error {
+	if degsPerSec < 0 {
+		return errors.Errorf("servo speed must not be negative, got %v degs/sec", degsPerSec)
+	}
+	out := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		out[k] = v
+	}
+	if degsPerSec != 0 {
+		out[speedKey] = degsPerSec
+	}
+	return s.Move(ctx, angleDeg, out)
+}
+
+// SpeedFromExtra returns the speed a caller of MoveWithSpeed asked for, and whether one was given.
+// Servo implementations that can limit their speed should use this in Move.
+func SpeedFromExtra(extra map[string]interface{}) (float64, bool) {
+	degsPerSec, ok := extra[speedKey].(float64)
+	return degsPerSec, ok && degsPerSec > 0
+}
@@ -168,6 +168,16 @@ func (b *myBase) Geometries(ctx context.Context, extra map[string]interface{}) (
 	return b.geometries, nil
 }
 
+// SetAccelerationLimits is unimplemented; a base that ramps its motors would store the limits here.
+func (b *myBase) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	return base.ErrAccelerationLimitsUnimplemented
+}
+
+// GetAccelerationLimits is unimplemented.
+func (b *myBase) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	return 0, 0, base.ErrAccelerationLimitsUnimplemented
+}
+
 // Close stops motion during shutdown.
 func (b *myBase) Close(ctx context.Context) error {
 	return b.Stop(ctx, nil)
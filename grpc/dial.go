@@ -6,11 +6,27 @@ import (
 	"time"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.viam.com/utils/rpc"
 )
 
-// Dial dials a gRPC server.
+// DefaultDialTimeout is how long Dial waits for a connection to be established.
+const DefaultDialTimeout = 20 * time.Second
+
+// Dial dials a gRPC server, giving up after DefaultDialTimeout.
 func Dial(ctx context.Context, address string, logger golog.Logger, opts ...rpc.DialOption) (rpc.ClientConn, error) {
+	return DialWithTimeout(ctx, address, DefaultDialTimeout, logger, opts...)
+}
+
+// DialWithTimeout dials a gRPC server, giving up if a connection is not established within the
+// given timeout. The timeout only bounds dialing and does not apply to the returned connection.
+func DialWithTimeout(
+	ctx context.Context,
+	address string,
+	timeout time.Duration,
+	logger golog.Logger,
+	opts ...rpc.DialOption,
+) (rpc.ClientConn, error) {
 	webrtcOpts := rpc.DialWebRTCOptions{
 		Config: &DefaultWebRTCConfiguration,
 	}
@@ -26,10 +42,14 @@ func Dial(ctx context.Context, address string, logger golog.Logger, opts ...rpc.
 	optsCopy[1] = rpc.WithAllowInsecureDowngrade()
 	copy(optsCopy[2:], opts)
 
-	ctx, timeoutCancel := context.WithTimeout(ctx, 20*time.Second)
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, timeout)
 	defer timeoutCancel()
 
-	return rpc.Dial(ctx, address, logger, optsCopy...)
+	conn, err := rpc.Dial(timeoutCtx, address, logger, optsCopy...)
+	if err != nil && ctx.Err() == nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) {
+		return nil, errors.Wrapf(err, "failed to dial %q within %s", address, timeout)
+	}
+	return conn, err
 }
 
 // InferSignalingServerAddress returns the appropriate WebRTC signaling server address
@@ -1,9 +1,15 @@
 package grpc
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/edaniels/golog"
 	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/testutils"
 )
 
 func TestInferSignalingServerAddress(t *testing.T) {
@@ -28,3 +34,22 @@ func TestInferSignalingServerAddress(t *testing.T) {
 		test.That(t, secure, test.ShouldEqual, input.isSecure)
 	}
 }
+
+func TestDialWithTimeout(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	listener := testutils.NewSilentListener(t)
+
+	start := time.Now()
+	_, err := DialWithTimeout(context.Background(), listener.Addr().String(), 200*time.Millisecond, logger, rpc.WithInsecure())
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "within 200ms")
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 5*time.Second)
+
+	// a canceled context is reported as such rather than as a timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = DialWithTimeout(ctx, listener.Addr().String(), time.Second, logger, rpc.WithInsecure())
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldNotContainSubstring, "within")
+}
@@ -1,8 +1,14 @@
 package protoutils
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/spatialmath"
@@ -19,7 +25,39 @@ const (
 	typeAxisAngle                = "r4aa"
 )
 
-func goToProto(v interface{}) (*structpb.Value, error) {
+// ErrUnsupportedReadingType matches, with errors.Is, the error ReadingGoToProto returns for a reading of
+// a type that cannot be converted to a proto value. Use errors.As with an *UnsupportedReadingTypeError to
+// find out which reading it was.
+var ErrUnsupportedReadingType = errors.New("unsupported reading type")
+
+// UnsupportedReadingTypeError is returned by ReadingGoToProto for a reading that cannot be converted to
+// a proto value, which is a bug in the driver that returned it. It is an Internal gRPC status, so servers
+// can return it as is.
+type UnsupportedReadingTypeError struct {
+	// Path is where the reading is, such as imu.raw[0] for the first element of the raw field of the imu
+	// reading.
+	Path string
+	// Type is the Go type of the reading.
+	Type reflect.Type
+}
+
+func (e *UnsupportedReadingTypeError) Error() string {
+	return fmt.Sprintf("reading %q has unsupported type %v", e.Path, e.Type)
+}
+
+// Is reports whether target is ErrUnsupportedReadingType.
+func (e *UnsupportedReadingTypeError) Is(target error) bool {
+	return target == ErrUnsupportedReadingType
+}
+
+// GRPCStatus returns the error as an Internal gRPC status.
+func (e *UnsupportedReadingTypeError) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, e.Error())
+}
+
+// goToProto converts the reading at path to a proto value. Maps and slices are converted recursively,
+// so that the spatialmath types and other maps and slices may be nested inside of them.
+func goToProto(path string, v interface{}) (*structpb.Value, error) {
 	switch x := v.(type) {
 	case spatialmath.AngularVelocity:
 		v = map[string]interface{}{
@@ -91,7 +129,52 @@ func goToProto(v interface{}) (*structpb.Value, error) {
 		}
 	}
 
-	return structpb.NewValue(v)
+	switch x := v.(type) {
+	case map[string]interface{}:
+		fields := make(map[string]*structpb.Value, len(x))
+		for k, elem := range x {
+			pv, err := goToProto(path+"."+k, elem)
+			if err != nil {
+				return nil, err
+			}
+			fields[k] = pv
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: fields}), nil
+	case []interface{}:
+		values := make([]*structpb.Value, 0, len(x))
+		for i, elem := range x {
+			pv, err := goToProto(fmt.Sprintf("%s[%d]", path, i), elem)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, pv)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values}), nil
+	}
+	if pv, err := structpb.NewValue(v); err == nil {
+		return pv, nil
+	}
+
+	// typed maps and slices, such as map[string]float64 or []r3.Vector, are converted like their
+	// interface{} counterparts.
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		m := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return goToProto(path, m)
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		l := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			l = append(l, rv.Index(i).Interface())
+		}
+		return goToProto(path, l)
+	default:
+		return nil, &UnsupportedReadingTypeError{Path: path, Type: reflect.TypeOf(v)}
+	}
 }
 
 // ReadingGoToProto converts go readings to proto readings.
@@ -99,7 +182,7 @@ func ReadingGoToProto(readings map[string]interface{}) (map[string]*structpb.Val
 	m := map[string]*structpb.Value{}
 
 	for k, v := range readings {
-		vv, err := goToProto(v)
+		vv, err := goToProto(k, v)
 		if err != nil {
 			return nil, err
 		}
@@ -174,8 +257,16 @@ func cleanSensorType(v interface{}) interface{} {
 				x["lng"].(float64),
 			)
 		default:
-			return v
+			for k, elem := range x {
+				x[k] = cleanSensorType(elem)
+			}
+			return x
+		}
+	case []interface{}:
+		for i, elem := range x {
+			x[i] = cleanSensorType(elem)
 		}
+		return x
 	default:
 		return v
 	}
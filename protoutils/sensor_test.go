@@ -31,3 +31,47 @@ func TestRoundtrip(t *testing.T) {
 
 	test.That(t, m2, test.ShouldResemble, m1)
 }
+
+func TestNestedReadings(t *testing.T) {
+	readings := map[string]interface{}{
+		"imu": map[string]interface{}{
+			"acceleration": r3.Vector{1, 2, 3},
+			"samples":      []float64{0.5, 1.5},
+			"flags":        map[string]bool{"calibrated": true},
+		},
+		"tracks": []map[string]interface{}{
+			{"id": 1, "position": geo.NewPoint(12, 13)},
+			{"id": 2, "history": [][]int{{1, 2}, {3}}},
+		},
+	}
+
+	p, err := ReadingGoToProto(readings)
+	test.That(t, err, test.ShouldBeNil)
+	imu := p["imu"].GetStructValue().GetFields()
+	test.That(t, imu["samples"].GetListValue().GetValues()[1].GetNumberValue(), test.ShouldEqual, 1.5)
+	test.That(t, imu["flags"].GetStructValue().GetFields()["calibrated"].GetBoolValue(), test.ShouldBeTrue)
+	history := p["tracks"].GetListValue().GetValues()[1].GetStructValue().GetFields()["history"]
+	test.That(t, history.GetListValue().GetValues()[0].GetListValue().GetValues()[1].GetNumberValue(), test.ShouldEqual, 2)
+
+	// nested spatial types come back as themselves, and everything else keeps its JSON types
+	m, err := ReadingProtoToGo(p)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m, test.ShouldResemble, map[string]interface{}{
+		"imu": map[string]interface{}{
+			"acceleration": r3.Vector{1, 2, 3},
+			"samples":      []interface{}{0.5, 1.5},
+			"flags":        map[string]interface{}{"calibrated": true},
+		},
+		"tracks": []interface{}{
+			map[string]interface{}{"id": 1.0, "position": geo.NewPoint(12, 13)},
+			map[string]interface{}{"id": 2.0, "history": []interface{}{[]interface{}{1.0, 2.0}, []interface{}{3.0}}},
+		},
+	})
+
+	type calibration struct{ Offset float64 }
+	_, err = ReadingGoToProto(map[string]interface{}{
+		"imu": map[string]interface{}{"calibrations": []interface{}{1.0, calibration{Offset: 2}}},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldEqual, `reading "imu.calibrations[1]" has unsupported type protoutils.calibration`)
+}
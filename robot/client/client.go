@@ -112,6 +112,7 @@ type RobotClient struct {
 	remoteName  string
 	address     string
 	dialOptions []rpc.DialOption
+	dialTimeout time.Duration
 
 	mu              sync.RWMutex
 	resourceNames   []resource.Name
@@ -268,6 +269,7 @@ func New(ctx context.Context, address string, logger golog.Logger, opts ...Robot
 		backgroundCtxCancel: backgroundCtxCancel,
 		logger:              logger,
 		dialOptions:         rOpts.dialOptions,
+		dialTimeout:         grpc.DefaultDialTimeout,
 		notifyParent:        nil,
 		resourceClients:     make(map[resource.Name]resource.Resource),
 		remoteNameMap:       make(map[resource.Name]resource.Name),
@@ -276,6 +278,10 @@ func New(ctx context.Context, address string, logger golog.Logger, opts ...Robot
 		heartbeatCtxCancel:  heartbeatCtxCancel,
 	}
 
+	if rOpts.dialTimeout != nil {
+		rc.dialTimeout = *rOpts.dialTimeout
+	}
+
 	// interceptors are applied in order from first to last
 	rc.dialOptions = append(
 		rc.dialOptions,
@@ -375,7 +381,7 @@ func (rc *RobotClient) connect(ctx context.Context) error {
 	if err := rc.conn.Close(); err != nil {
 		return err
 	}
-	conn, err := grpc.Dial(ctx, rc.address, rc.logger, rc.dialOptions...)
+	conn, err := grpc.DialWithTimeout(ctx, rc.address, rc.dialTimeout, rc.logger, rc.dialOptions...)
 	if err != nil {
 		return err
 	}
@@ -24,6 +24,10 @@ type robotClientOpts struct {
 	// it will automatically refresh every 1s
 	reconnectEvery *time.Duration
 
+	// dialTimeout is how long to wait for a connection to the robot
+	// to be established. If unset, grpc.DefaultDialTimeout is used.
+	dialTimeout *time.Duration
+
 	// dialOptions are options using for clients dialing gRPC servers.
 	dialOptions []rpc.DialOption
 
@@ -78,6 +82,14 @@ func WithReconnectEvery(reconnectEvery time.Duration) RobotClientOption {
 	})
 }
 
+// WithDialTimeout returns a RobotClientOption for how long to wait for a connection to the robot
+// to be established, both initially and when reconnecting.
+func WithDialTimeout(dialTimeout time.Duration) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.dialTimeout = &dialTimeout
+	})
+}
+
 // WithRemoteName returns a RobotClientOption setting the name of the remote robot.
 func WithRemoteName(remoteName string) RobotClientOption {
 	return newFuncRobotClientOption(func(o *robotClientOpts) {
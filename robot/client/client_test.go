@@ -1061,6 +1061,19 @@ func TestClientDialerOption(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 }
 
+func TestClientDialTimeout(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	listener := testutils.NewSilentListener(t)
+
+	start := time.Now()
+	_, err := New(context.Background(), listener.Addr().String(), logger, WithDialTimeout(200*time.Millisecond))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "failed to dial")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "within 200ms")
+	test.That(t, time.Since(start), test.ShouldBeLessThan, 5*time.Second)
+}
+
 func TestClientResources(t *testing.T) {
 	injectRobot := &inject.Robot{}
 
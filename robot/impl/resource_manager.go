@@ -785,6 +785,12 @@ func (manager *resourceManager) processResource(
 	}
 
 	manager.logger.Debugw("rebuilding", "name", resName)
+	// stop anything that moves before closing it so that it does not keep moving while it is being replaced.
+	if actuator, ok := currentRes.(resource.Actuator); ok {
+		if err := actuator.Stop(ctx, nil); err != nil {
+			manager.logger.Errorw("error stopping resource before rebuilding", "name", resName, "error", err)
+		}
+	}
 	if err := r.manager.closeResource(ctx, currentRes); err != nil {
 		manager.logger.Error(err)
 	}
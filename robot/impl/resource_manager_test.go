@@ -1655,6 +1655,63 @@ func TestReconfigure(t *testing.T) {
 	}()
 }
 
+func TestRebuildStopsActuator(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ctx := context.Background()
+
+	r, err := New(ctx, &config.Config{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	manager := managerForDummyRobot(r)
+	defer func() {
+		test.That(t, manager.Close(ctx), test.ShouldBeNil)
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+
+	model := resource.DefaultModelFamily.WithModel("test-rebuilt")
+	resource.RegisterComponent(base.API, model, resource.Registration[base.Base, resource.NoNativeConfig]{
+		Constructor: func(ctx context.Context, deps resource.Dependencies, c resource.Config, logger golog.Logger) (base.Base, error) {
+			return inject.NewBase(c.Name), nil
+		},
+	})
+	defer func() {
+		resource.Deregister(base.API, model)
+	}()
+
+	for _, tc := range []struct {
+		name    string
+		stopErr error
+	}{
+		{"stops before closing", nil},
+		{"stop error does not fail rebuild", errors.New("cannot stop")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			oldBase := inject.NewBase("base1")
+			oldBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+				calls = append(calls, "stop")
+				return tc.stopErr
+			}
+			oldBase.CloseFunc = func(ctx context.Context) error {
+				calls = append(calls, "close")
+				return nil
+			}
+
+			// changing the model of a resource requires it to be rebuilt.
+			conf := resource.Config{Name: "base1", API: base.API, Model: model}
+			node := resource.NewConfiguredGraphNode(conf, oldBase, resource.DefaultModelFamily.WithModel("old"))
+
+			local, ok := r.(*localRobot)
+			test.That(t, ok, test.ShouldBeTrue)
+			newRes, newlyBuilt, err := manager.processResource(ctx, conf, node, local)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, newlyBuilt, test.ShouldBeTrue)
+			test.That(t, newRes, test.ShouldNotEqual, oldBase)
+			test.That(t, calls, test.ShouldResemble, []string{"stop", "close"})
+		})
+	}
+}
+
 func TestResourceCreationPanic(t *testing.T) {
 	logger := golog.NewTestLogger(t)
 	ctx := context.Background()
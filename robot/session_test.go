@@ -927,6 +927,14 @@ func (db *dummyBase) Geometries(ctx context.Context, extra map[string]interface{
 	return []spatialmath.Geometry{}, nil
 }
 
+func (db *dummyBase) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	return base.ErrAccelerationLimitsUnimplemented
+}
+
+func (db *dummyBase) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	return 0, 0, base.ErrAccelerationLimitsUnimplemented
+}
+
 // NewClientFromConn constructs a new client from connection passed in.
 func NewClientFromConn(
 	ctx context.Context,
@@ -24,6 +24,9 @@ type Base struct {
 	SetVelocityFunc  func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error
 	PropertiesFunc   func(ctx context.Context, extra map[string]interface{}) (base.Properties, error)
 	GeometriesFunc   func(ctx context.Context) ([]spatialmath.Geometry, error)
+
+	SetAccelerationLimitsFunc func(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error
+	GetAccelerationLimitsFunc func(ctx context.Context) (float64, float64, error)
 }
 
 // NewBase returns a new injected base.
@@ -118,3 +121,19 @@ func (b *Base) Geometries(ctx context.Context, extra map[string]interface{}) ([]
 	}
 	return b.GeometriesFunc(ctx)
 }
+
+// SetAccelerationLimits calls the injected SetAccelerationLimits or the real version.
+func (b *Base) SetAccelerationLimits(ctx context.Context, linearMillisPerSec2, angularDegsPerSec2 float64) error {
+	if b.SetAccelerationLimitsFunc == nil {
+		return b.Base.SetAccelerationLimits(ctx, linearMillisPerSec2, angularDegsPerSec2)
+	}
+	return b.SetAccelerationLimitsFunc(ctx, linearMillisPerSec2, angularDegsPerSec2)
+}
+
+// GetAccelerationLimits calls the injected GetAccelerationLimits or the real version.
+func (b *Base) GetAccelerationLimits(ctx context.Context) (float64, float64, error) {
+	if b.GetAccelerationLimitsFunc == nil {
+		return b.Base.GetAccelerationLimits(ctx)
+	}
+	return b.GetAccelerationLimitsFunc(ctx)
+}
@@ -2,6 +2,8 @@ package inject
 
 import (
 	"context"
+	"math"
+	"math/rand"
 	"sync"
 
 	"github.com/golang/geo/r3"
@@ -43,6 +45,26 @@ func NewMovementSensor(name string) *MovementSensor {
 	return &MovementSensor{name: movementsensor.Named(name)}
 }
 
+// NewNoisyFixedCompass returns an injected movement sensor whose compass heading is trueHeading plus
+// Gaussian noise with a standard deviation of stddevDeg degrees, wrapped to [0, 360). The noise comes
+// from a source seeded with seed, so the same arguments always produce the same sequence of headings.
+func NewNoisyFixedCompass(name string, trueHeading, stddevDeg float64, seed int64) *MovementSensor {
+	ms := NewMovementSensor(name)
+	var mu sync.Mutex
+	//nolint:gosec
+	noise := rand.New(rand.NewSource(seed))
+	ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		heading := math.Mod(trueHeading+noise.NormFloat64()*stddevDeg, 360)
+		if heading < 0 {
+			heading += 360
+		}
+		return heading, nil
+	}
+	return ms
+}
+
 // Name returns the name of the resource.
 func (i *MovementSensor) Name() resource.Name {
 	return i.name
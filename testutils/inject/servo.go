@@ -14,6 +14,7 @@ type Servo struct {
 	DoFunc       func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
 	MoveFunc     func(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error
 	PositionFunc func(ctx context.Context, extra map[string]interface{}) (uint32, error)
+	RangeFunc    func(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error)
 	StopFunc     func(ctx context.Context, extra map[string]interface{}) error
 	IsMovingFunc func(context.Context) (bool, error)
 }
@@ -44,6 +45,14 @@ func (s *Servo) Position(ctx context.Context, extra map[string]interface{}) (uin
 	return s.PositionFunc(ctx, extra)
 }
 
+// Range calls the injected Range or the real version.
+func (s *Servo) Range(ctx context.Context, extra map[string]interface{}) (uint32, uint32, error) {
+	if s.RangeFunc == nil {
+		return s.Servo.Range(ctx, extra)
+	}
+	return s.RangeFunc(ctx, extra)
+}
+
 // Stop calls the injected Stop or the real version.
 func (s *Servo) Stop(ctx context.Context, extra map[string]interface{}) error {
 	if s.StopFunc == nil {
@@ -3,7 +3,9 @@ package testutils
 
 import (
 	"context"
+	"net"
 	"sync"
+	"testing"
 
 	"go.viam.com/utils/rpc"
 	"google.golang.org/grpc"
@@ -82,3 +84,30 @@ func (s *ServerTransportStream) Value(key string) []string {
 	defer s.mu.Unlock()
 	return s.md[key]
 }
+
+// NewSilentListener returns a listener on a local port that accepts connections but never speaks gRPC,
+// so that dialing it can only time out. The listener and its connections are closed when the test ends.
+func NewSilentListener(tb testing.TB) net.Listener {
+	tb.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		//nolint:errcheck,gosec
+		listener.Close()
+	})
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tb.Cleanup(func() {
+				//nolint:errcheck,gosec
+				conn.Close()
+			})
+		}
+	}()
+	return listener
+}
@@ -27,6 +27,14 @@ func AngleDiffDeg(a1, a2 float64) float64 {
 	return float64(180) - math.Abs(math.Abs(a1-a2)-float64(180))
 }
 
+// SignedAngleDiffDeg returns the angle in degrees to turn from the from angle to the to
+// angle the shorter way around, in [-180, 180). Unlike AngleDiffDeg, the result is positive
+// when to is counterclockwise of from, and the arguments may be any angle, including negative
+// ones and ones of more than a full turn.
+func SignedAngleDiffDeg(from, to float64) float64 {
+	return math.Mod(math.Mod(to-from, 360)+540, 360) - 180
+}
+
 // AntiCWDeg flips the given degrees as if you were to start at 0 and
 // go counter-clockwise or vice versa.
 func AntiCWDeg(deg float64) float64 {
@@ -39,6 +47,21 @@ func ModAngDeg(ang float64) float64 {
 	return math.Mod(math.Mod((ang), 360)+360, 360)
 }
 
+// MeanAngleDeg returns the circular mean of the given angles in [0, 360), so that
+// the mean of 350 and 10 is 0 rather than 180. If there are no angles, or they
+// cancel each other out, NaN is returned.
+func MeanAngleDeg(angles ...float64) float64 {
+	var sumSin, sumCos float64
+	for _, ang := range angles {
+		sumSin += math.Sin(DegToRad(ang))
+		sumCos += math.Cos(DegToRad(ang))
+	}
+	if len(angles) == 0 || math.Hypot(sumSin, sumCos) < 1e-9*float64(len(angles)) {
+		return math.NaN()
+	}
+	return ModAngDeg(RadToDeg(math.Atan2(sumSin, sumCos)))
+}
+
 // Median returns the median value of the given values. If there
 // are no values, NaN is returned.
 func Median(values ...float64) float64 {
@@ -100,6 +100,31 @@ func TestAntiCWDeg(t *testing.T) {
 	test.That(t, AntiCWDeg(45), test.ShouldEqual, 315)
 }
 
+func TestSignedAngleDiffDeg(t *testing.T) {
+	test.That(t, SignedAngleDiffDeg(10, 30), test.ShouldEqual, 20)
+	test.That(t, SignedAngleDiffDeg(30, 10), test.ShouldEqual, -20)
+	test.That(t, SignedAngleDiffDeg(350, 10), test.ShouldEqual, 20)
+	test.That(t, SignedAngleDiffDeg(10, 350), test.ShouldEqual, -20)
+	test.That(t, SignedAngleDiffDeg(0, 180), test.ShouldEqual, -180)
+	test.That(t, SignedAngleDiffDeg(90, 90), test.ShouldEqual, 0)
+	// negative angles and ones of more than a full turn
+	test.That(t, SignedAngleDiffDeg(-10, 10), test.ShouldEqual, 20)
+	test.That(t, SignedAngleDiffDeg(-350, -10), test.ShouldEqual, -20)
+	test.That(t, SignedAngleDiffDeg(720+10, 30), test.ShouldEqual, 20)
+	test.That(t, SignedAngleDiffDeg(10, -1080+350), test.ShouldEqual, -20)
+	test.That(t, SignedAngleDiffDeg(-200, 500), test.ShouldEqual, -20)
+}
+
+func TestMeanAngleDeg(t *testing.T) {
+	test.That(t, MeanAngleDeg(10, 20, 30), test.ShouldAlmostEqual, 20)
+	test.That(t, MeanAngleDeg(350, 10), test.ShouldAlmostEqual, 0)
+	test.That(t, MeanAngleDeg(340, 350, 10, 20), test.ShouldAlmostEqual, 0)
+	test.That(t, MeanAngleDeg(170, 190), test.ShouldAlmostEqual, 180)
+	test.That(t, MeanAngleDeg(-90), test.ShouldAlmostEqual, 270)
+	test.That(t, math.IsNaN(MeanAngleDeg()), test.ShouldBeTrue)
+	test.That(t, math.IsNaN(MeanAngleDeg(0, 180)), test.ShouldBeTrue)
+}
+
 func TestModAngDeg(t *testing.T) {
 	test.That(t, ModAngDeg(0-180), test.ShouldEqual, 180)
 	test.That(t, ModAngDeg(360+40), test.ShouldEqual, 40)